@@ -41,7 +41,7 @@ func init() {
 	flag.BoolVar(&verbose, "v", false, "Enable verbose logging (shorthand)")
 	flag.BoolVar(&showVersion, "version", false, "Show version information")
 	flag.StringVar(&outputDir, "output", "", "Override output directory from config")
-	flag.StringVar(&formats, "format", "excel,html,word,json", "Comma-separated output formats (excel,html,word,json)")
+	flag.StringVar(&formats, "format", "excel,html,word,json", "Comma-separated output formats (excel,html,word,json,security)")
 }
 
 func main() {
@@ -125,6 +125,11 @@ func runAnalysis(cfg *config.Config) error {
 	scanBar.SetTotal(len(files))
 
 	pool := linker.NewComponentPool()
+	pool.PropertyNaming = cfg.Analysis.PropertyNaming
+
+	// MyBatis mappers are added once every file is scanned, since <include>
+	// may refer to a <sql> fragment declared in another mapper file
+	var mappers []*xmlparser.MapperXML
 
 	for _, path := range files {
 		content, err := analyzer.ReadFile(path)
@@ -134,26 +139,50 @@ func runAnalysis(cfg *config.Config) error {
 			continue
 		}
 
+		// Repository-relative path for source references in reports
+		relPath := path
+		if rel, err := filepath.Rel(cfg.Project.RootDir, path); err == nil {
+			relPath = filepath.ToSlash(rel)
+		}
+
 		if strings.HasSuffix(path, ".java") {
-			cls, err := javaparser.ParseJavaFile(content)
+			types, err := javaparser.ParseJavaTypes(relPath, content)
 			if err == nil {
-				pool.AddJavaClass(cls, content)
+				for _, cls := range types {
+					pool.AddJavaClass(cls, content)
+				}
 			}
 		} else if strings.HasSuffix(path, ".xml") {
-			mapper, err := xmlparser.ParseXMLFile(content)
-			if err == nil {
-				pool.AddMapperXML(mapper)
+			switch xmlparser.RootElement(content) {
+			case "beans":
+				ctx, err := xmlparser.ParseSpringXMLWithPath(relPath, content)
+				if err == nil {
+					pool.AddSpringContext(ctx)
+				}
+			case "sqlMap":
+				mapper, err := xmlparser.ParseSqlMapWithPath(relPath, content)
+				if err == nil {
+					mappers = append(mappers, mapper)
+				}
+			default:
+				mapper, err := xmlparser.ParseXMLFileWithPath(relPath, content)
+				if err == nil {
+					mappers = append(mappers, mapper)
+				}
 			}
 		}
 		scanBar.Increment()
 	}
+	mainLinker := linker.NewLinker(pool)
+	if err := mainLinker.LoadMapperXMLs(mappers); err != nil {
+		return err
+	}
 	scanBar.Finish()
 
 	// --- Phase 2: Linking ---
 	logger.Info("Phase 2: Linking Components...")
 	linkBar := pipeline.NextPhase(50) // Arbitrary steps for linking
 
-	mainLinker := linker.NewLinker(pool)
 	tree := mainLinker.BuildCallGraph()
 	linkBar.Finish()
 
@@ -2,7 +2,9 @@ package analyzer
 
 import (
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
 	"strings"
 
 	"spec-recon/internal/logger"
@@ -45,16 +47,8 @@ func ExtractEndpoints(nodes []*model.Node, classMap map[string]*model.Node, fiel
 				continue
 			}
 
-			// KEYWORD FILTER: Block Java keywords and control flow statements
-			// These should never appear as API endpoints
-			if isJavaKeyword(method.Method) {
-				fmt.Printf("[API SKIP] Blocked keyword as endpoint: %s\n", method.Method)
-				continue
-			}
-
-			// CONSTRUCTOR FILTER: Block "new" and constructor patterns
-			if strings.HasPrefix(method.Method, "new ") || method.Method == "new" {
-				fmt.Printf("[API SKIP] Blocked constructor as endpoint: %s\n", method.Method)
+			// @ExceptionHandler methods write error responses, they are not endpoints
+			if method.ExceptionHandler != nil {
 				continue
 			}
 
@@ -63,11 +57,16 @@ func ExtractEndpoints(nodes []*model.Node, classMap map[string]*model.Node, fiel
 			if endpoint != nil {
 				// Filter out View Controllers (web pages, not REST APIs)
 				if isViewEndpoint(endpoint, method) {
-					fmt.Printf("[API SKIP] Excluded View Endpoint: %s (Type: %s)\n", endpoint.Path, endpoint.Response.Type)
+					fmt.Printf("[API SKIP] Excluded View Endpoint: %s (Type: %s)\n", endpoint.Path, endpoint.SuccessResponse().Type)
 					continue
 				}
 
-				endpoints = append(endpoints, *endpoint)
+				// One endpoint per mapped path ({"/a", "/b"} maps both)
+				for _, path := range method.Paths() {
+					pathEndpoint := *endpoint
+					pathEndpoint.Path = path
+					endpoints = append(endpoints, pathEndpoint)
+				}
 			}
 		}
 	}
@@ -84,32 +83,60 @@ func extractEndpointFromMethod(controller *model.Node, method *model.Node, class
 	if endpoint.Method == "" {
 		endpoint.Method = "GET" // Default
 	}
-
-	// Extract path from URL
-	endpoint.Path = method.URL
-	if endpoint.Path == "" {
-		endpoint.Path = "/" + method.Method
+	if method.Mapping != nil {
+		endpoint.Methods = method.Mapping.Methods
+		endpoint.ParamConditions = method.Mapping.Params
+		endpoint.HeaderConditions = method.Mapping.Headers
+		endpoint.Consumes = method.Mapping.Consumes
+		endpoint.Produces = method.Mapping.Produces
+	} else {
+		endpoint.Methods = []string{endpoint.Method}
 	}
 
+	// Extract path from URL (the first one when the method maps several)
+	endpoint.Path = method.Paths()[0]
+
 	// Controller and method names
 	endpoint.ControllerName = extractSimpleName(controller.ID)
 	endpoint.MethodName = method.Method
 
-	// Extract summary and description from comments
-	endpoint.Summary = extractSummary(method.Comment)
-	endpoint.Description = method.Comment
+	// Source location of the handler
+	endpoint.File = method.File
+	endpoint.Line = method.Line
+
+	// Extract summary and description from JavaDoc
+	if method.Doc != nil {
+		endpoint.Summary = method.Doc.Summary
+		endpoint.Description = method.Doc.Description
+	} else {
+		endpoint.Summary = extractSummary(method.Comment)
+		endpoint.Description = method.Comment
+	}
 
 	// Extract parameters with schema resolution
 	endpoint.Params = extractParameters(method, classMap, fieldTypeMap)
+	endpoint.Params = append(endpoint.Params, conditionParams(endpoint.ParamConditions, "Query", endpoint.Params)...)
+	endpoint.Params = append(endpoint.Params, conditionParams(endpoint.HeaderConditions, "Header", endpoint.Params)...)
 
-	// Extract response with schema resolution
-	endpoint.Response = extractResponse(method, classMap, fieldTypeMap)
+	// Extract responses with schema resolution: the handler's own, then the
+	// error responses of the exceptions thrown along its call chain
+	endpoint.Responses = mergeResponses(
+		extractResponses(method, classMap, fieldTypeMap),
+		extractErrorResponses(controller, method, classMap, fieldTypeMap))
 
 	return endpoint
 }
 
-// extractHTTPMethod extracts the HTTP method from annotations
+// extractHTTPMethod extracts the HTTP method from annotations: the methods
+// of the request mapping joined with ", ", or ALL when it accepts any
 func extractHTTPMethod(method *model.Node) string {
+	if method.Mapping != nil {
+		if len(method.Mapping.Methods) == 0 {
+			return "ALL"
+		}
+		return strings.Join(method.Mapping.Methods, ", ")
+	}
+
 	// Check the Annotation field which stores HTTP method
 	if method.Annotation != "" {
 		return strings.ToUpper(method.Annotation)
@@ -133,6 +160,41 @@ func extractHTTPMethod(method *model.Node) string {
 	return "GET" // Default
 }
 
+// conditionParams documents the parameters (or headers) a mapping condition
+// requires ("action=save", "X-API-VERSION", "version!=1") that the handler
+// does not declare; negated conditions ("!debug") require nothing
+func conditionParams(conditions []string, in string, declared []model.ParamDef) []model.ParamDef {
+	var params []model.ParamDef
+	for _, condition := range conditions {
+		if strings.HasPrefix(condition, "!") {
+			continue
+		}
+		name, _, _ := strings.Cut(condition, "=")
+		name = strings.TrimSpace(strings.TrimSuffix(name, "!"))
+		if hasParam(declared, name, in) || hasParam(params, name, in) {
+			continue
+		}
+		params = append(params, model.ParamDef{
+			Name:        name,
+			Type:        "String",
+			In:          in,
+			Required:    true,
+			Description: "Mapping condition: " + condition,
+		})
+	}
+	return params
+}
+
+// hasParam reports whether params include one with the given name and location
+func hasParam(params []model.ParamDef, name, in string) bool {
+	for _, param := range params {
+		if param.Name == name && param.In == in {
+			return true
+		}
+	}
+	return false
+}
+
 // extractParameters extracts parameter definitions from method signature
 func extractParameters(method *model.Node, classMap map[string]*model.Node, fieldTypeMap map[string]map[string]string) []model.ParamDef {
 	var params []model.ParamDef
@@ -141,8 +203,9 @@ func extractParameters(method *model.Node, classMap map[string]*model.Node, fiel
 		return params
 	}
 
-	// Parse parameter string: "String userId, User user"
-	paramParts := strings.Split(method.Params, ",")
+	// Parse parameter string: "String userId, @RequestParam(value = "q", required = false) String q"
+	paramParts := ParseMethodParams(method.Params)
+	mapDocumented := false
 	for _, part := range paramParts {
 		part = strings.TrimSpace(part)
 		if part == "" {
@@ -150,35 +213,104 @@ func extractParameters(method *model.Node, classMap map[string]*model.Node, fiel
 		}
 
 		param := parseParameter(part, classMap, fieldTypeMap)
+		if param != nil && isDynamicType(param.Type) && !mapDocumented {
+			// A Map handed down to a mapper takes the statement's parameterType
+			if fields := statementParameterSchema(method, classMap, fieldTypeMap, make(map[*model.Node]bool)); len(fields) > 0 {
+				param.Fields = fields
+				mapDocumented = true
+			}
+		}
 		if param != nil {
+			// @param documentation beats the generic location-based description
+			if desc := method.Doc.ParamDescription(param.Name); desc != "" {
+				param.Description = desc
+			}
+			// Constraints on the parameter itself, and on the fields of a
+			// @Valid/@Validated argument for the groups it is validated with
+			annotations := method.ParamAnnotations[param.Name]
+			applyConstraints(param, annotations, []string{defaultGroup})
+			if groups := validationGroups(annotations); groups != nil {
+				applyFieldConstraints(param.Fields, groups)
+			}
 			params = append(params, *param)
 		}
 	}
 
+	// Keys read from an undeclared Map or request are guessed from the SQL
+	if !mapDocumented {
+		for i := range params {
+			if !isDynamicType(params[i].Type) && !isServletRequestType(params[i].Type) {
+				continue
+			}
+			if params[i].In == "Body" && isDynamicType(params[i].Type) {
+				if fields := placeholderParams(method, "Body", 1); len(fields) > 0 {
+					params[i].Fields = fields // Replaces the "(Dynamic)" marker
+				}
+			} else {
+				params = append(params, undeclaredParams(placeholderParams(method, "Query", 0), params)...)
+			}
+			break
+		}
+	}
+
 	return params
 }
 
+// isServletRequestType reports whether a handler parameter gives access to
+// the raw request parameters
+func isServletRequestType(typeName string) bool {
+	switch simpleName(typeName) {
+	case "HttpServletRequest", "ServletRequest", "MultipartHttpServletRequest", "WebRequest", "NativeWebRequest":
+		return true
+	}
+	return false
+}
+
+// undeclaredParams drops the inferred parameters the handler already declares
+func undeclaredParams(inferred, declared []model.ParamDef) []model.ParamDef {
+	names := make(map[string]bool)
+	for _, param := range declared {
+		names[param.Name] = true
+	}
+	var result []model.ParamDef
+	for _, param := range inferred {
+		if !names[param.Name] {
+			result = append(result, param)
+		}
+	}
+	return result
+}
+
+// optionalBindingRegex matches a binding annotation that does not require its
+// parameter: @RequestParam(required = false), @RequestParam(defaultValue = "1")
+var optionalBindingRegex = regexp.MustCompile(`required\s*=\s*false|defaultvalue\s*=`)
+
 // parseParameter parses a single parameter string
 func parseParameter(paramStr string, classMap map[string]*model.Node, fieldTypeMap map[string]map[string]string) *model.ParamDef {
 	// Pattern: "Type name" or "@Annotation Type name"
-	parts := strings.Fields(paramStr)
+	parts := splitParameterTokens(paramStr)
 	if len(parts) < 2 {
 		return nil
 	}
 
-	param := &model.ParamDef{
-		Required: true, // Default to required
-	}
+	// Binding annotations require their parameter unless they say otherwise
+	// (required = false, a defaultValue); parameters bound without one are optional
+	param := &model.ParamDef{}
 
 	// Check for annotations
+	// Only a @RequestBody is read by Jackson; other objects (@ModelAttribute,
+	// or none) are bound from query and form parameters by property name
 	startIdx := 0
+	requestBody := false
 	for i, part := range parts {
 		if strings.HasPrefix(part, "@") {
 			// Determine parameter location from annotation
 			annotation := strings.ToLower(part)
+			bound := true
 			if strings.Contains(annotation, "requestbody") {
 				param.In = "Body"
 				param.Description = "Request body"
+				requestBody = true
 			} else if strings.Contains(annotation, "pathvariable") {
 				param.In = "Path"
 				param.Description = "Path variable"
@@ -188,6 +320,11 @@ func parseParameter(paramStr string, classMap map[string]*model.Node, fieldTypeM
 			} else if strings.Contains(annotation, "requestheader") {
 				param.In = "Header"
 				param.Description = "Header parameter"
+			} else {
+				bound = false
+			}
+			if bound {
+				param.Required = !optionalBindingRegex.MatchString(annotation)
 			}
 			startIdx = i + 1
 		}
@@ -204,6 +341,10 @@ func parseParameter(paramStr string, classMap map[string]*model.Node, fieldTypeM
 		param.Name = "param"
 	}
 
+	if strings.HasPrefix(param.Type, "Optional<") {
+		param.Required = false
+	}
+
 	// Default to Query if not specified
 	if param.In == "" {
 		// Heuristic: complex types are usually Body, primitives are Query
@@ -232,12 +373,56 @@ func parseParameter(paramStr string, classMap map[string]*model.Node, fieldTypeM
 
 	// Resolve nested schema for complex types
 	if isComplexType(param.Type) {
-		param.Fields = resolveSchema(param.Type, classMap, fieldTypeMap)
+		if requestBody {
+			param.Fields = resolveSchema(param.Type, classMap, fieldTypeMap)
+		} else {
+			param.Fields = resolveBeanSchema(param.Type, classMap, fieldTypeMap)
+		}
 	}
 
 	return param
 }
 
+// splitParameterTokens splits a parameter declaration into whitespace-separated
+// parts, keeping each annotation (including its arguments) and each generic type
+// as a single part and dropping the final modifier
+// Example: "@RequestParam(value = "q") final Map<String, Object> q" -> ["@RequestParam(value = "q")", "Map<String, Object>", "q"]
+func splitParameterTokens(paramStr string) []string {
+	var parts []string
+	var current strings.Builder
+	depth := 0
+
+	flush := func() {
+		if current.Len() > 0 {
+			if part := current.String(); part != "final" {
+				parts = append(parts, part)
+			}
+			current.Reset()
+		}
+	}
+
+	for _, char := range strings.TrimSpace(paramStr) {
+		switch {
+		case char == '(' || char == '<':
+			depth++
+		case char == ')' || char == '>':
+			depth--
+		case (char == ' ' || char == '\t' || char == '\n' || char == '\r') && depth == 0:
+			flush()
+			continue
+		}
+		// "@Ann (x)" and "Map <K, V>": attach the opening bracket to the previous part
+		if (char == '(' || char == '<') && current.Len() == 0 && len(parts) > 0 {
+			current.WriteString(parts[len(parts)-1])
+			parts = parts[:len(parts)-1]
+		}
+		current.WriteRune(char)
+	}
+	flush()
+
+	return parts
+}
+
 // extractResponse extracts response definition from method
 func extractResponse(method *model.Node, classMap map[string]*model.Node, fieldTypeMap map[string]map[string]string) model.ResponseDef {
 	response := model.ResponseDef{
@@ -284,6 +469,11 @@ func extractResponse(method *model.Node, classMap map[string]*model.Node, fieldT
 		response.StatusCode = 204
 	}
 
+	// @return documentation beats the generic type-based description
+	if method.Doc != nil && method.Doc.Return != "" {
+		response.Description = method.Doc.Return
+	}
+
 	// Resolve nested schema for complex response types
 	// ONLY if fields haven't already been inferred (e.g. by Map inference or Service Hop)
 	if isComplexType(response.Type) && len(response.Fields) == 0 {
@@ -369,7 +559,13 @@ func cleanAnnotation(annotation string) string {
 // isViewEndpoint checks if an endpoint is a view controller (web page) rather than a REST API
 // View controllers return HTML pages and should not be included in API documentation
 func isViewEndpoint(endpoint *model.EndpointDef, method *model.Node) bool {
-	returnType := endpoint.Response.Type
+	// Handlers mapped in a Spring XML context (Controller beans) return a
+	// ModelAndView by contract; they are the endpoints of legacy applications
+	if method.XMLMapped {
+		return false
+	}
+
+	returnType := endpoint.SuccessResponse().Type
 
 	// Check for explicit view return types
 	viewTypes := []string{
@@ -403,73 +599,6 @@ func isViewEndpoint(endpoint *model.EndpointDef, method *model.Node) bool {
 	return false
 }
 
-// isJavaKeyword checks if a name is a Java keyword or control flow statement
-// These should never appear as API endpoint method names
-func isJavaKeyword(name string) bool {
-	if name == "" {
-		return false
-	}
-
-	// Normalize to lowercase
-	lower := strings.ToLower(name)
-
-	// Comprehensive blocklist of Java keywords
-	keywords := map[string]bool{
-		"if":           true,
-		"else":         true,
-		"for":          true,
-		"while":        true,
-		"do":           true,
-		"switch":       true,
-		"case":         true,
-		"default":      true,
-		"return":       true,
-		"throw":        true,
-		"throws":       true,
-		"new":          true,
-		"try":          true,
-		"catch":        true,
-		"finally":      true,
-		"synchronized": true,
-		"assert":       true,
-		"break":        true,
-		"continue":     true,
-		"goto":         true,
-		"instanceof":   true,
-		"this":         true,
-		"super":        true,
-		"null":         true,
-		"true":         true,
-		"false":        true,
-		"void":         true,
-		"class":        true,
-		"interface":    true,
-		"enum":         true,
-		"extends":      true,
-		"implements":   true,
-		"import":       true,
-		"package":      true,
-		"private":      true,
-		"protected":    true,
-		"public":       true,
-		"static":       true,
-		"final":        true,
-		"abstract":     true,
-		"native":       true,
-		"strictfp":     true,
-		"transient":    true,
-		"volatile":     true,
-
-		// Spring/View types that should not be method names
-		"modelandview": true, // lowercase for case-insensitive match
-		"model":        true,
-		"view":         true,
-		"redirectview": true,
-	}
-
-	return keywords[lower]
-}
-
 // cleanTypeName removes generic wrappers and array notation to get the core type
 // Examples:
 //
@@ -561,15 +690,25 @@ func isSystemType(name string) bool {
 
 // resolveSchema resolves the schema (fields) of a complex type RECURSIVELY
 // It returns a flattened list of all fields with proper depth tracking for nested structures
+// Fields are named and filtered as Jackson serializes them (request and response bodies)
 func resolveSchema(typeName string, classMap map[string]*model.Node, fieldTypeMap map[string]map[string]string) []model.ParamDef {
-	fields := resolveSchemaRecursive(typeName, classMap, fieldTypeMap, 1, make(map[string]bool))
+	fields := resolveSchemaRecursive(typeName, classMap, fieldTypeMap, 1, make(map[string]bool), true)
+	return deduplicateFields(fields)
+}
+
+// resolveBeanSchema resolves the schema of a type by its Java property names,
+// for objects bound from query and form parameters or handed to SQL
+// statements, which Jackson annotations and naming strategies do not affect
+func resolveBeanSchema(typeName string, classMap map[string]*model.Node, fieldTypeMap map[string]map[string]string) []model.ParamDef {
+	fields := resolveSchemaRecursive(typeName, classMap, fieldTypeMap, 1, make(map[string]bool), false)
 	return deduplicateFields(fields)
 }
 
 // resolveSchemaRecursive is the recursive implementation of schema resolution
 // depth: current nesting level (0=root, 1=child, 2=grandchild, etc.)
 // visited: tracks visited types to prevent infinite recursion
-func resolveSchemaRecursive(typeName string, classMap map[string]*model.Node, fieldTypeMap map[string]map[string]string, depth int, visited map[string]bool) []model.ParamDef {
+// jackson: apply Jackson property names, ignores and formats (JSON bodies)
+func resolveSchemaRecursive(typeName string, classMap map[string]*model.Node, fieldTypeMap map[string]map[string]string, depth int, visited map[string]bool, jackson bool) []model.ParamDef {
 	var results []model.ParamDef
 
 	// BASE CASE 1: Max depth reached (prevent infinite loops)
@@ -586,7 +725,7 @@ func resolveSchemaRecursive(typeName string, classMap map[string]*model.Node, fi
 			fmt.Printf("[RECURSIVE] Unwrapping Collection: '%s' -> '%s' at depth %d\n", typeName, inner, depth)
 			// Recurse on the inner type using the SAME depth
 			// (because the List wrapper itself is not a separate level in terms of data fields)
-			return resolveSchemaRecursive(inner, classMap, fieldTypeMap, depth, visited)
+			return resolveSchemaRecursive(inner, classMap, fieldTypeMap, depth, visited, jackson)
 		}
 	}
 
@@ -665,22 +804,64 @@ func resolveSchemaRecursive(typeName string, classMap map[string]*model.Node, fi
 	}
 
 	// STEP 8: Build ParamDef list from fields WITH RECURSION
-	if fieldTypes != nil {
-		for fieldName, fieldType := range fieldTypes {
+	// Fields inherited from superclasses (AbstractDTO, BaseEntity, ...) are part of the schema;
+	// a field redeclared in a subclass hides the inherited one
+	type declaredField struct {
+		name, fieldType string
+		owner           *model.Node
+	}
+	var declared []declaredField
+	seen := make(map[string]bool)
+	for _, fieldName := range orderedFields(node, fieldTypes) {
+		seen[fieldName] = true
+		declared = append(declared, declaredField{fieldName, fieldTypes[fieldName], node})
+	}
+	for _, ancestor := range superClassNodes(node, classMap) {
+		ancestorTypes := fieldTypeMap[ancestor.ID]
+		for _, fieldName := range orderedFields(ancestor, ancestorTypes) {
+			if !seen[fieldName] {
+				seen[fieldName] = true
+				declared = append(declared, declaredField{fieldName, ancestorTypes[fieldName], ancestor})
+			}
+		}
+	}
+
+	if len(declared) > 0 {
+		for _, f := range declared {
+			fieldName, fieldType := f.name, f.fieldType
+			// Jackson decides the JSON property name, and may skip the field
+			field := f.owner.GetField(fieldName)
+			propertyName, serialized := fieldName, true
+			if jackson {
+				propertyName, serialized = jsonProperty(field, fieldName, node, f.owner)
+			}
+			if !serialized {
+				continue
+			}
+
 			// Create the parent field
 			paramDef := model.ParamDef{
-				Name:        fieldName,
+				Name:        propertyName,
 				Type:        fieldType,
 				Depth:       depth,
 				Description: fmt.Sprintf("Field of %s", cleanType),
 			}
+			if field != nil {
+				if field.Comment != "" {
+					paramDef.Description = field.Comment
+				}
+				paramDef.Annotations = field.Annotations
+			}
+			if jackson {
+				applyJSONFormat(&paramDef, field, node)
+			}
 
 			// Add parent field to results
 			results = append(results, paramDef)
 
 			// RECURSION: If the field type is complex, resolve its children
 			if isComplexType(fieldType) {
-				childFields := resolveSchemaRecursive(fieldType, classMap, fieldTypeMap, depth+1, visited)
+				childFields := resolveSchemaRecursive(fieldType, classMap, fieldTypeMap, depth+1, visited, jackson)
 				// Append child fields immediately after parent
 				results = append(results, childFields...)
 			}
@@ -691,6 +872,40 @@ func resolveSchemaRecursive(typeName string, classMap map[string]*model.Node, fi
 	return results
 }
 
+// orderedFields returns the field names of a class in declaration order
+// (Node.Fields); names only the field type map knows follow, sorted
+func orderedFields(node *model.Node, fieldTypes map[string]string) []string {
+	var names []string
+	for _, field := range node.Fields {
+		if _, ok := fieldTypes[field.Name]; ok && !slices.Contains(names, field.Name) {
+			names = append(names, field.Name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(fieldTypes)) {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// superClassNodes returns the class nodes of a node's superclass chain, nearest first
+// Superclasses outside the project (not in classMap) end the chain
+func superClassNodes(node *model.Node, classMap map[string]*model.Node) []*model.Node {
+	var chain []*model.Node
+	seen := map[string]bool{node.ID: true}
+	for current := node; current.SuperClass != "" && !seen[current.SuperClass]; {
+		parent := classMap[current.SuperClass]
+		if parent == nil {
+			break
+		}
+		seen[parent.ID] = true
+		chain = append(chain, parent)
+		current = parent
+	}
+	return chain
+}
+
 // isDynamicType checks if a type represents a dynamic/generic structure
 // These types have no fixed schema and should be documented as dynamic
 func isDynamicType(typeName string) bool {
@@ -850,7 +1065,8 @@ func inferReturnType(node *model.Node, classMap map[string]*model.Node) string {
 func inferMapSchema(node *model.Node, classMap map[string]*model.Node, fieldTypeMap map[string]map[string]string) []model.ParamDef {
 	var results []model.ParamDef
 	if node.Body == "" {
-		return results
+		// Mapper interface methods have no body, but may have a statement
+		return inferStatementSchema(node, classMap, fieldTypeMap, make(map[*model.Node]bool))
 	}
 
 	// Strategy 1: Local Map Inference
@@ -930,7 +1146,7 @@ func inferMapSchema(node *model.Node, classMap map[string]*model.Node, fieldType
 				results = append(results, param)
 
 				if isComplexType(valueType) {
-					childFields := resolveSchemaRecursive(valueType, classMap, fieldTypeMap, 2, make(map[string]bool))
+					childFields := resolveSchemaRecursive(valueType, classMap, fieldTypeMap, 2, make(map[string]bool), true)
 					results = append(results, childFields...)
 				}
 			}
@@ -942,6 +1158,12 @@ func inferMapSchema(node *model.Node, classMap map[string]*model.Node, fieldType
 		return deduplicateFields(results)
 	}
 
+	// Strategy 1.5: Mapper Result
+	// The returned call reaches a statement whose resultMap/resultType maps the columns
+	if statementFields := inferStatementSchema(node, classMap, fieldTypeMap, make(map[*model.Node]bool)); len(statementFields) > 0 {
+		return statementFields
+	}
+
 	// Strategy 2: Service Hop
 	// 1. Extract Return Statement for context
 	// Regex for return statement: "return <content> ;"
@@ -1088,7 +1310,7 @@ func inferMapSchema(node *model.Node, classMap map[string]*model.Node, fieldType
 				results = append(results, param)
 
 				if isComplexType(valueType) {
-					childFields := resolveSchemaRecursive(valueType, classMap, fieldTypeMap, 2, make(map[string]bool))
+					childFields := resolveSchemaRecursive(valueType, classMap, fieldTypeMap, 2, make(map[string]bool), true)
 					results = append(results, childFields...)
 				}
 			}
@@ -1150,8 +1372,14 @@ func inferMapSchema(node *model.Node, classMap map[string]*model.Node, fieldType
 }
 
 // resolveImplementationClass finds the concrete implementation class for a given interface or class name
-// It uses suffix matching (Impl) and fuzzy package matching to locate the correct node.
+// It prefers the type hierarchy resolved by the linker, then falls back to
+// suffix matching (Impl) and fuzzy package matching to locate the correct node.
 func resolveImplementationClass(classMap map[string]*model.Node, targetType string) *model.Node {
+	// Strategy 0: Declared implements/extends clauses
+	if node := findDeclaredImplementation(classMap, targetType); node != nil {
+		return node
+	}
+
 	// Strategy A: Exact Match
 	if node, ok := classMap[targetType]; ok {
 		// Even if exact match found, check if it's likely an interface (empty body or name doesn't end in Impl)
@@ -1189,6 +1417,32 @@ func resolveImplementationClass(classMap map[string]*model.Node, targetType stri
 	return candidate // Return whatever we found (or nil)
 }
 
+// findDeclaredImplementation returns a class that directly implements or extends targetType
+// (full or simple name). Services win over other classes; ties go to the smallest ID
+func findDeclaredImplementation(classMap map[string]*model.Node, targetType string) *model.Node {
+	var best *model.Node
+	for _, node := range classMap {
+		if node.Kind == "interface" || !declaresSuperType(node, targetType) {
+			continue
+		}
+		if best == nil ||
+			(node.IsService() && !best.IsService()) ||
+			(node.IsService() == best.IsService() && node.ID < best.ID) {
+			best = node
+		}
+	}
+	return best
+}
+
+func declaresSuperType(node *model.Node, targetType string) bool {
+	for _, superType := range append([]string{node.SuperClass}, node.Interfaces...) {
+		if superType != "" && (superType == targetType || strings.HasSuffix(superType, "."+targetType)) {
+			return true
+		}
+	}
+	return false
+}
+
 // isAmbiguousType checks if a type is too vague to be useful without body scanning
 // Returns true for: void, Object, Map, <Object>, <?>
 func isAmbiguousType(typeName string) bool {
@@ -1207,6 +1461,11 @@ func isAmbiguousType(typeName string) bool {
 		return true
 	}
 
+	// Check Collections of dynamic types, e.g. List<Map<String, Object>>
+	if isCollectionType(typeName) && isDynamicType(getInnerType(typeName)) {
+		return true
+	}
+
 	// Check Raw Collections (List, Set, Collection without <...>)
 	// If it matches a collection type but has NO generic brackets, it's a raw collection of Objects.
 	if isCollectionType(typeName) && !strings.Contains(typeName, "<") {
@@ -1228,16 +1487,26 @@ func applyTypeHeuristics(key, currentType string) string {
 	return currentType
 }
 
-// deduplicateFields ensures field uniqueness, prioritizing concrete types over Object
+// deduplicateFields ensures field uniqueness by path (address.city), prioritizing
+// concrete types over Object; nested fields of a dropped duplicate are dropped too
 func deduplicateFields(fields []model.ParamDef) []model.ParamDef {
 	if len(fields) == 0 {
 		return fields
 	}
 	var unique []model.ParamDef
-	seen := make(map[string]int) // Name -> Index in unique
+	seen := make(map[string]int) // Path (address.city) -> Index in unique
+	var path []string
+	skipDepth := 0 // Depth of a dropped duplicate, whose nested fields are dropped too
 
 	for _, f := range fields {
-		if idx, exists := seen[f.Name]; exists {
+		depth := max(f.Depth, 1)
+		if skipDepth > 0 && depth > skipDepth {
+			continue
+		}
+		skipDepth = 0
+		path = append(path[:min(depth-1, len(path))], f.Name)
+		key := strings.Join(path, ".")
+		if idx, exists := seen[key]; exists {
 			// Determine if we should overwrite
 			existing := unique[idx]
 			// If existing is "Object" or vague but new is Concrete -> Overwrite
@@ -1248,8 +1517,9 @@ func deduplicateFields(fields []model.ParamDef) []model.ParamDef {
 				unique[idx] = f
 			}
 			// Else ignore (keep existing) - First come, first served (usually Direct Map Scan comes before Blind Scan)
+			skipDepth = depth
 		} else {
-			seen[f.Name] = len(unique)
+			seen[key] = len(unique)
 			unique = append(unique, f)
 		}
 	}
@@ -1352,7 +1622,7 @@ func inferMapSchemaLegacy(node *model.Node, classMap map[string]*model.Node, fie
 
 		// Recursion: If it's a complex type, resolve its schema
 		if isComplexType(valueType) {
-			childFields := resolveSchemaRecursive(valueType, classMap, fieldTypeMap, 2, make(map[string]bool))
+			childFields := resolveSchemaRecursive(valueType, classMap, fieldTypeMap, 2, make(map[string]bool), true)
 			results = append(results, childFields...)
 		}
 	}
@@ -0,0 +1,76 @@
+package analyzer
+
+import (
+	"sort"
+
+	"spec-recon/internal/model"
+)
+
+// BuildCRUDMatrix rolls the table usage of SQL statements up to the controller
+// endpoints that reach them through the call graph. View endpoints are kept:
+// page handlers touch tables too
+func BuildCRUDMatrix(nodes []*model.Node) model.CRUDMatrix {
+	matrix := model.CRUDMatrix{Columns: make(map[string][]string)}
+	columnSets := make(map[string]map[string]bool)
+
+	for _, node := range nodes {
+		if node.Type != model.NodeTypeController {
+			continue
+		}
+		for _, method := range node.Children {
+			if method.Type != model.NodeTypeController {
+				continue
+			}
+
+			access := make(map[string]string)
+			collectTableAccess(method, access, columnSets, make(map[*model.Node]bool))
+			for _, path := range method.Paths() {
+				matrix.Rows = append(matrix.Rows, model.CRUDRow{
+					Method:     extractHTTPMethod(method),
+					Path:       path,
+					Controller: extractSimpleName(node.ID),
+					Handler:    method.Method,
+					Access:     access,
+				})
+			}
+		}
+	}
+
+	for table, columnSet := range columnSets {
+		matrix.Tables = append(matrix.Tables, table)
+		columns := make([]string, 0, len(columnSet))
+		for column := range columnSet {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		matrix.Columns[table] = columns
+	}
+	sort.Strings(matrix.Tables)
+	sort.SliceStable(matrix.Rows, func(i, j int) bool {
+		return matrix.Rows[i].Path < matrix.Rows[j].Path
+	})
+	return matrix
+}
+
+// collectTableAccess merges the table operations of every SQL node reachable
+// from node into access, and their columns into columns
+// Shared callees are visited once per endpoint, which also stops call cycles
+func collectTableAccess(node *model.Node, access map[string]string, columns map[string]map[string]bool, visited map[*model.Node]bool) {
+	if node == nil || visited[node] {
+		return
+	}
+	visited[node] = true
+
+	for _, table := range node.Tables {
+		access[table.Table] = model.MergeOperations(access[table.Table], table.Operations)
+		if columns[table.Table] == nil {
+			columns[table.Table] = make(map[string]bool)
+		}
+		for _, column := range table.Columns {
+			columns[table.Table][column] = true
+		}
+	}
+	for _, child := range node.Children {
+		collectTableAccess(child, access, columns, visited)
+	}
+}
@@ -0,0 +1,207 @@
+package analyzer
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"spec-recon/internal/model"
+)
+
+// exceptionSuperTypes is the superclass of common JDK and Spring exceptions,
+// by simple name (project exceptions are followed through the class map)
+var exceptionSuperTypes = map[string]string{
+	"Exception": "Throwable", "Error": "Throwable", "RuntimeException": "Exception",
+	"IllegalArgumentException": "RuntimeException", "IllegalStateException": "RuntimeException",
+	"NumberFormatException": "IllegalArgumentException", "NullPointerException": "RuntimeException",
+	"UnsupportedOperationException": "RuntimeException", "IndexOutOfBoundsException": "RuntimeException",
+	"NoSuchElementException": "RuntimeException", "ClassCastException": "RuntimeException",
+	"ArithmeticException": "RuntimeException", "IOException": "Exception", "FileNotFoundException": "IOException",
+	"UncheckedIOException": "RuntimeException", "SQLException": "Exception",
+	"DataAccessException": "RuntimeException", "EmptyResultDataAccessException": "DataAccessException",
+	"DuplicateKeyException": "DataAccessException", "DataIntegrityViolationException": "DataAccessException",
+	"AccessDeniedException": "RuntimeException", "ResponseStatusException": "RuntimeException",
+	"ConstraintViolationException": "RuntimeException", "MethodArgumentNotValidException": "Exception",
+	"BindException": "Exception", "EntityNotFoundException": "RuntimeException",
+}
+
+// extractErrorResponses documents the error responses of an endpoint: each
+// exception declared or thrown along the handler's call chain goes to the
+// @ExceptionHandler that catches it (the controller's own handlers first, then
+// @ControllerAdvice ones, closest exception type first), or else to the
+// @ResponseStatus of its class. Responses are merged by status code and
+// described by their exceptions, with the JavaDoc @throws text when present
+func extractErrorResponses(controller, method *model.Node, classMap map[string]*model.Node, fieldTypeMap map[string]map[string]string) []model.ResponseDef {
+	docs := make(map[string]string)
+	thrown := collectThrows(method, nil, docs, make(map[*model.Node]bool))
+	if len(thrown) == 0 {
+		return nil
+	}
+
+	var local []*model.Node
+	for _, child := range controller.Children {
+		if child.ExceptionHandler != nil {
+			local = append(local, child)
+		}
+	}
+	var adviceClasses []string
+	for className, classNode := range classMap {
+		for _, child := range classNode.Children {
+			if child.ExceptionHandler != nil && child.ExceptionHandler.Advice && adviceApplies(child.ExceptionHandler, controller.Package) {
+				adviceClasses = append(adviceClasses, className)
+				break
+			}
+		}
+	}
+	sort.Strings(adviceClasses)
+
+	byStatus := make(map[int]*model.ResponseDef)
+	exceptions := make(map[int][]string)
+	for _, exception := range thrown {
+		chain := exceptionChain(exception, classMap)
+		handler := findExceptionHandler(local, chain, classMap)
+		for _, className := range adviceClasses {
+			if handler != nil {
+				break
+			}
+			handler = findExceptionHandler(classMap[className].Children, chain, classMap)
+		}
+
+		var response model.ResponseDef
+		if handler != nil {
+			response = extractResponse(handler, classMap, fieldTypeMap)
+			response.StatusCode = handler.ResponseStatus
+			if statuses := responseStatuses(handler); response.StatusCode == 0 && len(statuses) > 0 {
+				response.StatusCode = statuses[0]
+			}
+			if response.StatusCode == 0 {
+				response.StatusCode = 500
+			}
+		} else if status := exceptionStatus(chain, classMap); status != 0 {
+			response = model.ResponseDef{StatusCode: status}
+		} else {
+			continue // Left to the container's default error handling
+		}
+
+		if byStatus[response.StatusCode] == nil {
+			byStatus[response.StatusCode] = &response
+		}
+		description := extractSimpleName(exception)
+		if doc := docs[exception]; doc != "" {
+			description += " (" + doc + ")"
+		}
+		exceptions[response.StatusCode] = append(exceptions[response.StatusCode], description)
+	}
+
+	var responses []model.ResponseDef
+	for status, response := range byStatus {
+		response.Description = strings.Join(exceptions[status], ", ")
+		responses = append(responses, *response)
+	}
+	sort.Slice(responses, func(i, j int) bool { return responses[i].StatusCode < responses[j].StatusCode })
+	return responses
+}
+
+// collectThrows gathers the exceptions declared or thrown by a method and
+// everything it calls, in call order, and into docs the first @throws
+// description found for each of them
+func collectThrows(node *model.Node, thrown []string, docs map[string]string, visited map[*model.Node]bool) []string {
+	if node == nil || visited[node] {
+		return thrown
+	}
+	visited[node] = true
+	for _, exception := range node.Throws {
+		if !slices.Contains(thrown, exception) {
+			thrown = append(thrown, exception)
+		}
+		if docs[exception] == "" && node.Doc != nil {
+			docs[exception] = throwsDescription(node.Doc, exception)
+		}
+	}
+	for _, child := range node.Children {
+		thrown = collectThrows(child, thrown, docs, visited)
+	}
+	return thrown
+}
+
+// throwsDescription returns the @throws description of an exception, which
+// JavaDoc names as written in the source (usually by simple name)
+func throwsDescription(doc *model.DocComment, exception string) string {
+	if description := doc.Throws[exception]; description != "" {
+		return description
+	}
+	return doc.Throws[extractSimpleName(exception)]
+}
+
+// adviceApplies reports whether a @ControllerAdvice handler serves controllers of a package
+func adviceApplies(handler *model.ExceptionHandler, pkg string) bool {
+	if len(handler.BasePackages) == 0 {
+		return true
+	}
+	for _, base := range handler.BasePackages {
+		if pkg == base || strings.HasPrefix(pkg, base+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// exceptionChain returns an exception type followed by its superclasses
+func exceptionChain(exception string, classMap map[string]*model.Node) []string {
+	chain := []string{exception}
+	seen := map[string]bool{exception: true}
+	for current := exception; ; {
+		next := ""
+		if classNode := classMap[current]; classNode != nil {
+			next = classNode.SuperClass
+		} else {
+			next = exceptionSuperTypes[extractSimpleName(current)]
+		}
+		if next == "" || seen[next] {
+			return chain
+		}
+		seen[next] = true
+		chain = append(chain, next)
+		current = next
+	}
+}
+
+// findExceptionHandler returns the handler method for the closest type of an
+// exception chain, or nil
+func findExceptionHandler(methods []*model.Node, chain []string, classMap map[string]*model.Node) *model.Node {
+	for _, exception := range chain {
+		for _, method := range methods {
+			if method.ExceptionHandler == nil {
+				continue
+			}
+			for _, handled := range method.ExceptionHandler.Exceptions {
+				if sameType(handled, exception, classMap) {
+					return method
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// exceptionStatus returns the @ResponseStatus code declared on an exception
+// class or its nearest annotated superclass, or 0
+func exceptionStatus(chain []string, classMap map[string]*model.Node) int {
+	for _, exception := range chain {
+		if classNode := classMap[exception]; classNode != nil && classNode.ResponseStatus != 0 {
+			return classNode.ResponseStatus
+		}
+	}
+	return 0
+}
+
+// sameType compares type names, by simple name when one is outside the project
+func sameType(a, b string, classMap map[string]*model.Node) bool {
+	if a == b {
+		return true
+	}
+	if classMap[a] != nil && classMap[b] != nil {
+		return false
+	}
+	return extractSimpleName(a) == extractSimpleName(b)
+}
@@ -0,0 +1,156 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"spec-recon/internal/linker"
+)
+
+// TestErrorResponses verifies that exceptions thrown along an endpoint's call
+// chain are mapped to @ExceptionHandler methods (local first, then advice) or
+// to the @ResponseStatus of the exception class, and described with the
+// JavaDoc @throws text
+func TestErrorResponses(t *testing.T) {
+	sources := []string{
+		`package com.company.common;
+
+public class BusinessException extends RuntimeException {
+}
+`,
+		`package com.company.common;
+
+@ResponseStatus(HttpStatus.NOT_FOUND)
+public class UserNotFoundException extends BusinessException {
+}
+`,
+		`package com.company.common;
+
+public class DuplicateUserException extends BusinessException {
+}
+`,
+		`package com.company.common;
+
+@ResponseStatus(code = HttpStatus.CONFLICT)
+public class ConflictException extends RuntimeException {
+}
+`,
+		`package com.company.common;
+
+public class ErrorResponse {
+    private String code;
+    private String message;
+}
+`,
+		`package com.company.common;
+
+@RestControllerAdvice(basePackages = "com.company")
+public class GlobalExceptionHandler {
+    @ExceptionHandler(BusinessException.class)
+    @ResponseStatus(HttpStatus.BAD_REQUEST)
+    public ErrorResponse handleBusiness(BusinessException e) {
+        return new ErrorResponse();
+    }
+
+    @ExceptionHandler
+    public ResponseEntity<ErrorResponse> handleNotFound(UserNotFoundException e) {
+        return ResponseEntity.status(HttpStatus.NOT_FOUND).body(new ErrorResponse());
+    }
+}
+`,
+		`package com.other;
+
+@ControllerAdvice("com.other")
+public class OtherExceptionHandler {
+    @ExceptionHandler(RuntimeException.class)
+    @ResponseStatus(HttpStatus.SERVICE_UNAVAILABLE)
+    public String handle(RuntimeException e) {
+        return "error";
+    }
+}
+`,
+		`package com.company.user;
+
+@Service
+public class UserService {
+    /**
+     * Finds a user
+     *
+     * @throws UserNotFoundException if no user has the id
+     */
+    public User get(Long id) {
+        return userRepository.findById(id).orElseThrow(() -> new UserNotFoundException(id));
+    }
+
+    public void create(User user) {
+        if (exists(user)) {
+            throw new DuplicateUserException();
+        }
+    }
+}
+`,
+		`package com.company.user;
+
+@RestController
+@RequestMapping("/users")
+public class UserController {
+    @Autowired
+    private UserService userService;
+
+    @GetMapping("/{id}")
+    public User get(@PathVariable Long id) {
+        return userService.get(id);
+    }
+
+    @PostMapping
+    public void create(@RequestBody User user) {
+        userService.create(user);
+    }
+
+    @DeleteMapping("/{id}")
+    public void delete(@PathVariable Long id) throws ConflictException {
+        if (id < 0) {
+            throw new IllegalArgumentException("id");
+        }
+    }
+
+    @ExceptionHandler(IllegalArgumentException.class)
+    @ResponseStatus(HttpStatus.UNPROCESSABLE_ENTITY)
+    public void handleInvalid(IllegalArgumentException e) {
+    }
+}
+`,
+	}
+
+	pool := linker.NewTestPool(t, sources...)
+	nodes := linker.NewLinker(pool).BuildCallGraph()
+
+	type errorResponse struct {
+		Status      int
+		Type        string
+		Description string
+		Fields      int
+	}
+	want := map[string][]errorResponse{
+		"get":    {{404, "ResponseEntity<ErrorResponse>", "UserNotFoundException (if no user has the id)", 2}},
+		"create": {{400, "ErrorResponse", "DuplicateUserException", 2}},
+		"delete": {{409, "", "ConflictException", 0}, {422, "void", "IllegalArgumentException", 0}},
+	}
+	got := make(map[string][]errorResponse)
+	for _, endpoint := range ExtractEndpoints(nodes, pool.ClassMap, pool.FieldTypeMap) {
+		if endpoint.ControllerName != "UserController" {
+			continue
+		}
+		responses := []errorResponse{}
+		for _, response := range endpoint.Responses {
+			if !response.IsError() {
+				continue
+			}
+			responses = append(responses, errorResponse{response.StatusCode, response.Type, response.Description, len(response.Fields)})
+		}
+		got[endpoint.MethodName] = responses
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Error responses:\ngot  %+v\nwant %+v", got, want)
+	}
+}
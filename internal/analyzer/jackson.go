@@ -0,0 +1,111 @@
+package analyzer
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"spec-recon/internal/model"
+)
+
+// jsonIncludeRules describe the @JsonInclude values that leave a property out
+// of the serialized JSON
+var jsonIncludeRules = map[string]string{
+	"NON_NULL":    "omitted when null",
+	"NON_ABSENT":  "omitted when null or absent",
+	"NON_EMPTY":   "omitted when empty",
+	"NON_DEFAULT": "omitted when default",
+}
+
+// jsonProperty returns the name a field is serialized under by Jackson, or
+// false when Jackson skips it (@JsonIgnore, or @JsonIgnoreProperties of the
+// serialized class or the superclass declaring the field). An explicit
+// @JsonProperty name is kept as written; other names follow the serialized
+// class's naming strategy
+func jsonProperty(field *model.FieldInfo, fieldName string, classNode, owner *model.Node) (string, bool) {
+	for _, declaring := range []*model.Node{classNode, owner} {
+		if ann, ok := model.FindAnnotation(declaring.TypeAnnotations, "JsonIgnoreProperties"); ok && slices.Contains(ann.Values["value"], fieldName) {
+			return "", false
+		}
+	}
+	if field == nil {
+		return translatePropertyName(fieldName, classNode.PropertyNaming), true
+	}
+	if ann, ok := model.FindAnnotation(field.Annotations, "JsonIgnore"); ok && ann.Value("value") != "false" {
+		return "", false
+	}
+	if ann, ok := model.FindAnnotation(field.Annotations, "JsonProperty"); ok && ann.Value("value") != "" {
+		return ann.Value("value"), true
+	}
+	return translatePropertyName(fieldName, classNode.PropertyNaming), true
+}
+
+// applyJSONFormat records how a field is written to JSON: the date pattern of
+// @JsonFormat, and the @JsonInclude rule of the field or its class, both
+// noted in the description
+func applyJSONFormat(param *model.ParamDef, field *model.FieldInfo, classNode *model.Node) {
+	include, ok := model.FindAnnotation(classNode.TypeAnnotations, "JsonInclude")
+	if field != nil {
+		if ann, ok := model.FindAnnotation(field.Annotations, "JsonFormat"); ok && ann.Value("pattern") != "" {
+			param.DatePattern = ann.Value("pattern")
+			param.Description += " (" + param.DatePattern + ")"
+		}
+		if ann, found := model.FindAnnotation(field.Annotations, "JsonInclude"); found {
+			include, ok = ann, true
+		}
+	}
+	if !ok {
+		return
+	}
+	value := include.Value("value")
+	if rule := jsonIncludeRules[value[strings.LastIndex(value, ".")+1:]]; rule != "" {
+		param.Description += " (" + rule + ")"
+	}
+}
+
+// translatePropertyName applies a Jackson naming strategy to a property name
+// The strategy may be named as in configuration ("SNAKE_CASE") or by its
+// class ("SnakeCaseStrategy"); unknown strategies keep the name
+func translatePropertyName(name, strategy string) string {
+	normalized := strings.ToUpper(strings.NewReplacer("_", "", "-", "").Replace(strategy))
+	switch strings.TrimSuffix(normalized, "STRATEGY") {
+	case "SNAKECASE", "LOWERCASEWITHUNDERSCORES", "CAMELCASETOLOWERCASEWITHUNDERSCORES":
+		return separateWords(name, '_')
+	case "KEBABCASE":
+		return separateWords(name, '-')
+	case "LOWERDOTCASE":
+		return separateWords(name, '.')
+	case "LOWERCASE":
+		return strings.ToLower(name)
+	case "UPPERSNAKECASE":
+		return strings.ToUpper(separateWords(name, '_'))
+	case "UPPERCAMELCASE", "PASCALCASETOCAMELCASE", "PASCALCASE":
+		if name == "" {
+			return name
+		}
+		runes := []rune(name)
+		runes[0] = unicode.ToUpper(runes[0])
+		return string(runes)
+	}
+	return name
+}
+
+// separateWords lower-cases a camelCase name and separates its words the way
+// Jackson does: a run of capitals is one word ("userID" -> "user_id")
+func separateWords(name string, separator rune) string {
+	var sb strings.Builder
+	previousUpper := false
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if !previousUpper && i > 0 && !strings.HasSuffix(sb.String(), string(separator)) {
+				sb.WriteRune(separator)
+			}
+			r = unicode.ToLower(r)
+			previousUpper = true
+		} else {
+			previousUpper = false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
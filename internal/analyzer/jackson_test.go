@@ -0,0 +1,125 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"spec-recon/internal/linker"
+)
+
+// TestTranslatePropertyName verifies the Jackson naming strategies, named as
+// in configuration or by their class
+func TestTranslatePropertyName(t *testing.T) {
+	tests := []struct {
+		name, strategy, want string
+	}{
+		{"userId", "", "userId"},
+		{"userId", "SNAKE_CASE", "user_id"},
+		{"userID", "SnakeCaseStrategy", "user_id"},
+		{"HTMLParser", "snake_case", "htmlparser"},
+		{"userId", "UPPER_SNAKE_CASE", "USER_ID"},
+		{"userId", "KebabCaseStrategy", "user-id"},
+		{"userId", "LOWER_DOT_CASE", "user.id"},
+		{"userId", "LowerCaseStrategy", "userid"},
+		{"userId", "UPPER_CAMEL_CASE", "UserId"},
+		{"userId", "LOWER_CAMEL_CASE", "userId"},
+	}
+	for _, tt := range tests {
+		if got := translatePropertyName(tt.name, tt.strategy); got != tt.want {
+			t.Errorf("translatePropertyName(%q, %q) = %q, want %q", tt.name, tt.strategy, got, tt.want)
+		}
+	}
+}
+
+// TestJacksonProperties verifies that request and response schemas document
+// the JSON properties Jackson writes: renamed by @JsonProperty and the naming
+// strategy (@JsonNaming, else the project-wide one), without ignored fields,
+// with @JsonFormat patterns and @JsonInclude rules; objects bound from query
+// and form parameters keep their Java property names
+func TestJacksonProperties(t *testing.T) {
+	sources := []string{
+		`package com.company.user;
+
+@JsonNaming(PropertyNamingStrategies.SnakeCaseStrategy.class)
+@JsonIgnoreProperties({"internalCode"})
+@JsonInclude(JsonInclude.Include.NON_NULL)
+public class UserResponse {
+    @JsonProperty("uid")
+    private Long userId;
+
+    private String displayName;
+
+    @JsonIgnore
+    private String password;
+
+    private String internalCode;
+
+    @JsonFormat(pattern = "yyyy-MM-dd HH:mm:ss")
+    private LocalDateTime createdAt;
+}
+`,
+		`package com.company.user;
+
+public class UserForm {
+    private String firstName;
+
+    @JsonProperty(value = "mail")
+    private String emailAddress;
+
+    @JsonIgnore(false)
+    private String lastName;
+}
+`,
+		`package com.company.user;
+
+@RestController
+@RequestMapping("/users")
+public class UserController {
+    @GetMapping("/{id}")
+    public UserResponse get(@PathVariable Long id) {
+        return null;
+    }
+
+    @PostMapping
+    public void create(@RequestBody UserForm form) {
+    }
+
+    @GetMapping
+    public void search(@ModelAttribute UserForm filter) {
+    }
+}
+`,
+	}
+
+	pool := linker.NewTestPool(t, sources...)
+	pool.PropertyNaming = "KEBAB_CASE"
+	nodes := linker.NewLinker(pool).BuildCallGraph()
+
+	got := make(map[string]string)
+	for _, endpoint := range ExtractEndpoints(nodes, pool.ClassMap, pool.FieldTypeMap) {
+		for _, response := range endpoint.Responses {
+			for _, field := range response.Fields {
+				got["response."+field.Name] = field.DatePattern + "|" + field.Description
+			}
+		}
+		for _, param := range endpoint.Params {
+			for _, field := range param.Fields {
+				got[param.Name+"."+field.Name] = field.DatePattern + "|" + field.Description
+			}
+		}
+	}
+	want := map[string]string{
+		"response.uid":          "|Field of UserResponse (omitted when null)",
+		"response.display_name": "|Field of UserResponse (omitted when null)",
+		"response.created_at":   "yyyy-MM-dd HH:mm:ss|Field of UserResponse (yyyy-MM-dd HH:mm:ss) (omitted when null)",
+		"form.first-name":       "|Field of UserForm",
+		"form.mail":             "|Field of UserForm",
+		"form.last-name":        "|Field of UserForm",
+		"filter.firstName":      "|Field of UserForm",
+		"filter.emailAddress":   "|Field of UserForm",
+		"filter.lastName":       "|Field of UserForm",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("JSON properties:\ngot  %v\nwant %v", got, want)
+	}
+}
@@ -170,3 +170,41 @@ func TestServiceHop(t *testing.T) {
 		}
 	}
 }
+
+func TestEndpointUsesJavaDoc(t *testing.T) {
+	controller := &model.Node{ID: "com.company.UserController", Type: model.NodeTypeController}
+	method := &model.Node{
+		ID:           "com.company.UserController.getUser",
+		Type:         model.NodeTypeController,
+		Method:       "getUser",
+		Params:       `@PathVariable("id") final Long id, @RequestParam(value = "type", required = false) String type`,
+		ReturnDetail: "String",
+		URL:          "/users/{id}",
+		Annotation:   "GET",
+		Comment:      "사용자를 조회한다.",
+		Doc: &model.DocComment{
+			Summary:     "사용자를 조회한다.",
+			Description: "사용자를 조회한다. 삭제된 사용자는 제외된다.",
+			Params:      map[string]string{"id": "사용자 ID", "type": "조회 유형"},
+			Return:      "사용자 이름",
+		},
+	}
+
+	endpoint := extractEndpointFromMethod(controller, method, map[string]*model.Node{}, map[string]map[string]string{})
+
+	if endpoint.Summary != "사용자를 조회한다." || endpoint.Description != method.Doc.Description {
+		t.Errorf("Unexpected summary/description: %q / %q", endpoint.Summary, endpoint.Description)
+	}
+	if len(endpoint.Params) != 2 {
+		t.Fatalf("Expected 2 params, got %d: %+v", len(endpoint.Params), endpoint.Params)
+	}
+	if p := endpoint.Params[0]; p.Name != "id" || p.Type != "Long" || p.In != "Path" || p.Description != "사용자 ID" {
+		t.Errorf("Unexpected path param: %+v", p)
+	}
+	if p := endpoint.Params[1]; p.Name != "type" || p.In != "Query" || p.Description != "조회 유형" {
+		t.Errorf("Unexpected query param: %+v", p)
+	}
+	if endpoint.SuccessResponse().Description != "사용자 이름" {
+		t.Errorf("Unexpected response description %q", endpoint.SuccessResponse().Description)
+	}
+}
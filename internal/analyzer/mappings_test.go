@@ -0,0 +1,178 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"spec-recon/internal/linker"
+	"spec-recon/internal/model"
+)
+
+// TestRequestMappingConstants verifies mapping paths built from constants of
+// other classes, concatenations and arrays, and one endpoint per path
+func TestRequestMappingConstants(t *testing.T) {
+	sources := []string{
+		`package com.company.common;
+
+public final class UrlConstants {
+    public static final String API = "/api";
+    public static final String USER_BASE = API + "/users";
+}
+`,
+		`package com.company.user;
+
+import com.company.common.UrlConstants;
+
+@RestController
+@RequestMapping(value = UrlConstants.USER_BASE)
+public class UserController {
+    private static final String PATH = "/{id}";
+
+    @GetMapping(value = PATH + "/orders")
+    public List<OrderDto> orders(@PathVariable Long id) {
+        return null;
+    }
+
+    @GetMapping(PATH)
+    public UserDto get(@PathVariable Long id) {
+        return null;
+    }
+
+    @RequestMapping(path = {"/search", "/find"}, method = RequestMethod.GET)
+    public List<UserDto> search(String keyword) {
+        return null;
+    }
+
+    @PostMapping(value = Routes.UNKNOWN)
+    public void save(@RequestBody UserDto user) {
+    }
+}
+`,
+		`package com.company.user;
+
+import static com.company.common.UrlConstants.API;
+
+@RequestMapping({API + "/members", API + "/people"})
+public abstract class BaseMemberController {
+}
+`,
+		`package com.company.user;
+
+@RestController
+public class MemberController extends BaseMemberController {
+    @GetMapping
+    public List<UserDto> list() {
+        return null;
+    }
+}
+`,
+	}
+
+	pool := linker.NewTestPool(t, sources...)
+	nodes := linker.NewLinker(pool).BuildCallGraph()
+
+	want := map[string][]string{
+		"com.company.user.UserController.orders(Long)":   {"/api/users/{id}/orders"},
+		"com.company.user.UserController.get(Long)":      {"/api/users/{id}"},
+		"com.company.user.UserController.search(String)": {"/api/users/search", "/api/users/find"},
+		"com.company.user.UserController.save(UserDto)":  {"/api/users/Routes.UNKNOWN"},
+		"com.company.user.MemberController.list()":       {"/api/members", "/api/people"},
+	}
+	for key, paths := range want {
+		method := pool.GetMethod(key)
+		if method == nil {
+			t.Fatalf("%s: method not found", key)
+		}
+		if got := method.Paths(); !reflect.DeepEqual(got, paths) || method.URL != paths[0] {
+			t.Errorf("%s: URL %q, paths %q, want %q", key, method.URL, got, paths)
+		}
+	}
+
+	var endpoints []string
+	for _, endpoint := range ExtractEndpoints(nodes, pool.ClassMap, pool.FieldTypeMap) {
+		if endpoint.ControllerName == "MemberController" {
+			endpoints = append(endpoints, endpoint.Method+" "+endpoint.Path)
+		}
+	}
+	if want := []string{"GET /api/members", "GET /api/people"}; !reflect.DeepEqual(endpoints, want) {
+		t.Errorf("MemberController endpoints: got %v, want %v", endpoints, want)
+	}
+}
+
+// TestRequestMappingConditions verifies that methods, params, headers,
+// consumes and produces are combined with the class mapping, and that
+// handlers told apart by params stay separate endpoints
+func TestRequestMappingConditions(t *testing.T) {
+	source := `package com.company.board;
+
+@Controller
+@RequestMapping(value = "/board", produces = MediaType.APPLICATION_JSON_VALUE, headers = "X-API-VERSION=2")
+public class BoardController {
+    private static final String FORM = "application/x-www-form-urlencoded";
+
+    @RequestMapping(value = "/edit.do", params = "action=save", method = {RequestMethod.GET, RequestMethod.POST}, consumes = FORM)
+    @ResponseBody
+    public Result save(BoardForm form) {
+        return null;
+    }
+
+    @RequestMapping(value = "/edit.do", params = {"action=delete", "!force"})
+    @ResponseBody
+    public Result delete(@RequestParam("id") Long id) {
+        return null;
+    }
+
+    @GetMapping(value = "/export", produces = {"text/csv", MediaType.APPLICATION_PDF_VALUE})
+    @ResponseBody
+    public byte[] export() {
+        return null;
+    }
+}
+`
+	pool := linker.NewTestPool(t, source)
+	nodes := linker.NewLinker(pool).BuildCallGraph()
+
+	want := map[string]model.RequestMapping{
+		"save(BoardForm)": {
+			Methods:  []string{"GET", "POST"},
+			Params:   []string{"action=save"},
+			Headers:  []string{"X-API-VERSION=2"},
+			Consumes: []string{"application/x-www-form-urlencoded"},
+			Produces: []string{"application/json"},
+		},
+		"delete(Long)": {
+			Params:   []string{"action=delete", "!force"},
+			Headers:  []string{"X-API-VERSION=2"},
+			Produces: []string{"application/json"},
+		},
+		"export()": {
+			Methods:  []string{"GET"},
+			Headers:  []string{"X-API-VERSION=2"},
+			Produces: []string{"text/csv", "application/pdf"},
+		},
+	}
+	for method, w := range want {
+		node := pool.GetMethod("com.company.board.BoardController." + method)
+		if node.Mapping == nil || !reflect.DeepEqual(*node.Mapping, w) {
+			t.Errorf("%s: got %+v, want %+v", method, node.Mapping, w)
+		}
+	}
+
+	endpoints := make(map[string]model.EndpointDef)
+	for _, endpoint := range ExtractEndpoints(nodes, pool.ClassMap, pool.FieldTypeMap) {
+		endpoints[endpoint.MethodName] = endpoint
+	}
+	if len(endpoints) != 3 {
+		t.Fatalf("Expected 3 endpoints, got %+v", endpoints)
+	}
+	if save := endpoints["save"]; save.Method != "GET, POST" || save.Path != "/board/edit.do" {
+		t.Errorf("save: got %s %s", save.Method, save.Path)
+	}
+	var conditionParams []string
+	for _, param := range endpoints["delete"].Params {
+		conditionParams = append(conditionParams, param.In+" "+param.Name)
+	}
+	if want := []string{"Query id", "Query action", "Header X-API-VERSION"}; endpoints["delete"].Method != "ALL" || !reflect.DeepEqual(conditionParams, want) {
+		t.Errorf("delete: got %s with params %v, want ALL with %v", endpoints["delete"].Method, conditionParams, want)
+	}
+}
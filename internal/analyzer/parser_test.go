@@ -401,3 +401,201 @@ func TestHybridSampleCompleteness(t *testing.T) {
 		t.Errorf("Not all files parsed successfully")
 	}
 }
+
+// TestXMLParserDynamicSQL verifies that SQL inside dynamic elements is kept and marked
+func TestXMLParserDynamicSQL(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<mapper namespace="com.company.modern.OrderMapper">
+    <select id="searchOrders" resultType="OrderDto">
+        SELECT ORDER_ID, STATUS FROM TB_ORDER
+        <where>
+            DEL_YN = 'N'
+            <if test="status != null">AND STATUS = #{status}</if>
+            <choose>
+                <when test="sort == 'date'">AND ORDER_DATE &gt;= #{from}</when>
+                <otherwise>AND ORDER_DATE IS NOT NULL</otherwise>
+            </choose>
+            <if test="ids != null">
+                AND ORDER_ID IN
+                <foreach collection="ids" item="id" open="(" separator="," close=")">#{id}</foreach>
+            </if>
+        </where>
+    </select>
+    <update id="updateOrder">
+        UPDATE TB_ORDER
+        <set>
+            <if test="status != null">STATUS = #{status},</if>
+            UPDATED_AT = NOW(),
+        </set>
+        <trim prefix="WHERE" prefixOverrides="AND |OR ">AND ORDER_ID = #{orderId}</trim>
+    </update>
+</mapper>
+`
+	mapper, err := xmlparser.ParseXMLFile(content)
+	if err != nil {
+		t.Fatalf("Failed to parse XML: %v", err)
+	}
+
+	search := mapper.GetSQLByID("searchOrders")
+	expected := "SELECT ORDER_ID, STATUS FROM TB_ORDER WHERE DEL_YN = 'N' " +
+		"[if status != null] AND STATUS = #{status} [/if] " +
+		"[when sort == 'date'] AND ORDER_DATE >= #{from} [/when] [otherwise] AND ORDER_DATE IS NOT NULL [/otherwise] " +
+		"[if ids != null] AND ORDER_ID IN [foreach id in ids] ( #{id} ) [/foreach] [/if]"
+	if search.Content != expected {
+		t.Errorf("Unexpected dynamic SQL:\n got: %s\nwant: %s", search.Content, expected)
+	}
+	if search.Line != 3 {
+		t.Errorf("Expected line 3, got %d", search.Line)
+	}
+
+	conditions := search.Root.Conditions()
+	if strings.Join(conditions, "; ") != "status != null; sort == 'date'; ids != null" {
+		t.Errorf("Unexpected conditions: %v", conditions)
+	}
+
+	expected = "SELECT ORDER_ID, STATUS FROM TB_ORDER WHERE DEL_YN = 'N' AND STATUS = #{status} " +
+		"AND ORDER_DATE >= #{from} AND ORDER_DATE IS NOT NULL AND ORDER_ID IN ( #{id} )"
+	if plain := search.Root.PlainSQL(); plain != expected {
+		t.Errorf("Unexpected plain SQL:\n got: %s\nwant: %s", plain, expected)
+	}
+
+	update := mapper.GetSQLByID("updateOrder")
+	expected = "UPDATE TB_ORDER SET [if status != null] STATUS = #{status}, [/if] UPDATED_AT = NOW() WHERE ORDER_ID = #{orderId}"
+	if update.Content != expected {
+		t.Errorf("Unexpected <set>/<trim> rendering:\n got: %s\nwant: %s", update.Content, expected)
+	}
+}
+
+// TestXMLParserSQLFragments verifies <include> expansion within and across mapper files
+func TestXMLParserSQLFragments(t *testing.T) {
+	commonXML := `<?xml version="1.0" encoding="UTF-8"?>
+<mapper namespace="com.company.common.CommonMapper">
+    <sql id="pagingHeader">SELECT * FROM (SELECT ROWNUM RN, A.* FROM (</sql>
+    <sql id="pagingFooter">) A) WHERE RN BETWEEN #{start} AND <include refid="pageEnd"/></sql>
+    <sql id="pageEnd">#{end}</sql>
+</mapper>
+`
+	userXML := `<?xml version="1.0" encoding="UTF-8"?>
+<mapper namespace="com.company.modern.UserMapper">
+    <sql id="userColumns">${alias}.USER_ID, ${alias}.USER_NAME</sql>
+    <select id="selectUserPage">
+        <include refid="com.company.common.CommonMapper.pagingHeader"/>
+        SELECT <include refid="userColumns"><property name="alias" value="u"/></include>
+        FROM TB_USER u WHERE u.NAME LIKE '${keyword}'
+        <include refid="com.company.common.CommonMapper.pagingFooter"/>
+    </select>
+    <select id="selectBroken">
+        SELECT <include refid="missingColumns"/> FROM TB_USER
+    </select>
+</mapper>
+`
+	common, err := xmlparser.ParseXMLFileWithPath("mapper/common/CommonMapper.xml", commonXML)
+	if err != nil {
+		t.Fatalf("Failed to parse common mapper: %v", err)
+	}
+	user, err := xmlparser.ParseXMLFileWithPath("mapper/UserMapper.xml", userXML)
+	if err != nil {
+		t.Fatalf("Failed to parse user mapper: %v", err)
+	}
+
+	// Same-file fragments are expanded while parsing
+	page := user.GetSQLByID("selectUserPage")
+	if !strings.Contains(page.Content, "SELECT u.USER_ID, u.USER_NAME FROM TB_USER") {
+		t.Errorf("Local include with <property> was not expanded: %s", page.Content)
+	}
+	if !strings.Contains(page.Content, "[unresolved include com.company.common.CommonMapper.pagingHeader]") {
+		t.Errorf("Cross-file include should stay marked until registry expansion: %s", page.Content)
+	}
+
+	registry := xmlparser.NewFragmentRegistry()
+	registry.Register(common)
+	registry.Register(user)
+	diagnostics := registry.Expand(user)
+
+	expected := "SELECT * FROM (SELECT ROWNUM RN, A.* FROM ( SELECT u.USER_ID, u.USER_NAME FROM TB_USER u " +
+		"WHERE u.NAME LIKE '${keyword}' ) A) WHERE RN BETWEEN #{start} AND #{end}"
+	if page = user.GetSQLByID("selectUserPage"); page.Content != expected {
+		t.Errorf("Unexpected expanded SQL:\n got: %s\nwant: %s", page.Content, expected)
+	}
+
+	if len(diagnostics) != 1 {
+		t.Fatalf("Expected 1 diagnostic, got %v", diagnostics)
+	}
+	if got := diagnostics[0].String(); got != `mapper/UserMapper.xml:11: <include refid="missingColumns"> in 'selectBroken': fragment not found` {
+		t.Errorf("Unexpected diagnostic: %s", got)
+	}
+	if broken := user.GetSQLByID("selectBroken"); broken.Content != "SELECT [unresolved include missingColumns] FROM TB_USER" {
+		t.Errorf("Unexpected content for unresolved include: %s", broken.Content)
+	}
+}
+
+// TestXMLParserResultMaps verifies statement mapping attributes and <resultMap> parsing
+func TestXMLParserResultMaps(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<mapper namespace="com.company.order.OrderMapper">
+    <resultMap id="orderMap" type="com.company.order.OrderVO" extends="baseMap">
+        <constructor>
+            <idArg column="ORDER_ID" javaType="long" name="orderId"/>
+        </constructor>
+        <result property="status" column="STATUS"/>
+        <association property="customer" resultMap="com.company.user.UserMapper.userMap" columnPrefix="C_"/>
+        <collection property="items" ofType="com.company.order.OrderItemVO">
+            <id property="itemId" column="ITEM_ID"/>
+            <result property="qty" column="QTY"/>
+        </collection>
+        <discriminator javaType="string" column="ORDER_TYPE">
+            <case value="GIFT" resultType="com.company.order.GiftOrderVO">
+                <result property="message" column="GIFT_MESSAGE"/>
+            </case>
+        </discriminator>
+    </resultMap>
+
+    <select id="selectOrder" parameterType="long" resultMap="orderMap">
+        SELECT * FROM TB_ORDER WHERE ORDER_ID = #{orderId}
+    </select>
+    <select id="selectSummary" parameterType="com.company.order.OrderSearch" resultType="map">
+        SELECT STATUS, COUNT(*) CNT FROM TB_ORDER GROUP BY STATUS
+    </select>
+</mapper>
+`
+	mapper, err := xmlparser.ParseXMLFile(content)
+	if err != nil {
+		t.Fatalf("Failed to parse XML: %v", err)
+	}
+
+	order := mapper.GetSQLByID("selectOrder")
+	if order.ParameterType != "long" || order.ResultMap != "orderMap" || order.ResultType != "" {
+		t.Errorf("Unexpected selectOrder attributes: %+v", order)
+	}
+	summary := mapper.GetSQLByID("selectSummary")
+	if summary.ParameterType != "com.company.order.OrderSearch" || summary.ResultType != "map" {
+		t.Errorf("Unexpected selectSummary attributes: %+v", summary)
+	}
+
+	resultMap := mapper.ResultMaps["orderMap"]
+	if resultMap == nil {
+		t.Fatalf("Expected resultMap orderMap, got %v", mapper.ResultMaps)
+	}
+	if resultMap.Type != "com.company.order.OrderVO" || resultMap.Extends != "baseMap" || resultMap.Line != 3 ||
+		resultMap.Namespace != "com.company.order.OrderMapper" {
+		t.Errorf("Unexpected resultMap header: %+v", resultMap)
+	}
+
+	var kinds []string
+	for _, mapping := range resultMap.Mappings {
+		kinds = append(kinds, mapping.Kind+":"+mapping.Property)
+	}
+	if got := strings.Join(kinds, ", "); got != "idArg:orderId, result:status, association:customer, collection:items, case:" {
+		t.Errorf("Unexpected mappings: %s", got)
+	}
+	customer, items, gift := resultMap.Mappings[2], resultMap.Mappings[3], resultMap.Mappings[4]
+	if customer.ResultMap != "com.company.user.UserMapper.userMap" || customer.ColumnPrefix != "C_" {
+		t.Errorf("Unexpected association: %+v", customer)
+	}
+	if items.JavaType != "com.company.order.OrderItemVO" || len(items.Mappings) != 2 || items.Mappings[1].Column != "QTY" {
+		t.Errorf("Unexpected collection: %+v", items)
+	}
+	if len(gift.Mappings) != 1 || gift.Mappings[0].Property != "message" {
+		t.Errorf("Unexpected discriminator case: %+v", gift)
+	}
+}
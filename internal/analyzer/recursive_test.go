@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"testing"
 
+	"spec-recon/internal/linker"
 	"spec-recon/internal/model"
 )
 
@@ -74,3 +75,99 @@ func TestRecursiveGenerics(t *testing.T) {
 		t.Error("Failed to find 'name' field (child of MemberDTO) flattened in the list")
 	}
 }
+
+// TestInheritedDTOFields verifies that fields declared in a DTO's superclass are part of its schema
+func TestInheritedDTOFields(t *testing.T) {
+	classMap := map[string]*model.Node{
+		"com.company.dto.UserDTO": {
+			ID:         "com.company.dto.UserDTO",
+			SuperClass: "com.company.dto.AbstractDTO",
+		},
+		"com.company.dto.AbstractDTO": {
+			ID:     "com.company.dto.AbstractDTO",
+			Fields: []model.FieldInfo{{Name: "createdAt", Type: "LocalDateTime", Comment: "생성 일시"}},
+		},
+	}
+	fieldTypeMap := map[string]map[string]string{
+		"com.company.dto.UserDTO":     {"name": "String", "id": "String"},
+		"com.company.dto.AbstractDTO": {"id": "Long", "createdAt": "LocalDateTime"},
+	}
+
+	fields := resolveSchema("UserDTO", classMap, fieldTypeMap)
+
+	byName := make(map[string]model.ParamDef)
+	for _, f := range fields {
+		byName[f.Name] = f
+	}
+	if len(byName) != 3 {
+		t.Fatalf("Expected 3 fields (name, id, createdAt), got %+v", fields)
+	}
+	if byName["id"].Type != "String" {
+		t.Errorf("Subclass field should hide the inherited one, got type %s", byName["id"].Type)
+	}
+	if byName["createdAt"].Description != "생성 일시" {
+		t.Errorf("Inherited field should keep its comment, got %q", byName["createdAt"].Description)
+	}
+}
+
+// TestNestedDTOFields verifies that nested classes and secondary top-level
+// types of a source file are registered and resolved in request schemas
+func TestNestedDTOFields(t *testing.T) {
+	source := `package com.company.user;
+
+public class UserForm {
+    private String name;
+    private Address address;
+    private UserForm.Phone phone;
+    private Grade grade;
+
+    public static class Address {
+        private String city;
+    }
+
+    public static class Phone {
+        private String number;
+    }
+}
+
+class Grade {
+    private int level;
+}
+`
+	controller := `package com.company.user;
+
+@RestController
+public class UserController {
+    @PostMapping("/users")
+    public void create(@RequestBody UserForm form) {
+    }
+}
+`
+	pool := linker.NewTestPool(t, source, controller)
+	for _, name := range []string{"com.company.user.UserForm.Address", "com.company.user.UserForm.Phone", "com.company.user.Grade"} {
+		if pool.ClassMap[name] == nil {
+			t.Errorf("Expected %s in the class map", name)
+		}
+	}
+
+	nodes := linker.NewLinker(pool).BuildCallGraph()
+	endpoints := ExtractEndpoints(nodes, pool.ClassMap, pool.FieldTypeMap)
+	if len(endpoints) != 1 || len(endpoints[0].Params) != 1 {
+		t.Fatalf("Expected one endpoint with one parameter, got %+v", endpoints)
+	}
+	fields := make(map[string]model.ParamDef)
+	var parents []string
+	for _, field := range endpoints[0].Params[0].Fields {
+		parents = append(parents[:field.Depth-1], field.Name)
+		path := parents[0]
+		for _, name := range parents[1:] {
+			path += "." + name
+		}
+		fields[path] = field
+	}
+	for _, path := range []string{"address.city", "phone.number", "grade.level"} {
+		if _, ok := fields[path]; !ok {
+			t.Errorf("Expected schema field %s, got %v", path, fields)
+		}
+	}
+}
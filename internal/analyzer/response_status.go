@@ -0,0 +1,143 @@
+package analyzer
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"spec-recon/internal/model"
+)
+
+// responseStatusRegexes read the statuses a method body sets:
+// ResponseEntity.status(HttpStatus.X) and new ResponseEntity<>(body, HttpStatus.X)
+var responseStatusRegexes = []*regexp.Regexp{
+	regexp.MustCompile(`ResponseEntity\s*\.\s*status\s*\(\s*([\w.]+(?:\(\s*\d*\s*\))?)`),
+	regexp.MustCompile(`new\s+ResponseEntity\s*<[^>]*>\s*\([^;]*?(HttpStatus\s*\.\s*\w+)`),
+}
+
+// servletStatusRegex reads response.setStatus(...) / response.sendError(...)
+// with the receiver, which must be the servlet response (see isServletResponse)
+var servletStatusRegex = regexp.MustCompile(`\b(\w+)\s*\.\s*(?:setStatus|sendError)\s*\(\s*([\w.]+(?:\(\s*\d*\s*\))?)`)
+
+// responseEntityShortcutRegex reads the status builders: ResponseEntity.notFound()
+var responseEntityShortcutRegex = regexp.MustCompile(`ResponseEntity\s*\.\s*(ok|created|accepted|noContent|badRequest|notFound|unprocessableEntity|internalServerError)\s*\(`)
+
+// responseEntityShortcuts are the codes of the ResponseEntity status builders
+var responseEntityShortcuts = map[string]int{
+	"ok": 200, "created": 201, "accepted": 202, "noContent": 204,
+	"badRequest": 400, "notFound": 404, "unprocessableEntity": 422, "internalServerError": 500,
+}
+
+// extractResponses documents the responses a handler writes itself: the
+// @ResponseStatus code, else every status its body sets (keeping the default
+// 200, or 204 for void, when none of them is a success), else the default
+func extractResponses(method *model.Node, classMap map[string]*model.Node, fieldTypeMap map[string]map[string]string) []model.ResponseDef {
+	response := extractResponse(method, classMap, fieldTypeMap)
+	if method.ResponseStatus != 0 {
+		return []model.ResponseDef{withStatus(response, method.ResponseStatus)}
+	}
+
+	statuses := responseStatuses(method)
+	var responses []model.ResponseDef
+	success := false
+	for _, status := range statuses {
+		switch {
+		case status == 204:
+			responses = append(responses, model.ResponseDef{StatusCode: status, Type: "void", Description: "No content"})
+			success = true
+		case status >= 200 && status < 300:
+			responses = append(responses, withStatus(response, status))
+			success = true
+		default:
+			responses = append(responses, model.ResponseDef{StatusCode: status, Description: model.HTTPStatusText(status)})
+		}
+	}
+	if !success {
+		responses = append(responses, response)
+	}
+	return responses
+}
+
+// withStatus returns a response with another status code; a response without
+// a body takes the status's reason phrase as its description
+func withStatus(response model.ResponseDef, status int) model.ResponseDef {
+	if response.StatusCode == 204 && response.Description == "No content" {
+		response.Description = model.HTTPStatusText(status)
+	}
+	response.StatusCode = status
+	return response
+}
+
+// responseStatuses returns the distinct status codes a method body sets, in
+// source order
+func responseStatuses(method *model.Node) []int {
+	body := method.Body
+	type match struct{ pos, status int }
+	var matches []match
+	for _, re := range responseStatusRegexes {
+		for _, loc := range re.FindAllStringSubmatchIndex(body, -1) {
+			expr := strings.Join(strings.Fields(body[loc[2]:loc[3]]), "")
+			if status := model.HTTPStatusCode(expr); status != 0 {
+				matches = append(matches, match{loc[0], status})
+			}
+		}
+	}
+	for _, loc := range servletStatusRegex.FindAllStringSubmatchIndex(body, -1) {
+		if !isServletResponse(method, body[loc[2]:loc[3]]) {
+			continue // vo.setStatus(...) on a DTO
+		}
+		expr := strings.Join(strings.Fields(body[loc[4]:loc[5]]), "")
+		if status := model.HTTPStatusCode(expr); status != 0 {
+			matches = append(matches, match{loc[0], status})
+		}
+	}
+	for _, loc := range responseEntityShortcutRegex.FindAllStringSubmatchIndex(body, -1) {
+		matches = append(matches, match{loc[0], responseEntityShortcuts[body[loc[2]:loc[3]]]})
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].pos < matches[j].pos })
+
+	var statuses []int
+	for _, m := range matches {
+		if !slices.Contains(statuses, m.status) {
+			statuses = append(statuses, m.status)
+		}
+	}
+	return statuses
+}
+
+// isServletResponse reports whether a variable of a method is the servlet
+// response: declared as HttpServletResponse or ServletResponse (as a parameter
+// or a local), or named response
+func isServletResponse(method *model.Node, name string) bool {
+	if name == "response" {
+		return true
+	}
+	declRegex := regexp.MustCompile(`\b(?:HttpServletResponse|ServletResponse)\s+` + regexp.QuoteMeta(name) + `\b`)
+	return declRegex.MatchString(method.Params) || declRegex.MatchString(method.Body)
+}
+
+// mergeResponses combines response lists by status code, in ascending order
+// The first response for a code keeps its type and schema; descriptions add up
+func mergeResponses(lists ...[]model.ResponseDef) []model.ResponseDef {
+	var merged []model.ResponseDef
+	index := make(map[int]int)
+	for _, list := range lists {
+		for _, response := range list {
+			i, ok := index[response.StatusCode]
+			if !ok {
+				index[response.StatusCode] = len(merged)
+				merged = append(merged, response)
+				continue
+			}
+			if existing := &merged[i]; response.Description != "" && !strings.Contains(existing.Description, response.Description) {
+				if existing.Description != "" {
+					existing.Description += "; "
+				}
+				existing.Description += response.Description
+			}
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].StatusCode < merged[j].StatusCode })
+	return merged
+}
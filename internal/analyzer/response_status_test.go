@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"spec-recon/internal/linker"
+	"spec-recon/internal/model"
+)
+
+// TestSuccessResponses verifies the success status codes read from
+// @ResponseStatus, the ResponseEntity builders and the servlet response
+func TestSuccessResponses(t *testing.T) {
+	source := `package com.company.user;
+
+@RestController
+@RequestMapping("/users")
+public class UserController {
+    @PostMapping
+    @ResponseStatus(HttpStatus.CREATED)
+    public void create(@RequestBody User user) {
+    }
+
+    @PutMapping("/{id}")
+    public ResponseEntity<User> update(@PathVariable Long id) {
+        return ResponseEntity.status(HttpStatus.CREATED).body(user);
+    }
+
+    @GetMapping("/{id}")
+    public ResponseEntity<User> get(@PathVariable Long id) {
+        if (id < 0) {
+            return ResponseEntity.notFound().build();
+        }
+        return ResponseEntity.ok(user);
+    }
+
+    @PostMapping("/jobs")
+    public ResponseEntity<String> submit() {
+        return new ResponseEntity<String>("queued", HttpStatus.ACCEPTED);
+    }
+
+    @DeleteMapping("/{id}")
+    public void delete(@PathVariable Long id, HttpServletResponse response) {
+        response.setStatus(HttpServletResponse.SC_NO_CONTENT);
+    }
+}
+`
+	pool := linker.NewTestPool(t, source)
+	nodes := linker.NewLinker(pool).BuildCallGraph()
+
+	type response struct {
+		Status int
+		Type   string
+	}
+	want := map[string][]response{
+		"create": {{201, "void"}},
+		"update": {{201, "ResponseEntity<User>"}},
+		"get":    {{200, "ResponseEntity<User>"}, {404, ""}},
+		"submit": {{202, "ResponseEntity<String>"}},
+		"delete": {{204, "void"}},
+	}
+	got := make(map[string][]response)
+	for _, endpoint := range ExtractEndpoints(nodes, pool.ClassMap, pool.FieldTypeMap) {
+		for _, r := range endpoint.Responses {
+			got[endpoint.MethodName] = append(got[endpoint.MethodName], response{r.StatusCode, r.Type})
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Responses:\ngot  %+v\nwant %+v", got, want)
+	}
+}
+
+// TestResponseStatuses verifies that setStatus and sendError count only on the
+// servlet response, and that numbers outside the status code range are ignored
+func TestResponseStatuses(t *testing.T) {
+	tests := []struct {
+		params, body string
+		want         []int
+	}{
+		{"", "vo.setStatus(1); order.setStatus(CREATED); return ResponseEntity.ok(vo);", []int{200}},
+		{"", "response.sendError(404); return null;", []int{404}},
+		{"HttpServletResponse res", "res.setStatus(HttpServletResponse.SC_ACCEPTED);", []int{202}},
+		{"", "ServletResponse out = getResponse(); out.setStatus(201);", []int{201}},
+		{"", "response.setStatus(42); return ResponseEntity.status(999).build();", nil},
+	}
+	for _, tt := range tests {
+		method := &model.Node{Params: tt.params, Body: tt.body}
+		if got := responseStatuses(method); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("responseStatuses(%q) = %v, want %v", tt.body, got, tt.want)
+		}
+	}
+}
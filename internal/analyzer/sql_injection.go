@@ -0,0 +1,476 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"spec-recon/internal/model"
+)
+
+var (
+	// getParameterRegex captures the name read by request.getParameter("name")
+	getParameterRegex = regexp.MustCompile(`\bgetParameter(?:Values)?\s*\(\s*"([^"]+)"`)
+	// mapGetRegex captures the key read by map.get("key")
+	mapGetRegex = regexp.MustCompile(`\.get\s*\(\s*"([^"]+)"\s*\)`)
+	// getterRegex captures the property read by dto.getName()
+	getterRegex = regexp.MustCompile(`\.(?:get|is)([A-Z]\w*)\s*\(\s*\)`)
+	// requestNameRegex captures explicit names of request-bound parameters
+	requestNameRegex = regexp.MustCompile(`@(?:RequestParam|PathVariable|RequestHeader|CookieValue)\s*\(\s*(?:(?:value|name)\s*=\s*)?"([^"]+)"`)
+	// constantRegex matches constant references: MAX_ROWS, SqlConst.SELECT_USER
+	constantRegex = regexp.MustCompile(`^(?:\w+\.)*[A-Z][A-Z0-9_]*$`)
+	// identifierRegex matches a plain variable, optionally followed by .toString()
+	identifierRegex = regexp.MustCompile(`^([A-Za-z_$][\w$]*)(?:\s*\.\s*toString\s*\(\s*\))?$`)
+)
+
+// requestInputs are the request values an endpoint hands to the code it calls
+type requestInputs struct {
+	endpoint    string          // "GET /users/list"
+	names       map[string]bool // Parameter names, DTO fields and getParameter("...") keys
+	passthrough bool            // The handler takes a request Map: any key may flow down
+}
+
+// BuildInjectionFindings lists the MyBatis statements that use ${} substitution
+// and the Java methods that build SQL by concatenation for JdbcTemplate or JDBC
+// statements, with the endpoints that reach them through the call graph
+// Findings whose values trace back to a request parameter rank first
+func BuildInjectionFindings(nodes []*model.Node, classMap map[string]*model.Node, fieldTypeMap map[string]map[string]string) []model.InjectionFinding {
+	reach := endpointInputs(nodes, classMap, fieldTypeMap)
+
+	var findings []model.InjectionFinding
+	visited := make(map[*model.Node]bool)
+	var walk func(node *model.Node)
+	walk = func(node *model.Node) {
+		if node == nil || visited[node] {
+			return
+		}
+		visited[node] = true
+
+		// JDBC calls are SQL nodes of the calling method (see linker.linkJdbcCalls)
+		switch {
+		case node.Type == model.NodeTypeSQL && node.Annotation == "JDBC":
+			if finding, ok := stringBuiltFinding(node, reach[node]); ok {
+				findings = append(findings, finding)
+			}
+		case node.Type == model.NodeTypeSQL:
+			if finding, ok := substitutionFinding(node, reach[node]); ok {
+				findings = append(findings, finding)
+			}
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	for _, node := range nodes {
+		walk(node)
+	}
+
+	rank := map[string]int{model.RiskHigh: 0, model.RiskMedium: 1, model.RiskLow: 2}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if rank[findings[i].Risk] != rank[findings[j].Risk] {
+			return rank[findings[i].Risk] < rank[findings[j].Risk]
+		}
+		if findings[i].Location != findings[j].Location {
+			return findings[i].Location < findings[j].Location
+		}
+		return findings[i].Sink < findings[j].Sink
+	})
+	return findings
+}
+
+// endpointInputs maps every node reachable from a handler to the request
+// inputs of the endpoints that reach it
+func endpointInputs(nodes []*model.Node, classMap map[string]*model.Node, fieldTypeMap map[string]map[string]string) map[*model.Node][]requestInputs {
+	reach := make(map[*model.Node][]requestInputs)
+	for _, node := range nodes {
+		if node.Type != model.NodeTypeController {
+			continue
+		}
+		for _, method := range node.Children {
+			if method.Type != model.NodeTypeController {
+				continue
+			}
+			// One entry per mapped path, sharing the handler's inputs
+			var inputs []requestInputs
+			handler := handlerInputs(method, classMap, fieldTypeMap)
+			for _, path := range method.Paths() {
+				handler.endpoint = extractHTTPMethod(method) + " " + path
+				inputs = append(inputs, handler)
+			}
+
+			visited := make(map[*model.Node]bool)
+			var walk func(n *model.Node)
+			walk = func(n *model.Node) {
+				if n == nil || visited[n] {
+					return
+				}
+				visited[n] = true
+				reach[n] = append(reach[n], inputs...)
+				for _, child := range n.Children {
+					walk(child)
+				}
+			}
+			walk(method)
+		}
+	}
+	return reach
+}
+
+// handlerInputs collects the request values a handler declares or reads
+// Parameters inferred from SQL placeholders are left out: they come from the
+// statements being checked
+func handlerInputs(method *model.Node, classMap map[string]*model.Node, fieldTypeMap map[string]map[string]string) requestInputs {
+	inputs := requestInputs{
+		endpoint: extractHTTPMethod(method) + " " + method.Paths()[0],
+		names:    make(map[string]bool),
+	}
+
+	var addFields func(fields []model.ParamDef)
+	addFields = func(fields []model.ParamDef) {
+		for _, field := range fields {
+			if !field.Inferred && field.Name != "(Dynamic)" {
+				inputs.names[field.Name] = true
+				addFields(field.Fields)
+			}
+		}
+	}
+	for _, param := range extractParameters(method, classMap, fieldTypeMap) {
+		switch {
+		case param.Inferred || isServletRequestType(param.Type):
+		case isDynamicType(param.Type):
+			inputs.passthrough = true
+		default:
+			inputs.names[param.Name] = true
+			addFields(param.Fields)
+		}
+	}
+	for _, match := range requestNameRegex.FindAllStringSubmatch(method.Params, -1) {
+		inputs.names[match[1]] = true
+	}
+	for _, match := range getParameterRegex.FindAllStringSubmatch(method.Body, -1) {
+		inputs.names[match[1]] = true
+	}
+	return inputs
+}
+
+// substitutionFinding reports a statement with ${} placeholders
+func substitutionFinding(sqlNode *model.Node, inputs []requestInputs) (model.InjectionFinding, bool) {
+	finding := model.InjectionFinding{
+		Kind:     model.FindingSubstitution,
+		Location: sqlNode.ID,
+		File:     sqlNode.File,
+		Line:     sqlNode.Line,
+		Sink:     sqlNode.ID,
+	}
+	var values [][]string
+	for _, placeholder := range sqlNode.Placeholders {
+		if placeholder.Raw {
+			finding.Expressions = append(finding.Expressions, placeholder.Syntax())
+			values = append(values, valueNames(placeholder.Name))
+		}
+	}
+	if len(values) == 0 {
+		return finding, false
+	}
+	rankFinding(&finding, values, false, inputs)
+	return finding, true
+}
+
+// stringBuiltFinding reports a JDBC call, as linked by the linker, whose SQL
+// argument is concatenated (or formatted) from non-constant values of the
+// calling method
+func stringBuiltFinding(sqlNode *model.Node, inputs []requestInputs) (model.InjectionFinding, bool) {
+	method := sqlNode.Parent
+	if method == nil {
+		return model.InjectionFinding{}, false
+	}
+	operands := sqlArgumentOperands(sqlNode.SQLArgument, method.Body)
+	if len(operands) == 0 {
+		return model.InjectionFinding{}, false
+	}
+
+	finding := model.InjectionFinding{
+		Kind:        model.FindingStringBuilt,
+		Location:    method.ID,
+		File:        sqlNode.File,
+		Line:        sqlNode.Line,
+		Sink:        sqlNode.Method,
+		Expressions: operands,
+	}
+	direct := false
+	var values [][]string
+	for _, operand := range operands {
+		direct = direct || getParameterRegex.MatchString(operand)
+		values = append(values, operandNames(operand))
+	}
+	rankFinding(&finding, values, direct, inputs)
+	return finding, true
+}
+
+// rankFinding fills the endpoints of a finding and ranks it: values that match
+// a request input of a reaching endpoint (or are read from the request
+// directly) are High, other reachable findings Medium, unreachable ones Low
+// Each value is given as the names it may be bound to, most specific first
+func rankFinding(finding *model.InjectionFinding, values [][]string, direct bool, inputs []requestInputs) {
+	endpoints := make(map[string]bool)
+	traced := make(map[string]bool)
+	for _, input := range inputs {
+		endpoints[input.endpoint] = true
+		for _, value := range values {
+			if origin := traceValue(value, input); origin != "" {
+				traced[origin] = true
+			}
+		}
+	}
+	if direct {
+		traced["request.getParameter"] = true
+	}
+
+	finding.Endpoints = sortedKeys(endpoints)
+	finding.TracedTo = sortedKeys(traced)
+	switch {
+	case len(finding.TracedTo) > 0:
+		finding.Risk = model.RiskHigh
+	case len(finding.Endpoints) > 0:
+		finding.Risk = model.RiskMedium
+	default:
+		finding.Risk = model.RiskLow
+	}
+}
+
+// traceValue describes the request input a value comes from, or returns ""
+func traceValue(names []string, input requestInputs) string {
+	if len(names) == 0 {
+		return ""
+	}
+	for _, name := range names {
+		if input.names[name] {
+			return fmt.Sprintf("%s (%s)", name, input.endpoint)
+		}
+	}
+	if input.passthrough {
+		return fmt.Sprintf("%s via request map (%s)", names[0], input.endpoint)
+	}
+	return ""
+}
+
+// valueNames returns the names a value may be bound to in the request: the
+// property path's last segment, then its root ("search.sort" -> sort, search)
+func valueNames(value string) []string {
+	value = strings.TrimSuffix(value, "[]")
+	parts := strings.Split(value, ".")
+	names := []string{parts[len(parts)-1]}
+	if len(parts) > 1 {
+		names = append(names, parts[0])
+	}
+	return names
+}
+
+// sqlArgumentOperands returns the non-constant operands an SQL argument is
+// built from; a variable argument is followed to its assignments and
+// StringBuilder appends in the body. nil when the SQL is constant or unknown
+func sqlArgumentOperands(arg, body string) []string {
+	arg = strings.TrimSpace(arg)
+	if match := identifierRegex.FindStringSubmatch(arg); match != nil {
+		operands, _ := variableOperands(match[1], body, 0)
+		return uniqueOperands(operands)
+	}
+	return uniqueOperands(concatOperands(arg, body, 0))
+}
+
+// variableOperands collects the non-constant operands of every value assigned
+// or appended to a variable; assigned is false when the body never sets it
+func variableOperands(name, body string, depth int) (operands []string, assigned bool) {
+	if depth > 3 {
+		return nil, true
+	}
+	assignRegex := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\s*(\+?=)([^=][^;]*);`)
+	for _, match := range assignRegex.FindAllStringSubmatch(body, -1) {
+		assigned = true
+		rhs := strings.TrimSpace(match[2])
+		if strings.HasPrefix(rhs, "new ") {
+			// new StringBuilder("SELECT ...") / new StringBuffer(sql)
+			if open := strings.Index(rhs, "("); open >= 0 {
+				operands = append(operands, concatOperands(firstArgument(rhs, open+1), body, depth+1)...)
+			}
+			continue
+		}
+		operands = append(operands, concatOperands(rhs, body, depth+1)...)
+	}
+
+	appendRegex := regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\s*\.\s*append\s*\(`)
+	for _, loc := range appendRegex.FindAllStringIndex(body, -1) {
+		assigned = true
+		// Chained appends: sql.append(a).append(b)
+		for pos := loc[1]; ; {
+			arg := firstArgument(body, pos)
+			operands = append(operands, concatOperands(arg, body, depth+1)...)
+			next := pos + len(arg) + 1
+			rest := strings.TrimLeft(body[min(next, len(body)):], " \t\r\n")
+			if !strings.HasPrefix(rest, ".append") {
+				break
+			}
+			open := strings.Index(rest, "(")
+			if open < 0 {
+				break
+			}
+			pos = len(body) - len(rest) + open + 1
+		}
+	}
+	return operands, assigned
+}
+
+// concatOperands returns the non-constant operands of a string expression
+// Literals, numbers and constants are dropped; variables the body builds
+// itself are replaced by their own operands
+func concatOperands(expr, body string, depth int) []string {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil
+	}
+	if strings.HasPrefix(expr, "String.format(") {
+		args := splitTopLevel(firstArgument(expr, len("String.format(")), ',')
+		var operands []string
+		for _, arg := range args[1:] {
+			operands = append(operands, concatOperands(arg, body, depth)...)
+		}
+		return operands
+	}
+
+	var operands []string
+	for _, part := range splitTopLevel(expr, '+') {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "" || isConstantOperand(part):
+		case strings.HasPrefix(part, "(") && strings.HasSuffix(part, ")"):
+			operands = append(operands, concatOperands(part[1:len(part)-1], body, depth)...)
+		default:
+			if match := identifierRegex.FindStringSubmatch(part); match != nil {
+				if built, assigned := variableOperands(match[1], body, depth+1); assigned {
+					operands = append(operands, built...)
+					continue
+				}
+			}
+			operands = append(operands, part)
+		}
+	}
+	return operands
+}
+
+// isConstantOperand reports whether an operand cannot carry request data:
+// a string, char or number literal, null, or a constant
+func isConstantOperand(operand string) bool {
+	switch {
+	case strings.HasPrefix(operand, `"`) && strings.HasSuffix(operand, `"`):
+		return true
+	case strings.HasPrefix(operand, "'") && strings.HasSuffix(operand, "'"):
+		return true
+	case operand == "null" || operand == "true" || operand == "false":
+		return true
+	case unicode.IsDigit(rune(operand[0])):
+		return true
+	}
+	return constantRegex.MatchString(operand)
+}
+
+// operandNames returns the names an operand reads: the key or property it
+// reads (params.get("sort"), dto.getSort()), then the variable itself
+func operandNames(operand string) []string {
+	var names []string
+	for _, match := range getParameterRegex.FindAllStringSubmatch(operand, -1) {
+		names = append(names, match[1])
+	}
+	for _, match := range mapGetRegex.FindAllStringSubmatch(operand, -1) {
+		names = append(names, match[1])
+	}
+	for _, match := range getterRegex.FindAllStringSubmatch(operand, -1) {
+		names = append(names, strings.ToLower(match[1][:1])+match[1][1:])
+	}
+	if match := regexp.MustCompile(`^[A-Za-z_$][\w$]*`).FindString(operand); match != "" {
+		names = append(names, match)
+	}
+	return names
+}
+
+// firstArgument returns the first argument of a call whose argument list
+// starts at text[start] (just after the opening parenthesis)
+func firstArgument(text string, start int) string {
+	depth := 0
+	for i := start; i < len(text); i++ {
+		switch c := text[i]; c {
+		case '"', '\'':
+			i = skipJavaLiteral(text, i)
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			if depth == 0 {
+				return text[start:i]
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				return text[start:i]
+			}
+		}
+	}
+	return text[min(start, len(text)):]
+}
+
+// splitTopLevel splits an expression on sep outside literals and brackets
+func splitTopLevel(expr string, sep byte) []string {
+	var parts []string
+	depth, last := 0, 0
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; {
+		case c == '"' || c == '\'':
+			i = skipJavaLiteral(expr, i)
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, expr[last:i])
+			last = i + 1
+		}
+	}
+	return append(parts, expr[last:])
+}
+
+// skipJavaLiteral returns the index of the quote closing the literal opened at text[start]
+func skipJavaLiteral(text string, start int) int {
+	quote := text[start]
+	for i := start + 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case quote:
+			return i
+		}
+	}
+	return len(text) - 1
+}
+
+// uniqueOperands drops repeated operands, keeping the first occurrence
+func uniqueOperands(operands []string) []string {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, operand := range operands {
+		if !seen[operand] {
+			seen[operand] = true
+			unique = append(unique, operand)
+		}
+	}
+	return unique
+}
+
+// sortedKeys returns the keys of a set, sorted
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
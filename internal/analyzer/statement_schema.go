@@ -0,0 +1,254 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"spec-recon/internal/logger"
+	"spec-recon/internal/model"
+)
+
+var (
+	// returnRegex captures the expression of each return statement
+	returnRegex = regexp.MustCompile(`(?s)\breturn\s+([^;]+);`)
+	// callNameRegex captures the method name of each call in an expression
+	callNameRegex = regexp.MustCompile(`(\w+)\s*\(`)
+	// returnVarRegex matches a return expression that is a plain variable
+	returnVarRegex = regexp.MustCompile(`^\w+$`)
+)
+
+// inferStatementSchema follows the call a method returns down the call graph
+// to a mapped statement and returns the statement's result mapping as a schema
+// Mapper and DAO methods (nodes linked directly to SQL) return their
+// statement's result. visited stops call cycles
+func inferStatementSchema(node *model.Node, classMap map[string]*model.Node, fieldTypeMap map[string]map[string]string, visited map[*model.Node]bool) []model.ParamDef {
+	if node == nil || visited[node] {
+		return nil
+	}
+	visited[node] = true
+
+	for _, child := range node.Children {
+		if child.Type != model.NodeTypeSQL {
+			continue
+		}
+		if fields := statementResultSchema(child, classMap, fieldTypeMap); len(fields) > 0 {
+			logger.Info("[STATEMENT] Using result mapping of %s for %s", child.ID, node.Method)
+			return fields
+		}
+	}
+
+	for _, name := range returnedCalls(node.Body) {
+		for _, child := range node.Children {
+			if child.Type == model.NodeTypeSQL || child.Method != name {
+				continue
+			}
+			if fields := inferStatementSchema(child, classMap, fieldTypeMap, visited); len(fields) > 0 {
+				return fields
+			}
+		}
+	}
+	return nil
+}
+
+// returnedCalls lists the names of the methods whose results a body returns:
+// calls in return expressions, or in the assignment of a returned variable
+// ("List<Map> list = mapper.selectList(p); return list;")
+func returnedCalls(body string) []string {
+	var names []string
+	for _, match := range returnRegex.FindAllStringSubmatch(body, -1) {
+		expr := strings.TrimSpace(match[1])
+		if returnVarRegex.MatchString(expr) {
+			assignRegex := regexp.MustCompile(fmt.Sprintf(`\b%s\s*=\s*([^;]+);`, regexp.QuoteMeta(expr)))
+			for _, assignment := range assignRegex.FindAllStringSubmatch(body, -1) {
+				names = append(names, callNames(assignment[1])...)
+			}
+			continue
+		}
+		names = append(names, callNames(expr)...)
+	}
+	return names
+}
+
+// callNames returns the names of the methods called in an expression, in order
+// Names are only compared with the methods a node calls, so a constructor or
+// keyword followed by "(" never matches
+func callNames(expr string) []string {
+	var names []string
+	for _, match := range callNameRegex.FindAllStringSubmatch(expr, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+// statementResultSchema returns the response schema of a statement: its
+// resultMap (or map result) fields, otherwise the fields of its resultType
+func statementResultSchema(sqlNode *model.Node, classMap map[string]*model.Node, fieldTypeMap map[string]map[string]string) []model.ParamDef {
+	if len(sqlNode.ResultFields) > 0 {
+		return resultFieldParams(sqlNode.ResultFields, sqlNode.ResultType, 1, classMap, fieldTypeMap)
+	}
+	if sqlNode.ResultType != "" && isComplexType(sqlNode.ResultType) && !isDynamicType(sqlNode.ResultType) {
+		return resolveSchema(sqlNode.ResultType, classMap, fieldTypeMap)
+	}
+	return nil
+}
+
+// statementParameterSchema returns the fields of the first parameterType class
+// among the statements a method reaches; it documents Map parameters that are
+// handed down to the mapper
+func statementParameterSchema(node *model.Node, classMap map[string]*model.Node, fieldTypeMap map[string]map[string]string, visited map[*model.Node]bool) []model.ParamDef {
+	if node == nil || visited[node] {
+		return nil
+	}
+	visited[node] = true
+
+	if node.Type == model.NodeTypeSQL {
+		if node.ParameterType != "" && isComplexType(node.ParameterType) && !isDynamicType(node.ParameterType) {
+			return resolveBeanSchema(node.ParameterType, classMap, fieldTypeMap)
+		}
+		return nil
+	}
+	for _, child := range node.Children {
+		if fields := statementParameterSchema(child, classMap, fieldTypeMap, visited); len(fields) > 0 {
+			return fields
+		}
+	}
+	return nil
+}
+
+// resultFieldParams converts result mappings into schema fields
+// Properties without a declared javaType take the type of the field they fill
+func resultFieldParams(fields []model.ResultField, ownerType string, depth int, classMap map[string]*model.Node, fieldTypeMap map[string]map[string]string) []model.ParamDef {
+	var params []model.ParamDef
+	if depth > 5 {
+		return params
+	}
+
+	for _, field := range fields {
+		fieldType := field.Type
+		if fieldType == "" {
+			fieldType = lookupFieldType(ownerType, field.Property, classMap, fieldTypeMap)
+		}
+		elementType := fieldType
+		if field.Collection && elementType != "" {
+			fieldType = "List<" + simpleName(elementType) + ">"
+		}
+		if fieldType == "" {
+			fieldType = "Object"
+		}
+
+		param := model.ParamDef{
+			Name:        field.Property,
+			Type:        simpleName(fieldType),
+			Depth:       depth,
+			Description: "nested result",
+		}
+		if field.Column != "" {
+			param.Description = "column " + field.Column
+		}
+		params = append(params, param)
+
+		switch {
+		case len(field.Fields) > 0:
+			params = append(params, resultFieldParams(field.Fields, elementType, depth+1, classMap, fieldTypeMap)...)
+		case elementType != "" && isComplexType(elementType) && !isSystemType(cleanTypeName(elementType)):
+			params = append(params, resolveSchemaRecursive(elementType, classMap, fieldTypeMap, depth+1, make(map[string]bool), true)...)
+		}
+	}
+	return params
+}
+
+// lookupFieldType returns the declared type of a property of a class,
+// searching its superclasses; "" when the class or property is unknown
+func lookupFieldType(typeName, property string, classMap map[string]*model.Node, fieldTypeMap map[string]map[string]string) string {
+	if typeName == "" || property == "" {
+		return ""
+	}
+	cleanType := cleanTypeName(typeName)
+	node := classMap[cleanType]
+	if node == nil {
+		for key, n := range classMap {
+			if strings.HasSuffix(key, "."+simpleName(cleanType)) {
+				node = n
+				break
+			}
+		}
+	}
+	if node == nil {
+		return ""
+	}
+	for _, owner := range append([]*model.Node{node}, superClassNodes(node, classMap)...) {
+		if fieldType, ok := fieldTypeMap[owner.ID][property]; ok {
+			return fieldType
+		}
+	}
+	return ""
+}
+
+// simpleName strips the package from a (possibly generic) type name:
+// com.x.UserVO -> UserVO; generic arguments are kept as written
+func simpleName(typeName string) string {
+	base, generics := typeName, ""
+	if idx := strings.Index(typeName, "<"); idx >= 0 {
+		base, generics = typeName[:idx], typeName[idx:]
+	}
+	return base[strings.LastIndex(base, ".")+1:] + generics
+}
+
+// placeholderParams lists the #{} and ${} placeholders of every statement a
+// method reaches as inferred, optional request parameters, in order of first
+// use. They document handlers whose inputs arrive through a Map or the
+// servlet request, whose keys the code does not declare
+func placeholderParams(method *model.Node, in string, depth int) []model.ParamDef {
+	var names []string
+	syntax := make(map[string]string)
+	statements := make(map[string][]string)
+
+	visited := make(map[*model.Node]bool)
+	var walk func(node *model.Node)
+	walk = func(node *model.Node) {
+		if node == nil || visited[node] {
+			return
+		}
+		visited[node] = true
+		if node.Type == model.NodeTypeSQL {
+			for _, placeholder := range node.Placeholders {
+				if _, seen := syntax[placeholder.Name]; !seen {
+					names = append(names, placeholder.Name)
+					syntax[placeholder.Name] = placeholder.Syntax()
+				}
+				statements[placeholder.Name] = append(statements[placeholder.Name], statementLabel(node))
+			}
+			return
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(method)
+
+	params := make([]model.ParamDef, 0, len(names))
+	for _, name := range names {
+		source := statements[name][0]
+		if more := len(statements[name]) - 1; more > 0 {
+			source = fmt.Sprintf("%s and %d more", source, more)
+		}
+		params = append(params, model.ParamDef{
+			Name:        name,
+			Type:        "String",
+			In:          in,
+			Depth:       depth,
+			Description: fmt.Sprintf("Inferred from %s in %s (low confidence)", syntax[name], source),
+			Inferred:    true,
+		})
+	}
+	return params
+}
+
+// statementLabel names a statement as "UserMapper.selectUser"
+func statementLabel(sqlNode *model.Node) string {
+	if sqlNode.Package == "" {
+		return sqlNode.Method
+	}
+	return simpleName(sqlNode.Package) + "." + sqlNode.Method
+}
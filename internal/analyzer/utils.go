@@ -13,8 +13,7 @@ import (
 
 // ReadFile reads a file with automatic encoding detection
 // Supports UTF-8 and EUC-KR/CP949 encoding
-// For Java files, comments are removed to prevent regex false positives
-// For XML files, comments are preserved
+// Comments are preserved: the Java tokenizer skips them and keeps JavaDoc
 func ReadFile(path string) (string, error) {
 	// Read raw bytes
 	rawBytes, err := os.ReadFile(path)
@@ -25,10 +24,6 @@ func ReadFile(path string) (string, error) {
 	// Try UTF-8 first
 	content := string(rawBytes)
 	if utf8.Valid(rawBytes) {
-		// Valid UTF-8, check if we should remove comments
-		if IsJavaFile(path) {
-			return removeComments(content), nil
-		}
 		return content, nil
 	}
 
@@ -37,30 +32,10 @@ func ReadFile(path string) (string, error) {
 	decodedBytes, _, err := transform.Bytes(decoder, rawBytes)
 	if err != nil {
 		// If EUC-KR fails, fall back to original (might be corrupted)
-		if IsJavaFile(path) {
-			return removeComments(content), nil
-		}
 		return content, nil
 	}
 
-	content = string(decodedBytes)
-	if IsJavaFile(path) {
-		return removeComments(content), nil
-	}
-	return content, nil
-}
-
-// removeComments removes Java comments to prevent regex false positives
-func removeComments(content string) string {
-	// Remove multi-line comments: /* ... */ and /** ... */
-	multiLineCommentRegex := regexp.MustCompile(`(?s)/\*.*?\*/`)
-	content = multiLineCommentRegex.ReplaceAllString(content, "")
-
-	// Remove single-line comments: //
-	singleLineCommentRegex := regexp.MustCompile(`//.*`)
-	content = singleLineCommentRegex.ReplaceAllString(content, "")
-
-	return content
+	return string(decodedBytes), nil
 }
 
 // NormalizeWhitespace reduces multiple consecutive whitespace to single space
@@ -191,15 +166,15 @@ func ParseMethodParams(params string) []string {
 		return []string{}
 	}
 
-	// Split by comma, but respect generics
+	// Split by comma, but respect generics and annotation arguments
 	result := []string{}
 	current := ""
 	depth := 0
 
 	for _, char := range params {
-		if char == '<' {
+		if char == '<' || char == '(' || char == '{' {
 			depth++
-		} else if char == '>' {
+		} else if char == '>' || char == ')' || char == '}' {
 			depth--
 		} else if char == ',' && depth == 0 {
 			result = append(result, strings.TrimSpace(current))
@@ -0,0 +1,109 @@
+package analyzer
+
+import (
+	"slices"
+	"strconv"
+
+	"spec-recon/internal/model"
+)
+
+// defaultGroup is the Bean Validation group of constraints declaring no groups
+const defaultGroup = "Default"
+
+// validationGroups returns the groups a handler argument is validated with:
+// those of @Validated, or Default for @Valid and a bare @Validated; nil when
+// the argument is not validated (Spring then checks none of its constraints)
+func validationGroups(annotations []model.Annotation) []string {
+	if validated, ok := model.FindAnnotation(annotations, "Validated"); ok {
+		if groups := validated.Values["value"]; len(groups) > 0 {
+			return groups
+		}
+		return []string{defaultGroup}
+	}
+	if _, ok := model.FindAnnotation(annotations, "Valid"); ok {
+		return []string{defaultGroup}
+	}
+	return nil
+}
+
+// constraintApplies reports whether a constraint belongs to one of the groups
+// being validated
+func constraintApplies(constraint model.Annotation, groups []string) bool {
+	constraintGroups := constraint.Values["groups"]
+	if len(constraintGroups) == 0 {
+		constraintGroups = []string{defaultGroup}
+	}
+	for _, group := range constraintGroups {
+		if slices.Contains(groups, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyConstraints maps the constraints of the given groups onto a parameter
+// Other annotations are ignored
+func applyConstraints(param *model.ParamDef, annotations []model.Annotation, groups []string) {
+	for _, ann := range annotations {
+		if !constraintApplies(ann, groups) {
+			continue
+		}
+		switch ann.Name {
+		case "NotNull":
+			param.Required = true
+		case "NotEmpty", "NotBlank":
+			param.Required = true
+			if param.MinLength == nil {
+				param.MinLength = intPtr(1)
+			}
+		case "Size", "Length":
+			if min, err := strconv.Atoi(ann.Value("min")); err == nil && min > 0 {
+				param.MinLength = intPtr(min)
+			}
+			if max, err := strconv.Atoi(ann.Value("max")); err == nil {
+				param.MaxLength = intPtr(max)
+			}
+		case "Pattern":
+			param.Pattern = ann.Value("regexp")
+		case "Email":
+			param.Format = "email"
+		case "Min", "DecimalMin":
+			param.Minimum = floatPtr(ann.Value("value"))
+		case "Max", "DecimalMax":
+			param.Maximum = floatPtr(ann.Value("value"))
+		case "PositiveOrZero":
+			param.Minimum = floatPtr("0")
+		case "NegativeOrZero":
+			param.Maximum = floatPtr("0")
+		}
+	}
+}
+
+// applyFieldConstraints applies the constraints of a validated argument's
+// fields (flattened, depth-first) for the groups in effect
+// Validation cascades into a nested object only through a @Valid field
+func applyFieldConstraints(fields []model.ParamDef, groups []string) {
+	cascade := map[int]bool{1: true}
+	for i := range fields {
+		field := &fields[i]
+		validated := cascade[field.Depth]
+		_, valid := model.FindAnnotation(field.Annotations, "Valid")
+		cascade[field.Depth+1] = validated && valid
+		if validated {
+			applyConstraints(field, field.Annotations, groups)
+		}
+	}
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+// floatPtr parses a constraint bound, or returns nil
+func floatPtr(value string) *float64 {
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil
+	}
+	return &number
+}
@@ -0,0 +1,133 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"spec-recon/internal/linker"
+	"spec-recon/internal/model"
+)
+
+// TestValidationConstraints verifies that Bean Validation constraints of
+// validated request bodies and handler parameters reach the parameters, for
+// the validation groups in effect, and that binding annotations decide
+// whether a parameter is required
+func TestValidationConstraints(t *testing.T) {
+	sources := []string{
+		`package com.company.user;
+
+public interface UserConstants {
+    int NAME_MAX = 20;
+    String CODE_PATTERN = "^[A-Z]{3}$";
+}
+`,
+		`package com.company.user;
+
+public class AddressDto {
+    @NotBlank
+    private String city;
+}
+`,
+		`package com.company.user;
+
+public class UserForm {
+    @NotNull(groups = Update.class)
+    private Long id;
+
+    @NotBlank
+    @Size(min = 2, max = UserConstants.NAME_MAX)
+    private String name;
+
+    @Email
+    private String email;
+
+    @Pattern(regexp = UserConstants.CODE_PATTERN, groups = {Create.class, Update.class})
+    private String code;
+
+    @Min(18) @Max(150)
+    private Integer age;
+
+    @Valid
+    private AddressDto address;
+
+    private AddressDto billing;
+}
+`,
+		`package com.company.user;
+
+@RestController
+@RequestMapping("/users")
+public class UserController {
+    @PostMapping
+    public void create(@Valid @RequestBody UserForm form) {
+    }
+
+    @PutMapping("/{id}")
+    public void update(@PathVariable @Min(1) Long id, @Validated(Update.class) @RequestBody UserForm form) {
+    }
+
+    @PostMapping("/draft")
+    public void draft(@RequestBody UserForm form) {
+    }
+
+    @GetMapping
+    public void search(@RequestParam(required = false) @Size(max = 20) String q,
+                       @RequestParam(defaultValue = "1") int page,
+                       @RequestHeader("X-Tenant") String tenant, String sort) {
+    }
+}
+`,
+	}
+
+	pool := linker.NewTestPool(t, sources...)
+	nodes := linker.NewLinker(pool).BuildCallGraph()
+
+	params := make(map[string]map[string]model.ParamDef)
+	for _, endpoint := range ExtractEndpoints(nodes, pool.ClassMap, pool.FieldTypeMap) {
+		byName := make(map[string]model.ParamDef)
+		for _, param := range endpoint.Params {
+			byName[param.Name] = param
+			path := []string{param.Name}
+			for _, field := range param.Fields {
+				path = append(path[:field.Depth], field.Name)
+				byName[strings.Join(path, ".")] = field
+			}
+		}
+		params[endpoint.MethodName] = byName
+	}
+
+	tests := []struct {
+		method, param string
+		required      bool
+		constraints   string
+	}{
+		{"create", "form", true, ""},
+		{"create", "form.id", false, ""},
+		{"create", "form.name", true, "minLength: 2, maxLength: 20"},
+		{"create", "form.email", false, "format: email"},
+		{"create", "form.code", false, ""},
+		{"create", "form.age", false, "minimum: 18, maximum: 150"},
+		{"create", "form.address.city", true, "minLength: 1"},
+		{"create", "form.billing.city", false, ""},
+		{"update", "id", true, "minimum: 1"},
+		{"update", "form.id", true, ""},
+		{"update", "form.name", false, ""},
+		{"update", "form.code", false, "pattern: ^[A-Z]{3}$"},
+		{"draft", "form.name", false, ""},
+		{"search", "q", false, "maxLength: 20"},
+		{"search", "page", false, ""},
+		{"search", "tenant", true, ""},
+		{"search", "sort", false, ""},
+	}
+	for _, tt := range tests {
+		param, ok := params[tt.method][tt.param]
+		if !ok {
+			t.Errorf("%s: parameter %s not found in %v", tt.method, tt.param, params[tt.method])
+			continue
+		}
+		if param.Required != tt.required || param.ConstraintText() != tt.constraints {
+			t.Errorf("%s %s: got required=%v constraints %q, want required=%v constraints %q",
+				tt.method, tt.param, param.Required, param.ConstraintText(), tt.required, tt.constraints)
+		}
+	}
+}
@@ -28,6 +28,10 @@ type AnalysisConfig struct {
 	ExcludeDirs  []string `mapstructure:"exclude_dirs"`  // Directories to exclude
 	UtilPatterns []string `mapstructure:"util_patterns"` // Patterns for utility classes to exclude
 	IncludeUtils bool     `mapstructure:"include_utils"` // Whether to include utility classes in output
+
+	// Project-wide Jackson naming strategy for JSON property names (e.g., "SNAKE_CASE"),
+	// as spring.jackson.property-naming-strategy; empty keeps the Java field names
+	PropertyNaming string `mapstructure:"property_naming"`
 }
 
 // OutputConfig holds output settings
@@ -123,6 +127,7 @@ func setDefaults(v *viper.Viper) {
 		"*Configuration",
 	})
 	v.SetDefault("analysis.include_utils", false)
+	v.SetDefault("analysis.property_naming", "")
 
 	// Output defaults
 	v.SetDefault("output.dir", "./output")
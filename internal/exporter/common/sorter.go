@@ -10,6 +10,7 @@ import "spec-recon/internal/model"
 //   - Collect CTRL, SVC, MAP, SQL into mainStream
 //   - Collect UTIL into utilStream
 //   - Keep raw data clean (NO indentation characters like └, ㄴ)
+//   - Stop at nodes already on the current path (recursion, decorators calling their interface)
 //
 // Returns:
 //   - mainStream: Business logic nodes (Service, Mapper, SQL)
@@ -19,32 +20,35 @@ func SortNodes(root *model.Node) (mainStream []*model.Node, utilStream []*model.
 	var utils []*model.Node
 
 	// Traverse children of the controller
+	onPath := map[*model.Node]bool{root: true}
 	for _, child := range root.Children {
-		traverseAndSort(child, &main, &utils)
+		traverseAndSort(child, &main, &utils, onPath)
 	}
 
 	return main, utils
 }
 
 // traverseAndSort recursively traverses the tree and separates nodes
-func traverseAndSort(node *model.Node, main *[]*model.Node, utils *[]*model.Node) {
-	if node == nil {
+func traverseAndSort(node *model.Node, main *[]*model.Node, utils *[]*model.Node, onPath map[*model.Node]bool) {
+	if node == nil || onPath[node] {
 		return
 	}
+	onPath[node] = true
+	defer delete(onPath, node)
 
 	// Classify node
 	if node.Type == model.NodeTypeUtil {
 		*utils = append(*utils, node)
 		// For Utils, include their children in the util stream
 		for _, child := range node.Children {
-			traverseAndSort(child, utils, utils)
+			traverseAndSort(child, utils, utils, onPath)
 		}
 	} else {
 		// Business logic nodes (Service, Mapper, SQL)
 		*main = append(*main, node)
 		// Recursively process children
 		for _, child := range node.Children {
-			traverseAndSort(child, main, utils)
+			traverseAndSort(child, main, utils, onPath)
 		}
 	}
 }
@@ -15,26 +15,34 @@ func FlattenTree(root *model.Node) ([]*FlattenedNode, []*FlattenedNode) {
 	var utils []*FlattenedNode
 
 	// Iterate children of the passed root
+	onPath := map[*model.Node]bool{root: true}
 	for _, child := range root.Children {
-		recursiveTraverse(child, 1, &main, &utils)
+		recursiveTraverse(child, 1, &main, &utils, onPath)
 	}
 
 	return main, utils
 }
 
-func recursiveTraverse(node *model.Node, indent int, main *[]*FlattenedNode, utils *[]*FlattenedNode) {
+// recursiveTraverse skips nodes already on the current path, so call cycles terminate
+func recursiveTraverse(node *model.Node, indent int, main *[]*FlattenedNode, utils *[]*FlattenedNode, onPath map[*model.Node]bool) {
+	if onPath[node] {
+		return
+	}
+	onPath[node] = true
+	defer delete(onPath, node)
+
 	row := &FlattenedNode{Node: node, Indent: indent}
 
 	if node.Type == model.NodeTypeUtil {
 		*utils = append(*utils, row)
 		// For Utils, we include their children in the util stream to preserve context
 		for _, child := range node.Children {
-			recursiveTraverse(child, indent+1, utils, utils)
+			recursiveTraverse(child, indent+1, utils, utils, onPath)
 		}
 	} else {
 		*main = append(*main, row)
 		for _, child := range node.Children {
-			recursiveTraverse(child, indent+1, main, utils)
+			recursiveTraverse(child, indent+1, main, utils, onPath)
 		}
 	}
 }
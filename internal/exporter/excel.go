@@ -5,10 +5,10 @@ import (
 	"sort"
 	"strings"
 
+	"spec-recon/internal/analyzer"
 	"spec-recon/internal/config"
 	"spec-recon/internal/exporter/common"
 	"spec-recon/internal/model"
-	"spec-recon/internal/utils"
 
 	"github.com/xuri/excelize/v2"
 )
@@ -42,6 +42,11 @@ func (e *ExcelExporter) Export(summary *model.Summary, tree []*model.Node, cfg *
 		return err
 	}
 
+	// 3. Create CRUD Matrix Sheet
+	if err := e.writeCRUDMatrix(f, styler, analyzer.BuildCRUDMatrix(tree)); err != nil {
+		return err
+	}
+
 	// Remove default "Sheet1"
 	if idx, err := f.GetSheetIndex("Sheet1"); err == nil && idx != -1 {
 		f.DeleteSheet("Sheet1")
@@ -143,13 +148,67 @@ func (e *ExcelExporter) writeOverview(f *excelize.File, s *Styler, summary *mode
 	return nil
 }
 
+// --- CRUD Matrix Sheet Logic ---
+
+// writeCRUDMatrix writes the endpoint × table matrix, followed by the columns
+// referenced per table
+func (e *ExcelExporter) writeCRUDMatrix(f *excelize.File, s *Styler, matrix model.CRUDMatrix) error {
+	sheet := "CRUD Matrix"
+	f.NewSheet(sheet)
+
+	// Section A: Endpoint × Table
+	headers := append([]string{"Method", "Path", "Handler"}, matrix.Tables...)
+	e.writeRow(f, sheet, 1, headers, s.HeaderStyle)
+
+	f.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		XSplit:      3,
+		YSplit:      1,
+		TopLeftCell: "D2",
+		ActivePane:  "bottomRight",
+	})
+
+	row := 2
+	tableOps := make(map[string]string)
+	for _, r := range matrix.Rows {
+		values := []string{r.Method, r.Path, r.Controller + "." + r.Handler}
+		for _, table := range matrix.Tables {
+			values = append(values, r.Access[table])
+			tableOps[table] = model.MergeOperations(tableOps[table], r.Access[table])
+		}
+		e.writeRow(f, sheet, row, values, s.DefaultStyle)
+		row++
+	}
+
+	row += 2 // Spacer
+
+	// Section B: Table Columns
+	e.writeRow(f, sheet, row, []string{"Table", "CRUD", "Columns"}, s.HeaderStyle)
+	row++
+	for _, table := range matrix.Tables {
+		e.writeRow(f, sheet, row, []string{table, tableOps[table], strings.Join(matrix.Columns[table], ", ")}, s.DefaultStyle)
+		row++
+	}
+
+	// Adjust column widths
+	f.SetColWidth(sheet, "A", "A", 20) // Method / Table
+	f.SetColWidth(sheet, "B", "B", 40) // Path / CRUD
+	f.SetColWidth(sheet, "C", "C", 40) // Handler / Columns
+	if len(matrix.Tables) > 0 {
+		last, _ := excelize.ColumnNumberToName(3 + len(matrix.Tables))
+		f.SetColWidth(sheet, "D", last, 16)
+	}
+
+	return nil
+}
+
 // --- Spec Detail Sheet Logic ---
 
 func (e *ExcelExporter) writeSpecDetail(f *excelize.File, s *Styler, controllers []*model.Node) error {
 	sheet := "Spec Detail"
 	f.NewSheet(sheet)
 
-	headers := []string{"Type", "Package/File", "Method/ID", "URL", "Params (Input)", "Return/Detail (Output)", "Comment"}
+	headers := []string{"Type", "Package/File", "Method/ID", "URL", "Params (Input)", "Return/Detail (Output)", "Comment", "Source"}
 	e.writeRow(f, sheet, 1, headers, s.HeaderStyle)
 
 	f.SetPanes(sheet, &excelize.Panes{
@@ -203,7 +262,7 @@ func (e *ExcelExporter) writeSpecDetail(f *excelize.File, s *Styler, controllers
 		if len(validUtil) > 0 {
 			// Separator Row (Only if main stream had content, optional but cleaner)
 			if len(validMain) > 0 {
-				f.SetCellStyle(sheet, fmt.Sprintf("A%d", row), fmt.Sprintf("G%d", row), s.DefaultStyle)
+				f.SetCellStyle(sheet, fmt.Sprintf("A%d", row), fmt.Sprintf("H%d", row), s.DefaultStyle)
 				row++
 			}
 
@@ -223,22 +282,32 @@ func (e *ExcelExporter) writeSpecDetail(f *excelize.File, s *Styler, controllers
 	f.SetColWidth(sheet, "D", "D", 40) // URL
 	f.SetColWidth(sheet, "E", "F", 30) // Params/Return
 	f.SetColWidth(sheet, "G", "G", 50) // Comment
+	f.SetColWidth(sheet, "H", "H", 45) // Source
 
 	return nil
 }
 
+// nodeURL renders a node's request mapping URL, one line per path when it maps several
+func nodeURL(node *model.Node) string {
+	if len(node.URLs) > 0 {
+		return strings.Join(node.URLs, "\n")
+	}
+	return node.URL
+}
+
 func (e *ExcelExporter) writeControllerRow(f *excelize.File, sheet string, row int, node *model.Node, s *Styler) {
 	typeLabel := fmt.Sprintf("[%s]", node.Type)
 
 	f.SetCellValue(sheet, fmt.Sprintf("A%d", row), typeLabel)
 	f.SetCellValue(sheet, fmt.Sprintf("B%d", row), node.Package)
 	f.SetCellValue(sheet, fmt.Sprintf("C%d", row), node.Method)
-	f.SetCellValue(sheet, fmt.Sprintf("D%d", row), node.URL)
+	f.SetCellValue(sheet, fmt.Sprintf("D%d", row), nodeURL(node))
 	f.SetCellValue(sheet, fmt.Sprintf("E%d", row), node.Params)
 	f.SetCellValue(sheet, fmt.Sprintf("F%d", row), node.ReturnDetail)
 	f.SetCellValue(sheet, fmt.Sprintf("G%d", row), node.Comment)
+	f.SetCellValue(sheet, fmt.Sprintf("H%d", row), node.Source())
 
-	f.SetCellStyle(sheet, fmt.Sprintf("A%d", row), fmt.Sprintf("G%d", row), s.ControllerStyle)
+	f.SetCellStyle(sheet, fmt.Sprintf("A%d", row), fmt.Sprintf("H%d", row), s.ControllerStyle)
 }
 
 func (e *ExcelExporter) writeNodeRow(f *excelize.File, sheet string, row int, node *model.Node, s *Styler) {
@@ -271,7 +340,7 @@ func (e *ExcelExporter) writeNodeRow(f *excelize.File, sheet string, row int, no
 	f.SetCellValue(sheet, fmt.Sprintf("C%d", row), node.Method)
 
 	// Column D: URL
-	f.SetCellValue(sheet, fmt.Sprintf("D%d", row), node.URL)
+	f.SetCellValue(sheet, fmt.Sprintf("D%d", row), nodeURL(node))
 
 	// Column E: Params
 	f.SetCellValue(sheet, fmt.Sprintf("E%d", row), node.Params)
@@ -284,9 +353,15 @@ func (e *ExcelExporter) writeNodeRow(f *excelize.File, sheet string, row int, no
 	if node.Type == model.NodeTypeUtil && strings.TrimSpace(comment) == "" {
 		comment = "[Ref] Used in this flow"
 	}
+	if note := node.ImplementationNote(); note != "" {
+		comment = strings.TrimSpace(note + " " + comment)
+	}
 	f.SetCellValue(sheet, fmt.Sprintf("G%d", row), comment)
 
-	f.SetCellStyle(sheet, fmt.Sprintf("A%d", row), fmt.Sprintf("G%d", row), style)
+	// Column H: Source (repository-relative path:line)
+	f.SetCellValue(sheet, fmt.Sprintf("H%d", row), node.Source())
+
+	f.SetCellStyle(sheet, fmt.Sprintf("A%d", row), fmt.Sprintf("H%d", row), style)
 }
 
 func (e *ExcelExporter) writeRow(f *excelize.File, sheet string, row int, values []string, style int) {
@@ -331,17 +406,12 @@ func isExportable(node *model.Node) bool {
 		return false
 	}
 
-	// 3. Noise check (keywords, etc)
-	if utils.IsNoise(node.Method) {
-		return false
-	}
-
-	// 4. Model Class check (DTOs/VOs) using Shared Model Logic
+	// 3. Model Class check (DTOs/VOs) using Shared Model Logic
 	if model.IsModelClass(node.ID) {
 		return false
 	}
 
-	// 5. Empty Util check
+	// 4. Empty Util check
 	if node.Type == model.NodeTypeUtil && len(node.Children) == 0 {
 		return false
 	}
@@ -23,6 +23,7 @@ type APIReportData struct {
 	TotalEndpoints   int
 	TotalControllers int
 	Endpoints        []model.EndpointDef
+	CRUD             model.CRUDMatrix
 }
 
 func (e *HTMLExporter) Export(summary *model.Summary, tree []*model.Node, cfg *config.Config) error {
@@ -59,6 +60,7 @@ func (e *HTMLExporter) Export(summary *model.Summary, tree []*model.Node, cfg *c
 		TotalEndpoints:   totalEndpoints,
 		TotalControllers: totalControllers,
 		Endpoints:        endpoints,
+		CRUD:             analyzer.BuildCRUDMatrix(tree),
 	}
 
 	// Create Output
@@ -73,6 +75,7 @@ func (e *HTMLExporter) Export(summary *model.Summary, tree []*model.Node, cfg *c
 	tmpl, err := template.New("api-report").Funcs(template.FuncMap{
 		"methodColor": getMethodColor,
 		"methodBadge": getMethodBadge,
+		"join":        strings.Join,
 		"mul": func(a, b int) int {
 			return a * b
 		},
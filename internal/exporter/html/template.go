@@ -148,6 +148,19 @@ const APIReportTemplate = `<!DOCTYPE html>
             color: #495057;
         }
 
+        .endpoint-source {
+            font-family: 'Courier New', monospace;
+            font-size: 0.9em;
+            color: #495057;
+        }
+
+        .endpoint-description {
+            margin-top: 6px;
+            color: #6c757d;
+            font-size: 0.9em;
+            white-space: pre-line;
+        }
+
         .endpoint-body {
             padding: 20px;
         }
@@ -196,6 +209,12 @@ const APIReportTemplate = `<!DOCTYPE html>
             color: #e83e8c;
         }
 
+        .param-constraints {
+            font-family: 'Courier New', monospace;
+            font-size: 0.9em;
+            color: #6c757d;
+        }
+
         .param-in {
             display: inline-block;
             padding: 2px 8px;
@@ -230,6 +249,11 @@ const APIReportTemplate = `<!DOCTYPE html>
             font-weight: 600;
         }
 
+        .response-error {
+            color: #f93e3e;
+            font-weight: 600;
+        }
+
         footer {
             text-align: center;
             padding: 30px 20px;
@@ -243,6 +267,25 @@ const APIReportTemplate = `<!DOCTYPE html>
             color: #6c757d;
         }
         
+        /* CRUD matrix */
+        .crud-matrix {
+            overflow-x: auto;
+        }
+
+        .crud-table {
+            font-family: 'Courier New', monospace;
+            font-size: 0.85em;
+            text-align: center;
+            white-space: nowrap;
+        }
+
+        .crud-cell {
+            font-family: 'Courier New', monospace;
+            font-weight: 600;
+            text-align: center;
+            color: #d32f2f;
+        }
+
         /* Nested field styling */
         .nested-param {
             background: #fcfcfc;
@@ -290,10 +333,14 @@ const APIReportTemplate = `<!DOCTYPE html>
                     </div>
                     <div class="endpoint-meta">
                         Controller: <strong>{{.ControllerName}}</strong> · Method: <strong>{{.MethodName}}</strong>
+                        {{if .Source}} · Source: <code class="endpoint-source">{{.Source}}</code>{{end}}
                     </div>
                     {{if .Summary}}
                     <div class="endpoint-summary">{{.Summary}}</div>
                     {{end}}
+                    {{if and .Description (ne .Description .Summary)}}
+                    <div class="endpoint-description">{{.Description}}</div>
+                    {{end}}
                 </div>
 
                 <div class="endpoint-body">
@@ -306,6 +353,7 @@ const APIReportTemplate = `<!DOCTYPE html>
                                 <th>Type</th>
                                 <th>In</th>
                                 <th>Required</th>
+                                <th>Constraints</th>
                                 <th>Description</th>
                             </tr>
                         </thead>
@@ -322,6 +370,7 @@ const APIReportTemplate = `<!DOCTYPE html>
                                     <span class="optional-badge">Optional</span>
                                     {{end}}
                                 </td>
+                                <td class="param-constraints">{{.ConstraintText}}</td>
                                 <td>{{.Description}}</td>
                             </tr>
                             {{if .Fields}}
@@ -332,7 +381,8 @@ const APIReportTemplate = `<!DOCTYPE html>
                                     </td>
                                     <td class="param-type">{{.Type}}</td>
                                     <td>-</td>
-                                    <td>-</td>
+                                    <td>{{if .Required}}<span class="required-badge">REQUIRED</span>{{else}}-{{end}}</td>
+                                    <td class="param-constraints">{{.ConstraintText}}</td>
                                     <td>{{.Description}}</td>
                                 </tr>
                                 {{end}}
@@ -352,16 +402,19 @@ const APIReportTemplate = `<!DOCTYPE html>
                             </tr>
                         </thead>
                         <tbody>
+                            {{range .Responses}}
                             <tr>
-                                <td class="response-success">{{.Response.StatusCode}}</td>
-                                <td class="param-type">{{.Response.Type}}</td>
-                                <td>{{.Response.Description}}</td>
+                                <td class="{{if .IsError}}response-error{{else}}response-success{{end}}">{{.StatusCode}}</td>
+                                <td class="param-type">{{.Type}}</td>
+                                <td>{{.Description}}</td>
                             </tr>
+                            {{end}}
                         </tbody>
                     </table>
                     
-                    {{if .Response.Fields}}
-                    <div class="section-title">Response Fields</div>
+                    {{range .Responses}}
+                    {{if .Fields}}
+                    <div class="section-title">Response Fields ({{.StatusCode}})</div>
                     <table>
                         <thead>
                             <tr>
@@ -371,7 +424,7 @@ const APIReportTemplate = `<!DOCTYPE html>
                             </tr>
                         </thead>
                         <tbody>
-                            {{range .Response.Fields}}
+                            {{range .Fields}}
                             <tr class="nested-param">
                                 <td class="param-name" style="padding-left: {{mul (sub .Depth 1) 20}}px">
                                     {{if gt (sub .Depth 1) 0}}└ {{end}}{{.Name}}
@@ -383,6 +436,7 @@ const APIReportTemplate = `<!DOCTYPE html>
                         </tbody>
                     </table>
                     {{end}}
+                    {{end}}
                 </div>
             </div>
             {{end}}
@@ -393,6 +447,47 @@ const APIReportTemplate = `<!DOCTYPE html>
             </div>
         {{end}}
 
+        {{if .CRUD.Tables}}
+        <div class="summary crud-matrix">
+            <h2>Table CRUD Matrix</h2>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Endpoint</th>
+                        <th>Handler</th>
+                        {{range .CRUD.Tables}}<th class="crud-table">{{.}}</th>{{end}}
+                    </tr>
+                </thead>
+                <tbody>
+                    {{range $row := .CRUD.Rows}}
+                    <tr>
+                        <td><span class="method-badge {{methodColor $row.Method}}">{{methodBadge $row.Method}}</span> <span class="endpoint-path">{{$row.Path}}</span></td>
+                        <td>{{$row.Controller}}.{{$row.Handler}}</td>
+                        {{range $table := $.CRUD.Tables}}<td class="crud-cell">{{index $row.Access $table}}</td>{{end}}
+                    </tr>
+                    {{end}}
+                </tbody>
+            </table>
+            <div class="section-title">Table Columns</div>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Table</th>
+                        <th>Columns</th>
+                    </tr>
+                </thead>
+                <tbody>
+                    {{range .CRUD.Tables}}
+                    <tr>
+                        <td class="param-name">{{.}}</td>
+                        <td>{{join (index $.CRUD.Columns .) ", "}}</td>
+                    </tr>
+                    {{end}}
+                </tbody>
+            </table>
+        </div>
+        {{end}}
+
         <footer>
             <p>Generated by <strong>Spec Recon</strong> v1.0.0</p>
             <p>Static Analysis for Legacy Spring Projects</p>
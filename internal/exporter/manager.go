@@ -29,6 +29,8 @@ func GetExporters(formats []string) []Exporter {
 			exporters = append(exporters, word.NewWordExporter())
 		case "openapi", "swagger", "json":
 			exporters = append(exporters, openapi.NewOpenAPIExporter())
+		case "security", "sarif":
+			exporters = append(exporters, NewSecurityExporter())
 		}
 	}
 
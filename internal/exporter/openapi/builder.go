@@ -2,8 +2,11 @@ package openapi
 
 import (
 	"encoding/json"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
 	"spec-recon/internal/analyzer"
@@ -32,6 +35,22 @@ type Operation struct {
 	Parameters  []Parameter         `json:"parameters,omitempty"`
 	RequestBody *RequestBody        `json:"requestBody,omitempty"`
 	Responses   map[string]Response `json:"responses"`
+	XSource     *SourceRef          `json:"x-source,omitempty"`
+	XHandlers   []HandlerRef        `json:"x-handlers,omitempty"`
+}
+
+// HandlerRef is an x-handlers entry: one of the handlers mapped to the same
+// path and method, selected by its params condition (params="action=save")
+type HandlerRef struct {
+	OperationID string   `json:"operationId"`
+	Summary     string   `json:"summary,omitempty"`
+	Conditions  []string `json:"conditions,omitempty"`
+}
+
+// SourceRef is the x-source extension pointing at the handler method
+type SourceRef struct {
+	File string `json:"file"`
+	Line int    `json:"line,omitempty"`
 }
 
 type Parameter struct {
@@ -52,7 +71,18 @@ type MediaType struct {
 }
 
 type Schema struct {
-	Type string `json:"type"`
+	Type      string   `json:"type"`
+	Format    string   `json:"format,omitempty"`
+	Enum      []string `json:"enum,omitempty"`
+	Pattern   string   `json:"pattern,omitempty"`
+	MinLength *int     `json:"minLength,omitempty"`
+	MaxLength *int     `json:"maxLength,omitempty"`
+	MinItems  *int     `json:"minItems,omitempty"`
+	MaxItems  *int     `json:"maxItems,omitempty"`
+	Minimum   *float64 `json:"minimum,omitempty"`
+	Maximum   *float64 `json:"maximum,omitempty"`
+
+	XDatePattern string `json:"x-date-pattern,omitempty"`
 }
 
 type Response struct {
@@ -60,6 +90,9 @@ type Response struct {
 	Content     map[string]MediaType `json:"content,omitempty"`
 }
 
+// allMethods are the operations documented for a mapping that accepts any HTTP method
+var allMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+
 // OpenAPIExporter constructs OpenAPI spec
 type OpenAPIExporter struct {
 	// Stateless
@@ -103,6 +136,23 @@ func (b *OpenAPIExporter) Export(summary *model.Summary, tree []*model.Node, cfg
 	return encoder.Encode(spec)
 }
 
+// uniqueOperationID appends a numeric suffix when an overloaded handler already
+// used the same ControllerName_MethodName, since operationId must be unique
+func uniqueOperationID(spec *OpenAPI, base string) string {
+	used := make(map[string]bool)
+	for _, item := range spec.Paths {
+		for _, op := range item {
+			used[op.OperationID] = true
+		}
+	}
+
+	id := base
+	for n := 2; used[id]; n++ {
+		id = base + "_" + strconv.Itoa(n)
+	}
+	return id
+}
+
 func (b *OpenAPIExporter) processEndpoint(spec *OpenAPI, endpoint model.EndpointDef) {
 	fullPath := endpoint.Path
 	if fullPath == "" {
@@ -114,9 +164,15 @@ func (b *OpenAPIExporter) processEndpoint(spec *OpenAPI, endpoint model.Endpoint
 		fullPath = "/" + fullPath
 	}
 
-	method := strings.ToLower(endpoint.Method)
-	if method == "" {
-		method = "get"
+	methods := endpoint.Methods
+	switch {
+	case len(methods) > 0:
+	case endpoint.Method == "ALL":
+		methods = allMethods // A mapping without a method condition accepts every method
+	case endpoint.Method != "":
+		methods = []string{endpoint.Method}
+	default:
+		methods = []string{"GET"}
 	}
 
 	// Initialize PathItem
@@ -128,12 +184,14 @@ func (b *OpenAPIExporter) processEndpoint(spec *OpenAPI, endpoint model.Endpoint
 	op := Operation{
 		Summary:     endpoint.Summary,
 		Description: endpoint.Description,
-		OperationID: endpoint.ControllerName + "_" + endpoint.MethodName,
 		Responses:   make(map[string]Response),
 	}
 	if op.Summary == "" {
 		op.Summary = endpoint.MethodName
 	}
+	if endpoint.File != "" {
+		op.XSource = &SourceRef{File: endpoint.File, Line: endpoint.Line}
+	}
 
 	// 1. Process Parameters (Query, Path, Header, Body)
 	for _, param := range endpoint.Params {
@@ -148,11 +206,7 @@ func (b *OpenAPIExporter) processEndpoint(spec *OpenAPI, endpoint model.Endpoint
 			}
 
 			op.RequestBody = &RequestBody{
-				Content: map[string]MediaType{
-					"application/json": {
-						Schema: schema,
-					},
-				},
+				Content:  mediaTypes(endpoint.Consumes, schema),
 				Required: param.Required,
 			}
 		} else {
@@ -168,55 +222,156 @@ func (b *OpenAPIExporter) processEndpoint(spec *OpenAPI, endpoint model.Endpoint
 				Name:        param.Name,
 				In:          inType,
 				Required:    param.Required,
-				Schema:      Schema{Type: b.mapType(param.Type)},
+				Schema:      b.buildSimpleSchema(param),
 				Description: param.Description,
 			})
 		}
 	}
+	applyParamConditions(op.Parameters, endpoint.ParamConditions)
 
-	// 2. Process Response
-	responseDesc := endpoint.Response.Description
-	if responseDesc == "" {
-		responseDesc = "Successful response"
+	// 2. Process Responses, one per status code
+	responses := endpoint.Responses
+	if len(responses) == 0 {
+		responses = []model.ResponseDef{{StatusCode: 200}}
 	}
+	for _, response := range responses {
+		statusCode := "200"
+		if response.StatusCode != 0 {
+			statusCode = strconv.Itoa(response.StatusCode)
+		}
+
+		respObj := Response{
+			Description: response.Description,
+		}
+		if respObj.Description == "" && statusCode != "200" {
+			respObj.Description = model.HTTPStatusText(response.StatusCode)
+		}
+		if respObj.Description == "" {
+			respObj.Description = "Successful response"
+		}
 
-	respObj := Response{
-		Description: responseDesc,
+		// Build properties for response schema
+		if len(response.Fields) > 0 {
+			schema := b.buildComplexSchema(response.Fields)
+			respObj.Content = mediaTypes(endpoint.Produces, schema)
+		} else if response.Type != "" && response.Type != "void" {
+			// Simple response type (String, Integer)
+			respObj.Content = mediaTypes(endpoint.Produces, map[string]interface{}{
+				"type": b.mapType(response.Type),
+			})
+		}
+
+		op.Responses[statusCode] = respObj
 	}
 
-	// Build properties for response schema
-	if len(endpoint.Response.Fields) > 0 {
-		schema := b.buildComplexSchema(endpoint.Response.Fields)
-		respObj.Content = map[string]MediaType{
-			"application/json": {
-				Schema: schema,
-			},
+	for _, method := range methods {
+		methodOp := op
+		methodOp.OperationID = uniqueOperationID(spec, endpoint.ControllerName+"_"+endpoint.MethodName)
+		if len(endpoint.ParamConditions) > 0 {
+			methodOp.XHandlers = []HandlerRef{{OperationID: methodOp.OperationID, Summary: methodOp.Summary, Conditions: endpoint.ParamConditions}}
 		}
-	} else if endpoint.Response.Type != "void" {
-		// Simple response type (String, Integer)
-		respObj.Content = map[string]MediaType{
-			"application/json": {
-				Schema: map[string]interface{}{
-					"type": b.mapType(endpoint.Response.Type),
-				},
-			},
+		key := strings.ToLower(method)
+		if existing, ok := spec.Paths[fullPath][key]; ok {
+			// Handlers told apart only by params="action=save" share the operation
+			methodOp = mergeOperations(existing, methodOp)
 		}
+		spec.Paths[fullPath][key] = methodOp
 	}
+}
 
-	statusCode := "200"
-	if endpoint.Response.StatusCode != 0 {
-		statusCode = strings.TrimSpace(strings.Split(strings.Trim(string(endpoint.Response.StatusCode), "[]"), " ")[0]) // Just in case, but integer to string
-		// Wait, StatusCode is int
-		if endpoint.Response.StatusCode == 204 {
-			statusCode = "204"
-		} else {
-			statusCode = "200" // Simplify for now
+// applyParamConditions restricts the query parameters a params condition
+// fixes ("action=save") to that value
+func applyParamConditions(params []Parameter, conditions []string) {
+	for _, condition := range conditions {
+		name, value, ok := strings.Cut(condition, "=")
+		if !ok || strings.HasSuffix(name, "!") {
+			continue
+		}
+		for i := range params {
+			if params[i].In == "query" && params[i].Name == strings.TrimSpace(name) {
+				params[i].Required = true
+				params[i].Schema.Enum = []string{strings.TrimSpace(value)}
+			}
+		}
+	}
+}
+
+// mergeOperations documents a handler mapped to the same path and method as
+// an earlier one: parameters only some handlers require become optional, the
+// values of condition parameters are combined, and each handler is listed in
+// x-handlers. The earlier handler keeps the operation ID and summary
+func mergeOperations(existing, op Operation) Operation {
+	if len(existing.XHandlers) == 0 {
+		existing.XHandlers = []HandlerRef{{OperationID: existing.OperationID, Summary: existing.Summary}}
+	}
+	existing.XHandlers = append(existing.XHandlers, op.XHandlers...)
+	if len(op.XHandlers) == 0 {
+		existing.XHandlers = append(existing.XHandlers, HandlerRef{OperationID: op.OperationID, Summary: op.Summary})
+	}
+
+	params := make([]Parameter, 0, len(existing.Parameters)+len(op.Parameters))
+	for _, param := range existing.Parameters {
+		other := findParameter(op.Parameters, param.Name, param.In)
+		switch {
+		case other == nil:
+			param.Required = param.In == "path"
+		case len(param.Schema.Enum) == 0 || len(other.Schema.Enum) == 0:
+			param.Required = param.Required && other.Required
+			param.Schema.Enum = nil
+		default:
+			param.Required = param.Required && other.Required
+			param.Schema.Enum = slices.Clone(param.Schema.Enum)
+			for _, value := range other.Schema.Enum {
+				if !slices.Contains(param.Schema.Enum, value) {
+					param.Schema.Enum = append(param.Schema.Enum, value)
+				}
+			}
+		}
+		params = append(params, param)
+	}
+	for _, param := range op.Parameters {
+		if findParameter(existing.Parameters, param.Name, param.In) == nil {
+			param.Required = param.In == "path"
+			params = append(params, param)
 		}
 	}
+	existing.Parameters = params
 
-	op.Responses[statusCode] = respObj
+	if existing.RequestBody == nil && op.RequestBody != nil {
+		body := *op.RequestBody
+		body.Required = false
+		existing.RequestBody = &body
+	}
+	existing.Responses = maps.Clone(existing.Responses)
+	for status, response := range op.Responses {
+		if _, ok := existing.Responses[status]; !ok {
+			existing.Responses[status] = response
+		}
+	}
+	return existing
+}
 
-	spec.Paths[fullPath][method] = op
+// findParameter returns the parameter with a name and location, or nil
+func findParameter(params []Parameter, name, in string) *Parameter {
+	for i := range params {
+		if params[i].Name == name && params[i].In == in {
+			return &params[i]
+		}
+	}
+	return nil
+}
+
+// mediaTypes maps each declared media type (consumes or produces) to the
+// schema, defaulting to application/json
+func mediaTypes(types []string, schema interface{}) map[string]MediaType {
+	if len(types) == 0 {
+		types = []string{"application/json"}
+	}
+	content := make(map[string]MediaType, len(types))
+	for _, mediaType := range types {
+		content[mediaType] = MediaType{Schema: schema}
+	}
+	return content
 }
 
 // buildComplexSchema reconstructs the JSON schema from a flattened list of depth-aware ParamDefs
@@ -248,6 +403,7 @@ func (b *OpenAPIExporter) buildComplexSchema(fields []model.ParamDef) map[string
 
 		// Determine where to attach this field (properties vs items)
 		var targetProps map[string]interface{}
+		targetObject := parentSchema
 
 		parentType, _ := parentSchema["type"].(string)
 
@@ -269,6 +425,7 @@ func (b *OpenAPIExporter) buildComplexSchema(fields []model.ParamDef) map[string
 				itemsSchema["properties"] = make(map[string]interface{})
 			}
 			targetProps = itemsSchema["properties"].(map[string]interface{})
+			targetObject = itemsSchema
 		} else {
 			// Parent is Object -> Attach to "properties"
 			if _, hasProps := parentSchema["properties"]; !hasProps {
@@ -294,8 +451,14 @@ func (b *OpenAPIExporter) buildComplexSchema(fields []model.ParamDef) map[string
 			}
 		}
 
+		b.addConstraints(fieldSchema, field)
+
 		// Attach
 		targetProps[field.Name] = fieldSchema
+		if field.Required {
+			required, _ := targetObject["required"].([]string)
+			targetObject["required"] = append(required, field.Name)
+		}
 
 		// Update Path Map for next depth
 		pathMap[field.Depth] = fieldSchema
@@ -312,10 +475,74 @@ func (b *OpenAPIExporter) isCollection(typeName string) bool {
 
 // buildParamSchema builds a simple schema for a top-level parameter
 func (b *OpenAPIExporter) buildParamSchema(param model.ParamDef) map[string]interface{} {
-	return map[string]interface{}{
+	schema := map[string]interface{}{
 		"type":        b.mapType(param.Type),
 		"description": param.Description,
 	}
+	b.addConstraints(schema, param)
+	return schema
+}
+
+// buildSimpleSchema builds the schema of a query, path or header parameter
+func (b *OpenAPIExporter) buildSimpleSchema(param model.ParamDef) Schema {
+	schema := Schema{
+		Type:    b.mapType(param.Type),
+		Format:  param.Format,
+		Pattern: param.Pattern,
+		Minimum: param.Minimum,
+		Maximum: param.Maximum,
+	}
+	if schema.Type == "array" {
+		schema.MinItems, schema.MaxItems = param.MinLength, param.MaxLength
+	} else {
+		schema.MinLength, schema.MaxLength = param.MinLength, param.MaxLength
+	}
+	if param.DatePattern != "" {
+		schema.Type, schema.Format = "string", dateFormat(param.DatePattern)
+		schema.XDatePattern = param.DatePattern
+	}
+	return schema
+}
+
+// addConstraints adds the validation keywords of a parameter to its schema
+// (@Size bounds the items of an array, the characters of anything else)
+func (b *OpenAPIExporter) addConstraints(schema map[string]interface{}, param model.ParamDef) {
+	minKey, maxKey := "minLength", "maxLength"
+	if schema["type"] == "array" {
+		minKey, maxKey = "minItems", "maxItems"
+	}
+	if param.MinLength != nil {
+		schema[minKey] = *param.MinLength
+	}
+	if param.MaxLength != nil {
+		schema[maxKey] = *param.MaxLength
+	}
+	if param.Minimum != nil {
+		schema["minimum"] = *param.Minimum
+	}
+	if param.Maximum != nil {
+		schema["maximum"] = *param.Maximum
+	}
+	if param.Pattern != "" {
+		schema["pattern"] = param.Pattern
+	}
+	if param.Format != "" {
+		schema["format"] = param.Format
+	}
+	if param.DatePattern != "" {
+		schema["type"] = "string"
+		schema["format"] = dateFormat(param.DatePattern)
+		schema["x-date-pattern"] = param.DatePattern
+	}
+}
+
+// dateFormat returns the JSON Schema format of values written with a date
+// pattern: "date-time" when the pattern has time fields, else "date"
+func dateFormat(pattern string) string {
+	if strings.ContainsAny(pattern, "HhKkmsSa") {
+		return "date-time"
+	}
+	return "date"
 }
 
 // mapType maps Java types to JSON Schema types
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"spec-recon/internal/analyzer"
@@ -99,3 +101,87 @@ func TestOpenAPIExport(t *testing.T) {
 		t.Error("No paths in OpenAPI spec - filtering may be too aggressive")
 	}
 }
+
+// TestProcessEndpointConditions verifies one operation per accepted method,
+// the consumes/produces media types, and that handlers told apart by a params
+// condition share the operation of their path, with the condition values as
+// the parameter's enum and each handler in x-handlers
+func TestProcessEndpointConditions(t *testing.T) {
+	spec := OpenAPI{Paths: make(map[string]PathItem)}
+	exporter := NewOpenAPIExporter()
+	body := model.ParamDef{Name: "form", Type: "BoardForm", In: "Body", Required: true}
+	action := func(value string) model.ParamDef {
+		return model.ParamDef{Name: "action", Type: "String", In: "Query", Required: true, Description: "Mapping condition: action=" + value}
+	}
+	for _, endpoint := range []model.EndpointDef{
+		{Method: "GET, POST", Methods: []string{"GET", "POST"}, Path: "/board/edit.do", ControllerName: "BoardController", MethodName: "save",
+			ParamConditions: []string{"action=save"}, Consumes: []string{"application/x-www-form-urlencoded"},
+			Params: []model.ParamDef{body, action("save")}, Responses: []model.ResponseDef{{Type: "String"}}},
+		{Method: "ALL", Path: "/board/edit.do", ControllerName: "BoardController", MethodName: "delete",
+			ParamConditions: []string{"action=delete"}, Params: []model.ParamDef{action("delete")},
+			Responses: []model.ResponseDef{{Type: "void"}}},
+		{Method: "GET", Methods: []string{"GET"}, Path: "/board/export", ControllerName: "BoardController", MethodName: "export",
+			Produces: []string{"text/csv"}, Responses: []model.ResponseDef{{Type: "String"}}},
+	} {
+		exporter.processEndpoint(&spec, endpoint)
+	}
+
+	for path := range spec.Paths {
+		if strings.Contains(path, "?") {
+			t.Errorf("Path keys must not carry a query string, got %q", path)
+		}
+	}
+	edit := spec.Paths["/board/edit.do"]
+	if len(edit) != len(allMethods) {
+		t.Fatalf("edit.do: expected an operation per method, got %d", len(edit))
+	}
+	post := edit["post"]
+	if post.OperationID == edit["get"].OperationID {
+		t.Errorf("edit.do: expected distinct GET and POST operation IDs, got %q", post.OperationID)
+	}
+	if _, ok := post.RequestBody.Content["application/x-www-form-urlencoded"]; !ok {
+		t.Errorf("save: expected a form request body, got %+v", post.RequestBody.Content)
+	}
+	if len(post.Parameters) != 1 || !post.Parameters[0].Required || !reflect.DeepEqual(post.Parameters[0].Schema.Enum, []string{"save", "delete"}) {
+		t.Errorf("POST: expected a required action parameter with enum [save delete], got %+v", post.Parameters)
+	}
+	if len(post.XHandlers) != 2 || post.XHandlers[1].OperationID != "BoardController_delete" ||
+		!reflect.DeepEqual(post.XHandlers[1].Conditions, []string{"action=delete"}) {
+		t.Errorf("POST: expected both handlers in x-handlers, got %+v", post.XHandlers)
+	}
+	if put := edit["put"]; put.RequestBody != nil || !reflect.DeepEqual(put.Parameters[0].Schema.Enum, []string{"delete"}) {
+		t.Errorf("PUT: expected only the delete handler, got %+v", put)
+	}
+	if _, ok := spec.Paths["/board/export"]["get"].Responses["200"].Content["text/csv"]; !ok {
+		t.Errorf("export: expected a text/csv response, got %+v", spec.Paths["/board/export"]["get"].Responses)
+	}
+}
+
+// TestBuildComplexSchemaConstraints verifies the validation keywords, the
+// date formats and the required lists of a request body schema
+func TestBuildComplexSchemaConstraints(t *testing.T) {
+	two, twenty := 2, 20
+	eighteen := 18.0
+	schema := NewOpenAPIExporter().buildComplexSchema([]model.ParamDef{
+		{Name: "name", Type: "String", Depth: 1, Required: true, MinLength: &two, MaxLength: &twenty},
+		{Name: "age", Type: "Integer", Depth: 1, Minimum: &eighteen},
+		{Name: "tags", Type: "List<String>", Depth: 1, MaxLength: &twenty},
+		{Name: "address", Type: "AddressDto", Depth: 1, Required: true},
+		{Name: "zip", Type: "String", Depth: 2, Required: true, Pattern: "^[0-9]{5}$"},
+		{Name: "birthday", Type: "LocalDate", Depth: 1, DatePattern: "yyyy-MM-dd"},
+		{Name: "createdAt", Type: "LocalDateTime", Depth: 1, DatePattern: "yyyy-MM-dd HH:mm:ss"},
+	})
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"properties":{"address":{"properties":{"zip":{"pattern":"^[0-9]{5}$","type":"string"}},"required":["zip"],"type":"object"},` +
+		`"age":{"minimum":18,"type":"integer"},"birthday":{"format":"date","type":"string","x-date-pattern":"yyyy-MM-dd"},` +
+		`"createdAt":{"format":"date-time","type":"string","x-date-pattern":"yyyy-MM-dd HH:mm:ss"},` +
+		`"name":{"maxLength":20,"minLength":2,"type":"string"},` +
+		`"tags":{"items":{"type":"string"},"maxItems":20,"type":"array"}},"required":["name","address"],"type":"object"}`
+	if string(data) != want {
+		t.Errorf("Unexpected schema:\ngot  %s\nwant %s", data, want)
+	}
+}
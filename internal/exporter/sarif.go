@@ -0,0 +1,163 @@
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"spec-recon/internal/model"
+)
+
+// SARIF 2.1.0 log, limited to the properties the injection report uses
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	Help             sarifMessage `json:"help"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// sarifRules describes each finding kind as a SARIF rule
+var sarifRules = []struct {
+	kind string
+	rule sarifRule
+}{
+	{model.FindingSubstitution, sarifRule{
+		ID:               "SQLI001",
+		Name:             "MyBatisStringSubstitution",
+		ShortDescription: sarifMessage{Text: "MyBatis statement substitutes a value into the SQL text with ${}"},
+		Help:             sarifMessage{Text: "Bind values with #{} instead. When an identifier (column, table, sort order) must vary, map it from a fixed list of allowed values."},
+	}},
+	{model.FindingStringBuilt, sarifRule{
+		ID:               "SQLI002",
+		Name:             "StringBuiltJdbcSql",
+		ShortDescription: sarifMessage{Text: "SQL text passed to JdbcTemplate or a JDBC statement is built by concatenation"},
+		Help:             sarifMessage{Text: "Use ? or named parameters and pass the values as arguments. Keep only constants in the SQL text."},
+	}},
+}
+
+// sarifLevels maps finding risk to SARIF result levels
+var sarifLevels = map[string]string{
+	model.RiskHigh:   "error",
+	model.RiskMedium: "warning",
+	model.RiskLow:    "note",
+}
+
+// WriteSARIF writes injection findings as a SARIF 2.1.0 log
+func WriteSARIF(findings []model.InjectionFinding, outputFile string) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:    "spec-recon",
+			Version: "1.0.0",
+		}},
+		Results: make([]sarifResult, 0, len(findings)),
+	}
+	ruleIDs := make(map[string]string)
+	for _, r := range sarifRules {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, r.rule)
+		ruleIDs[r.kind] = r.rule.ID
+	}
+
+	for _, finding := range findings {
+		result := sarifResult{
+			RuleID:  ruleIDs[finding.Kind],
+			Level:   sarifLevels[finding.Risk],
+			Message: sarifMessage{Text: sarifMessageText(finding)},
+			Properties: map[string]interface{}{
+				"risk":      finding.Risk,
+				"sink":      finding.Sink,
+				"values":    finding.Expressions,
+				"endpoints": finding.Endpoints,
+				"tracedTo":  finding.TracedTo,
+			},
+		}
+		if finding.File != "" {
+			location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: finding.File},
+			}}
+			if finding.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: finding.Line}
+			}
+			result.Locations = []sarifLocation{location}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+// sarifMessageText summarizes a finding in one sentence
+func sarifMessageText(finding model.InjectionFinding) string {
+	message := fmt.Sprintf("%s in %s builds SQL from %s", finding.Kind, finding.Location, strings.Join(finding.Expressions, ", "))
+	switch {
+	case len(finding.TracedTo) > 0:
+		message += "; the value comes from request parameter " + strings.Join(finding.TracedTo, ", ")
+	case len(finding.Endpoints) > 0:
+		message += "; reachable from " + strings.Join(finding.Endpoints, ", ")
+	default:
+		message += "; not reachable from any endpoint"
+	}
+	return message
+}
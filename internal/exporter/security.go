@@ -0,0 +1,84 @@
+package exporter
+
+import (
+	"path/filepath"
+	"strings"
+
+	"spec-recon/internal/analyzer"
+	"spec-recon/internal/config"
+	"spec-recon/internal/model"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// SecurityExporter writes the SQL injection report: an Excel workbook for
+// review and a SARIF log for code scanning tools, next to the main report
+type SecurityExporter struct {
+	// Stateless
+}
+
+// NewSecurityExporter creates a new SecurityExporter
+func NewSecurityExporter() *SecurityExporter {
+	return &SecurityExporter{}
+}
+
+// Export generates sql_injection.xlsx and sql_injection.sarif
+func (e *SecurityExporter) Export(summary *model.Summary, tree []*model.Node, cfg *config.Config) error {
+	findings := analyzer.BuildInjectionFindings(tree, summary.ClassMap, summary.FieldTypeMap)
+	dir := filepath.Dir(cfg.GetOutputPath())
+
+	if err := e.writeWorkbook(findings, filepath.Join(dir, "sql_injection.xlsx")); err != nil {
+		return err
+	}
+	return WriteSARIF(findings, filepath.Join(dir, "sql_injection.sarif"))
+}
+
+// writeWorkbook writes the findings to a single "SQL Injection" sheet,
+// highest risk first; High findings are highlighted
+func (e *SecurityExporter) writeWorkbook(findings []model.InjectionFinding, outputFile string) error {
+	f := excelize.NewFile()
+	styler, err := NewStyler(f)
+	if err != nil {
+		return err
+	}
+	rows := NewExcelExporter()
+
+	sheet := "SQL Injection"
+	f.NewSheet(sheet)
+	headers := []string{"Risk", "Kind", "Location", "Source", "Sink", "Values", "Endpoints", "Traced To"}
+	rows.writeRow(f, sheet, 1, headers, styler.HeaderStyle)
+	f.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	})
+
+	for i, finding := range findings {
+		style := styler.DefaultStyle
+		if finding.Risk == model.RiskHigh {
+			style = styler.SQLStyle
+		}
+		rows.writeRow(f, sheet, i+2, []string{
+			finding.Risk,
+			finding.Kind,
+			finding.Location,
+			finding.Source(),
+			finding.Sink,
+			strings.Join(finding.Expressions, "\n"),
+			strings.Join(finding.Endpoints, "\n"),
+			strings.Join(finding.TracedTo, "\n"),
+		}, style)
+	}
+
+	// Adjust column widths
+	f.SetColWidth(sheet, "A", "A", 10) // Risk
+	f.SetColWidth(sheet, "B", "B", 24) // Kind
+	f.SetColWidth(sheet, "C", "E", 45) // Location / Source / Sink
+	f.SetColWidth(sheet, "F", "H", 40) // Values / Endpoints / Traced To
+
+	if idx, err := f.GetSheetIndex("Sheet1"); err == nil && idx != -1 {
+		f.DeleteSheet("Sheet1")
+	}
+	return f.SaveAs(outputFile)
+}
@@ -0,0 +1,66 @@
+package exporter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"spec-recon/internal/model"
+)
+
+// TestWriteSARIF verifies rule IDs, levels and locations of the SARIF log
+func TestWriteSARIF(t *testing.T) {
+	findings := []model.InjectionFinding{
+		{
+			Kind:        model.FindingSubstitution,
+			Risk:        model.RiskHigh,
+			Location:    "com.company.UserMapper.selectList",
+			File:        "src/main/resources/mapper/UserMapper.xml",
+			Line:        12,
+			Sink:        "com.company.UserMapper.selectList",
+			Expressions: []string{"${sort}"},
+			Endpoints:   []string{"GET /users"},
+			TracedTo:    []string{"sort (GET /users)"},
+		},
+		{
+			Kind:        model.FindingStringBuilt,
+			Risk:        model.RiskLow,
+			Location:    "com.company.UserDao.search(String)",
+			Sink:        "jdbcTemplate.query",
+			Expressions: []string{"name"},
+		},
+	}
+
+	outputFile := filepath.Join(t.TempDir(), "sql_injection.sarif")
+	if err := WriteSARIF(findings, outputFile); err != nil {
+		t.Fatalf("WriteSARIF failed: %v", err)
+	}
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read SARIF log: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(content, &log); err != nil {
+		t.Fatalf("SARIF log is not valid JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Tool.Driver.Rules) != 2 {
+		t.Fatalf("Unexpected SARIF structure: %+v", log)
+	}
+
+	results := log.Runs[0].Results
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].RuleID != "SQLI001" || results[0].Level != "error" {
+		t.Errorf("Unexpected first result: %+v", results[0])
+	}
+	if loc := results[0].Locations; len(loc) != 1 || loc[0].PhysicalLocation.ArtifactLocation.URI != findings[0].File ||
+		loc[0].PhysicalLocation.Region == nil || loc[0].PhysicalLocation.Region.StartLine != 12 {
+		t.Errorf("Unexpected location: %+v", loc)
+	}
+	if results[1].RuleID != "SQLI002" || results[1].Level != "note" || len(results[1].Locations) != 0 {
+		t.Errorf("Unexpected second result: %+v", results[1])
+	}
+}
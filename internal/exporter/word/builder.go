@@ -121,10 +121,25 @@ func buildEndpointText(sb *strings.Builder, endpoint *model.EndpointDef) {
 	// Endpoint Header
 	sb.WriteString(fmt.Sprintf("[%s] %s\n", endpoint.Method, endpoint.Path))
 	sb.WriteString(fmt.Sprintf("Controller: %s.%s\n", endpoint.ControllerName, endpoint.MethodName))
+	if source := endpoint.Source(); source != "" {
+		sb.WriteString(fmt.Sprintf("Source: %s\n", source))
+	}
 
 	if endpoint.Summary != "" {
 		sb.WriteString(fmt.Sprintf("Summary: %s\n", endpoint.Summary))
 	}
+	if endpoint.Description != "" && endpoint.Description != endpoint.Summary {
+		sb.WriteString(fmt.Sprintf("Description: %s\n", endpoint.Description))
+	}
+	if len(endpoint.Consumes) > 0 {
+		sb.WriteString(fmt.Sprintf("Consumes: %s\n", strings.Join(endpoint.Consumes, ", ")))
+	}
+	if len(endpoint.Produces) > 0 {
+		sb.WriteString(fmt.Sprintf("Produces: %s\n", strings.Join(endpoint.Produces, ", ")))
+	}
+	if conditions := append(append([]string(nil), endpoint.ParamConditions...), endpoint.HeaderConditions...); len(conditions) > 0 {
+		sb.WriteString(fmt.Sprintf("Conditions: %s\n", strings.Join(conditions, ", ")))
+	}
 	sb.WriteString("\n")
 
 	// Request Parameters
@@ -153,7 +168,7 @@ func buildEndpointText(sb *strings.Builder, endpoint *model.EndpointDef) {
 				truncate(param.Type, 20),
 				truncate(param.In, 10),
 				required,
-				param.Description))
+				withConstraints(param)))
 
 			// Nested fields are now flattened in the Fields slice with proper Depth
 			// No need for separate child iteration - they're already in the main list
@@ -167,12 +182,17 @@ func buildEndpointText(sb *strings.Builder, endpoint *model.EndpointDef) {
 					}
 					fieldName := fieldIndent + fieldMarker + field.Name
 
+					fieldRequired := "-"
+					if field.Required {
+						fieldRequired = "Yes"
+					}
+
 					sb.WriteString(fmt.Sprintf("%-25s %-20s %-10s %-10s %s\n",
 						truncate(fieldName, 25),
 						truncate(field.Type, 20),
 						"-", // No "In" for nested fields
-						"-", // No "Required" for nested fields
-						field.Description))
+						fieldRequired,
+						withConstraints(field)))
 				}
 			}
 		}
@@ -183,18 +203,23 @@ func buildEndpointText(sb *strings.Builder, endpoint *model.EndpointDef) {
 	sb.WriteString("RESPONSE:\n")
 	sb.WriteString(fmt.Sprintf("%-15s %-25s %s\n", "Status Code", "Type", "Description"))
 	sb.WriteString(strings.Repeat("-", 80) + "\n")
-	sb.WriteString(fmt.Sprintf("%-15d %-25s %s\n",
-		endpoint.Response.StatusCode,
-		truncate(endpoint.Response.Type, 25),
-		endpoint.Response.Description))
-
-	// Response nested fields (DTO schema)
-	if len(endpoint.Response.Fields) > 0 {
-		sb.WriteString("\nResponse Fields:\n")
+	for _, response := range endpoint.Responses {
+		sb.WriteString(fmt.Sprintf("%-15d %-25s %s\n",
+			response.StatusCode,
+			truncate(response.Type, 25),
+			response.Description))
+	}
+
+	// Response nested fields (DTO schema), per status code
+	for _, response := range endpoint.Responses {
+		if len(response.Fields) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\nResponse Fields (%d):\n", response.StatusCode))
 		sb.WriteString(fmt.Sprintf("%-30s %-20s %s\n", "Field Name", "Type", "Description"))
 		sb.WriteString(strings.Repeat("-", 80) + "\n")
 
-		for _, field := range endpoint.Response.Fields {
+		for _, field := range response.Fields {
 			// Calculate indentation based on depth
 			indent := strings.Repeat("  ", field.Depth)
 			marker := ""
@@ -213,6 +238,18 @@ func buildEndpointText(sb *strings.Builder, endpoint *model.EndpointDef) {
 	sb.WriteString("\n")
 }
 
+// withConstraints appends a parameter's validation constraints to its description
+func withConstraints(param model.ParamDef) string {
+	constraints := param.ConstraintText()
+	if constraints == "" {
+		return param.Description
+	}
+	if param.Description == "" {
+		return "[" + constraints + "]"
+	}
+	return param.Description + " [" + constraints + "]"
+}
+
 // truncate truncates a string to a maximum length
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
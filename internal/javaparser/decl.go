@@ -0,0 +1,912 @@
+package javaparser
+
+import (
+	"fmt"
+	"strings"
+
+	"spec-recon/internal/logger"
+)
+
+// declParser is a recursive-descent parser for Java declarations (Java 8-17)
+// It builds the class/member skeleton and treats method bodies and
+// initializers as opaque, brace-balanced regions of source text
+type declParser struct {
+	src  string
+	toks []Token
+	pos  int
+}
+
+// modifierKeywords are the declaration modifiers that precede a type or member
+var modifierKeywords = map[string]bool{
+	"public": true, "protected": true, "private": true, "static": true,
+	"final": true, "abstract": true, "native": true, "synchronized": true,
+	"transient": true, "volatile": true, "strictfp": true, "default": true,
+}
+
+// primitiveTypes are the Java primitive type keywords (plus void)
+var primitiveTypes = map[string]bool{
+	"boolean": true, "byte": true, "char": true, "short": true, "int": true,
+	"long": true, "float": true, "double": true, "void": true,
+}
+
+// declHeader holds the modifiers and annotations that prefix a declaration
+type declHeader struct {
+	annotations []Annotation
+	modifiers   []string
+	start       Token // First token of the declaration (annotation or modifier)
+}
+
+func newDeclParser(src string) *declParser {
+	return &declParser{src: src, toks: Tokenize(src)}
+}
+
+// --- Token cursor ---
+
+func (p *declParser) peek() Token {
+	return p.peekN(0)
+}
+
+func (p *declParser) peekN(n int) Token {
+	if p.pos+n < len(p.toks) {
+		return p.toks[p.pos+n]
+	}
+	return p.toks[len(p.toks)-1]
+}
+
+func (p *declParser) advance() Token {
+	tok := p.peek()
+	if p.pos < len(p.toks)-1 {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *declParser) atEOF() bool {
+	return p.peek().Kind == TokenEOF
+}
+
+func (p *declParser) accept(text string) bool {
+	if p.peek().Is(text) {
+		p.advance()
+		return true
+	}
+	return false
+}
+
+func (p *declParser) expect(text string) (Token, error) {
+	tok := p.peek()
+	if !tok.Is(text) {
+		return tok, fmt.Errorf("line %d: expected %q, found %q", tok.Line, text, tok.Text)
+	}
+	return p.advance(), nil
+}
+
+func (p *declParser) expectIdent() (Token, error) {
+	tok := p.peek()
+	if tok.Kind != TokenIdent {
+		return tok, fmt.Errorf("line %d: expected identifier, found %q", tok.Line, tok.Text)
+	}
+	return p.advance(), nil
+}
+
+// prev returns the most recently consumed token
+func (p *declParser) prev() Token {
+	if p.pos == 0 {
+		return p.toks[0]
+	}
+	return p.toks[p.pos-1]
+}
+
+// text returns the source between two tokens (inclusive) with whitespace collapsed
+func (p *declParser) text(from, to Token) string {
+	if to.End <= from.Pos {
+		return ""
+	}
+	return strings.Join(strings.Fields(p.src[from.Pos:to.End]), " ")
+}
+
+// matchClose returns the index of the token closing the bracket at index open
+func (p *declParser) matchClose(open int) int {
+	opener := p.toks[open].Text
+	closer := map[string]string{"{": "}", "(": ")", "[": "]"}[opener]
+	depth := 0
+	for i := open; i < len(p.toks); i++ {
+		tok := p.toks[i]
+		if tok.Kind != TokenOperator {
+			continue
+		}
+		switch tok.Text {
+		case opener:
+			depth++
+		case closer:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(p.toks) - 1
+}
+
+// skipBalanced consumes a bracketed region starting at the current token
+func (p *declParser) skipBalanced() (open, close Token) {
+	open = p.peek()
+	end := p.matchClose(p.pos)
+	p.pos = end
+	close = p.advance()
+	return open, close
+}
+
+// skipTypeParameters consumes a <...> region, counting angle brackets only
+func (p *declParser) skipTypeParameters() string {
+	if !p.peek().Is("<") {
+		return ""
+	}
+	start := p.peek()
+	depth := 0
+	for !p.atEOF() {
+		tok := p.advance()
+		if tok.Is("<") {
+			depth++
+		} else if tok.Is(">") {
+			depth--
+			if depth == 0 {
+				return p.text(start, tok)
+			}
+		}
+	}
+	return ""
+}
+
+// --- Compilation unit ---
+
+// parseCompilationUnit parses package, imports and every top-level type
+func (p *declParser) parseCompilationUnit() (pkg string, imports, staticImports []string, types []*JavaClass) {
+	imports = []string{}
+
+	for !p.atEOF() {
+		header := p.parseHeader()
+
+		switch {
+		case p.peek().Is("package"):
+			p.advance()
+			pkg = p.parseQualifiedName()
+			p.accept(";")
+		case p.peek().Is("import"):
+			p.advance()
+			isStatic := p.accept("static")
+			name := p.parseQualifiedName()
+			if p.accept(".") {
+				p.accept("*")
+				name += ".*"
+			}
+			p.accept(";")
+			if name == "" {
+				break
+			}
+			if isStatic {
+				staticImports = append(staticImports, name)
+			} else {
+				imports = append(imports, name)
+			}
+		case p.isTypeDeclStart():
+			cls, err := p.parseTypeDecl(header)
+			if cls != nil {
+				types = append(types, cls)
+			}
+			if err != nil {
+				logger.Debug("[PARSER] Recovered from syntax error: %v", err)
+				p.syncMember()
+			}
+		default:
+			// Stray token at top level (e.g. a lone ';'); skip it
+			p.advance()
+		}
+	}
+
+	return pkg, imports, staticImports, types
+}
+
+func (p *declParser) parseQualifiedName() string {
+	var parts []string
+	for p.peek().Kind == TokenIdent {
+		parts = append(parts, p.advance().Text)
+		if !(p.peek().Is(".") && p.peekN(1).Kind == TokenIdent) {
+			break
+		}
+		p.advance()
+	}
+	return strings.Join(parts, ".")
+}
+
+// parseHeader consumes annotations and modifiers in any order
+func (p *declParser) parseHeader() declHeader {
+	header := declHeader{start: p.peek(), annotations: []Annotation{}, modifiers: []string{}}
+	for {
+		tok := p.peek()
+		switch {
+		case tok.Is("@") && !p.peekN(1).Is("interface"):
+			header.annotations = append(header.annotations, p.parseAnnotation())
+		case tok.Kind == TokenKeyword && modifierKeywords[tok.Text] && !p.isDefaultValue():
+			header.modifiers = append(header.modifiers, p.advance().Text)
+		case tok.Kind == TokenIdent && tok.Text == "sealed" && p.isModifierContext(1):
+			header.modifiers = append(header.modifiers, p.advance().Text)
+		case tok.Kind == TokenIdent && tok.Text == "non" && p.peekN(1).Is("-") && p.peekN(2).Text == "sealed":
+			p.advance()
+			p.advance()
+			p.advance()
+			header.modifiers = append(header.modifiers, "non-sealed")
+		default:
+			return header
+		}
+	}
+}
+
+// isDefaultValue distinguishes the 'default' modifier from 'default' in a switch
+// or annotation member, which never reach parseHeader in valid code
+func (p *declParser) isDefaultValue() bool {
+	return p.peek().Text == "default" && (p.peekN(1).Is(":") || p.peekN(1).Is("->"))
+}
+
+// isModifierContext reports whether the token at offset n can follow a modifier
+func (p *declParser) isModifierContext(n int) bool {
+	next := p.peekN(n)
+	if next.Kind == TokenKeyword {
+		return modifierKeywords[next.Text] || next.Text == "class" || next.Text == "interface"
+	}
+	return next.Is("@") || next.Text == "record" || next.Text == "non"
+}
+
+func (p *declParser) isTypeDeclStart() bool {
+	tok := p.peek()
+	switch {
+	case tok.Is("class"), tok.Is("interface"), tok.Is("enum"):
+		return true
+	case tok.Is("@") && p.peekN(1).Is("interface"):
+		return true
+	case tok.Kind == TokenIdent && tok.Text == "record":
+		return p.peekN(1).Kind == TokenIdent && (p.peekN(2).Is("(") || p.peekN(2).Is("<"))
+	}
+	return false
+}
+
+// --- Type declarations ---
+
+// parseTypeDecl parses class, interface, enum, record and @interface declarations
+func (p *declParser) parseTypeDecl(header declHeader) (*JavaClass, error) {
+	cls := &JavaClass{
+		JavaDoc:      header.start.Doc,
+		Annotations:  header.annotations,
+		Modifiers:    header.modifiers,
+		Fields:       []Field{},
+		Methods:      []Method{},
+		Constructors: []Method{},
+	}
+
+	kindTok := p.advance()
+	switch {
+	case kindTok.Is("@"):
+		p.advance() // interface
+		cls.Kind = KindAnnotation
+	case kindTok.Is("interface"):
+		cls.Kind = KindInterface
+	case kindTok.Is("enum"):
+		cls.Kind = KindEnum
+	case kindTok.Text == "record":
+		cls.Kind = KindRecord
+	default:
+		cls.Kind = KindClass
+	}
+
+	nameTok, err := p.expectIdent()
+	if err != nil {
+		return nil, err
+	}
+	cls.Name = nameTok.Text
+	cls.Line = nameTok.Line
+	cls.Span.Start, cls.Span.StartLine = header.start.Pos, header.start.Line
+	if len(header.annotations) == 0 && len(header.modifiers) == 0 {
+		cls.Span.Start, cls.Span.StartLine = kindTok.Pos, kindTok.Line
+	}
+
+	p.skipTypeParameters()
+
+	if cls.Kind == KindRecord {
+		if err := p.parseRecordHeader(cls); err != nil {
+			return cls, err
+		}
+	}
+
+	// Supertype clauses; each is a comma-separated type list
+	// An interface's extends list holds interfaces, a class's holds its superclass
+	for {
+		tok := p.peek()
+		if tok.Is("extends") || tok.Is("implements") || (tok.Kind == TokenIdent && tok.Text == "permits") {
+			p.advance()
+			types, err := p.parseTypeList()
+			if err != nil {
+				return cls, err
+			}
+			switch {
+			case tok.Is("extends") && cls.Kind == KindClass && len(types) > 0:
+				cls.SuperClass = types[0]
+			case tok.Is("extends"), tok.Is("implements"):
+				cls.Interfaces = append(cls.Interfaces, types...)
+			}
+			continue
+		}
+		break
+	}
+
+	if err := p.parseClassBody(cls); err != nil {
+		return cls, err
+	}
+	cls.Span.End, cls.Span.EndLine = p.prev().End, p.prev().Line
+	return cls, nil
+}
+
+// parseRecordHeader turns record components into private final fields
+func (p *declParser) parseRecordHeader(cls *JavaClass) error {
+	if _, err := p.expect("("); err != nil {
+		return err
+	}
+	params, err := p.parseParameterList()
+	if err != nil {
+		return err
+	}
+	for _, param := range params {
+		cls.Fields = append(cls.Fields, Field{
+			Name:        param.Name,
+			Type:        param.Type,
+			Annotations: param.Annotations,
+			Modifiers:   []string{"private", "final"},
+			Line:        param.Line,
+		})
+	}
+	return nil
+}
+
+func (p *declParser) parseTypeList() ([]string, error) {
+	var types []string
+	for {
+		t, err := p.parseType()
+		if err != nil {
+			return types, err
+		}
+		types = append(types, t)
+		if !p.accept(",") {
+			return types, nil
+		}
+	}
+}
+
+// parseClassBody parses { members } including enum constants
+func (p *declParser) parseClassBody(cls *JavaClass) error {
+	if _, err := p.expect("{"); err != nil {
+		return err
+	}
+
+	if cls.Kind == KindEnum {
+		p.parseEnumConstants(cls)
+	}
+
+	for !p.atEOF() {
+		if p.accept("}") {
+			return nil
+		}
+		if err := p.parseMember(cls); err != nil {
+			logger.Debug("[PARSER] %s: skipping malformed member: %v", cls.Name, err)
+			p.syncMember()
+		}
+	}
+	return fmt.Errorf("unexpected end of file in body of %s", cls.Name)
+}
+
+// parseEnumConstants consumes CONSTANT(args) { body }, ... up to ';' or '}'
+func (p *declParser) parseEnumConstants(cls *JavaClass) {
+	for !p.atEOF() {
+		p.parseHeader() // Constants may carry annotations
+		tok := p.peek()
+		if tok.Kind != TokenIdent {
+			break
+		}
+		cls.EnumConstants = append(cls.EnumConstants, p.advance().Text)
+		if p.peek().Is("(") {
+			p.skipBalanced()
+		}
+		if p.peek().Is("{") {
+			p.skipBalanced() // Constant-specific class body
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+	p.accept(";")
+}
+
+// parseMember parses one class body declaration
+func (p *declParser) parseMember(cls *JavaClass) error {
+	if p.accept(";") {
+		return nil
+	}
+
+	// Instance or static initializer block
+	if p.peek().Is("{") || (p.peek().Is("static") && p.peekN(1).Is("{")) {
+		p.accept("static")
+		p.skipBalanced()
+		return nil
+	}
+
+	header := p.parseHeader()
+
+	if p.isTypeDeclStart() {
+		inner, err := p.parseTypeDecl(header)
+		if inner != nil {
+			cls.InnerClasses = append(cls.InnerClasses, inner)
+		}
+		return err
+	}
+
+	typeParams := p.skipTypeParameters()
+
+	// Constructor: Name ( ... )   or compact record constructor: Name { ... }
+	if p.peek().Kind == TokenIdent && p.peek().Text == cls.Name && (p.peekN(1).Is("(") || p.peekN(1).Is("{")) {
+		nameTok := p.advance()
+		ctor := Method{Name: nameTok.Text, Annotations: header.annotations, Modifiers: header.modifiers, TypeParameters: typeParams, JavaDoc: header.start.Doc}
+		if p.peek().Is("{") {
+			ctor.Parameters = []Parameter{}
+			ctor.ParamsList = []string{}
+		} else if err := p.parseMethodRest(&ctor); err != nil {
+			return err
+		}
+		if err := p.parseMethodBody(&ctor); err != nil {
+			return err
+		}
+		p.finishMember(&ctor.Span, &ctor.Line, header, nameTok)
+		cls.Constructors = append(cls.Constructors, ctor)
+		return nil
+	}
+
+	typeStart := p.peek()
+	memberType, err := p.parseType()
+	if err != nil {
+		return err
+	}
+	nameTok, err := p.expectIdent()
+	if err != nil {
+		return err
+	}
+
+	if p.peek().Is("(") {
+		method := Method{
+			Name:           nameTok.Text,
+			ReturnType:     memberType,
+			Annotations:    header.annotations,
+			Modifiers:      header.modifiers,
+			TypeParameters: typeParams,
+			JavaDoc:        header.start.Doc,
+		}
+		if err := p.parseMethodRest(&method); err != nil {
+			return err
+		}
+		// Legacy array syntax: int foo()[]
+		for p.peek().Is("[") && p.peekN(1).Is("]") {
+			p.advance()
+			p.advance()
+			method.ReturnType += "[]"
+		}
+		if err := p.parseThrows(&method); err != nil {
+			return err
+		}
+		if p.accept("default") {
+			p.skipExpression() // Annotation member default value
+		}
+		if err := p.parseMethodBody(&method); err != nil {
+			return err
+		}
+		p.finishMember(&method.Span, &method.Line, header, nameTok)
+		cls.Methods = append(cls.Methods, method)
+		return nil
+	}
+
+	// Field declaration: Type a [= x], b [= y];
+	if len(header.annotations) == 0 && len(header.modifiers) == 0 {
+		header.start = typeStart
+	}
+	for {
+		field := Field{
+			Name:        nameTok.Text,
+			Type:        memberType,
+			Annotations: header.annotations,
+			Modifiers:   header.modifiers,
+			JavaDoc:     header.start.Doc,
+		}
+		for p.peek().Is("[") && p.peekN(1).Is("]") {
+			p.advance()
+			p.advance()
+			field.Type += "[]"
+		}
+		if p.accept("=") {
+			field.Initializer = p.skipExpression()
+		}
+		p.finishMember(&field.Span, &field.Line, header, nameTok)
+		cls.Fields = append(cls.Fields, field)
+
+		if !p.accept(",") {
+			break
+		}
+		if nameTok, err = p.expectIdent(); err != nil {
+			return err
+		}
+	}
+	_, err = p.expect(";")
+	return err
+}
+
+// finishMember records the span of a member from its header to the last consumed token
+func (p *declParser) finishMember(span *Span, line *int, header declHeader, nameTok Token) {
+	last := p.prev()
+	span.Start, span.StartLine = header.start.Pos, header.start.Line
+	if header.start.Pos > nameTok.Pos {
+		span.Start, span.StartLine = nameTok.Pos, nameTok.Line
+	}
+	span.End, span.EndLine = last.End, last.Line
+	*line = nameTok.Line
+}
+
+// parseMethodRest parses the formal parameter list of a method or constructor
+func (p *declParser) parseMethodRest(method *Method) error {
+	openTok, err := p.expect("(")
+	if err != nil {
+		return err
+	}
+	params, err := p.parseParameterList()
+	if err != nil {
+		return err
+	}
+	closeTok := p.prev()
+
+	method.Parameters = params
+	method.ParamsList = make([]string, 0, len(params))
+	for _, param := range params {
+		method.ParamsList = append(method.ParamsList, param.Raw)
+	}
+	if closeTok.Pos > openTok.End {
+		method.Params = strings.Join(strings.Fields(p.src[openTok.End:closeTok.Pos]), " ")
+	}
+
+	return p.parseThrows(method)
+}
+
+func (p *declParser) parseThrows(method *Method) error {
+	if !p.accept("throws") {
+		return nil
+	}
+	types, err := p.parseTypeList()
+	method.Throws = append(method.Throws, types...)
+	return err
+}
+
+// parseMethodBody consumes either ';' or a brace-balanced body
+func (p *declParser) parseMethodBody(method *Method) error {
+	if p.accept(";") {
+		return nil
+	}
+	if !p.peek().Is("{") {
+		tok := p.peek()
+		return fmt.Errorf("line %d: expected method body for %s, found %q", tok.Line, method.Name, tok.Text)
+	}
+	openIdx := p.pos
+	p.skipBalanced()
+	method.Body = p.codeBetween(openIdx, p.pos-1)
+	method.HasBody = true
+	method.BodyLine = p.toks[openIdx].Line
+	logger.Debug("[PARSER] Captured Body for %s: %d chars", method.Name, len(method.Body))
+	return nil
+}
+
+// codeBetween returns the source strictly between the tokens at indices from and
+// to with comments removed. Line breaks are kept so line arithmetic on the body
+// still works, and the analyzer's body heuristics never see commented-out code
+func (p *declParser) codeBetween(from, to int) string {
+	if !p.toks[to].Is("}") {
+		to = len(p.toks) - 1 // Unterminated body: take the rest of the file
+	}
+	var sb strings.Builder
+	prevEnd := p.toks[from].End
+	for i := from + 1; i <= to; i++ {
+		tok := p.toks[i]
+		gap := p.src[prevEnd:tok.Pos]
+		if strings.Contains(gap, "/") {
+			// The gap between tokens holds only whitespace and comments
+			if lines := strings.Count(gap, "\n"); lines > 0 {
+				gap = strings.Repeat("\n", lines)
+			} else {
+				gap = " "
+			}
+		}
+		sb.WriteString(gap)
+		if i < to {
+			sb.WriteString(tok.Text)
+		}
+		prevEnd = tok.End
+	}
+	return sb.String()
+}
+
+// parseParameterList parses formal parameters after '(' up to and including ')'
+func (p *declParser) parseParameterList() ([]Parameter, error) {
+	params := []Parameter{}
+	if p.accept(")") {
+		return params, nil
+	}
+	for {
+		header := p.parseHeader()
+		startTok := header.start
+		typ, err := p.parseType()
+		if err != nil {
+			return params, err
+		}
+		param := Parameter{Type: typ, Annotations: header.annotations}
+		if p.accept("...") {
+			param.Varargs = true
+			param.Type += "..."
+		}
+
+		// Receiver parameter (Foo this) has no name of its own
+		nameTok := p.peek()
+		if nameTok.Kind == TokenIdent || nameTok.Is("this") {
+			p.advance()
+			param.Name = nameTok.Text
+		} else {
+			return params, fmt.Errorf("line %d: expected parameter name, found %q", nameTok.Line, nameTok.Text)
+		}
+		for p.peek().Is("[") && p.peekN(1).Is("]") {
+			p.advance()
+			p.advance()
+			param.Type += "[]"
+		}
+		param.Line = nameTok.Line
+		param.Raw = p.text(startTok, p.prev())
+
+		if param.Name != "this" {
+			params = append(params, param)
+		}
+
+		if p.accept(",") {
+			continue
+		}
+		if _, err := p.expect(")"); err != nil {
+			return params, err
+		}
+		return params, nil
+	}
+}
+
+// --- Types ---
+
+// parseType parses a (possibly generic, qualified, array) type and returns its
+// normalized text without type-use annotations, e.g. "Map<String, List<Long>>"
+func (p *declParser) parseType() (string, error) {
+	var sb strings.Builder
+	if err := p.writeType(&sb); err != nil {
+		return sb.String(), err
+	}
+	return sb.String(), nil
+}
+
+func (p *declParser) writeType(sb *strings.Builder) error {
+	p.skipTypeAnnotations()
+
+	tok := p.peek()
+	switch {
+	case tok.Kind == TokenKeyword && primitiveTypes[tok.Text]:
+		sb.WriteString(p.advance().Text)
+	case tok.Kind == TokenIdent:
+		for {
+			sb.WriteString(p.advance().Text)
+			if p.peek().Is("<") {
+				if err := p.writeTypeArguments(sb); err != nil {
+					return err
+				}
+			}
+			if !(p.peek().Is(".") && (p.peekN(1).Kind == TokenIdent || p.peekN(1).Is("@"))) {
+				break
+			}
+			sb.WriteString(p.advance().Text)
+			p.skipTypeAnnotations()
+		}
+	default:
+		return fmt.Errorf("line %d: expected type, found %q", tok.Line, tok.Text)
+	}
+
+	for {
+		p.skipTypeAnnotations()
+		if !(p.peek().Is("[") && p.peekN(1).Is("]")) {
+			break
+		}
+		p.advance()
+		p.advance()
+		sb.WriteString("[]")
+	}
+	return nil
+}
+
+func (p *declParser) writeTypeArguments(sb *strings.Builder) error {
+	p.advance() // <
+	sb.WriteString("<")
+	if p.accept(">") {
+		sb.WriteString(">") // Diamond
+		return nil
+	}
+	for {
+		p.skipTypeAnnotations()
+		if p.accept("?") {
+			sb.WriteString("?")
+			if p.peek().Is("extends") || p.peek().Is("super") {
+				sb.WriteString(" " + p.advance().Text + " ")
+				if err := p.writeType(sb); err != nil {
+					return err
+				}
+			}
+		} else if err := p.writeType(sb); err != nil {
+			return err
+		}
+		if p.accept(",") {
+			sb.WriteString(", ")
+			continue
+		}
+		if _, err := p.expect(">"); err != nil {
+			return err
+		}
+		sb.WriteString(">")
+		return nil
+	}
+}
+
+func (p *declParser) skipTypeAnnotations() {
+	for p.peek().Is("@") && !p.peekN(1).Is("interface") {
+		p.parseAnnotation()
+	}
+}
+
+// --- Expressions (opaque) ---
+
+// skipExpression consumes a variable initializer or default value up to (but not
+// including) the ',' or ';' that ends it, and returns its source text
+// Lambdas, anonymous classes and array initializers are skipped as balanced regions
+func (p *declParser) skipExpression() string {
+	start := p.peek()
+	var last Token
+	for !p.atEOF() {
+		tok := p.peek()
+		if tok.Is(";") || tok.Is("}") {
+			break
+		}
+		if tok.Is(",") && p.startsDeclarator(1) {
+			break
+		}
+		if tok.Is("(") || tok.Is("{") || tok.Is("[") {
+			_, last = p.skipBalanced()
+			continue
+		}
+		last = p.advance()
+	}
+	if last.End <= start.Pos {
+		return ""
+	}
+	return p.text(start, last)
+}
+
+// startsDeclarator reports whether the tokens at offset n look like the next
+// declarator of a multi-variable field ("b = 1" or "b;"), as opposed to a comma
+// inside a generic initializer like new HashMap<String, Integer>()
+func (p *declParser) startsDeclarator(n int) bool {
+	if p.peekN(n).Kind != TokenIdent {
+		return false
+	}
+	next := p.peekN(n + 1)
+	return next.Is("=") || next.Is(",") || next.Is(";") || next.Is("[")
+}
+
+// syncMember skips tokens until the end of the current member so parsing can resume
+func (p *declParser) syncMember() {
+	for !p.atEOF() {
+		tok := p.peek()
+		switch {
+		case tok.Is(";"):
+			p.advance()
+			return
+		case tok.Is("}"):
+			return
+		case tok.Is("{"):
+			p.skipBalanced()
+			return
+		case tok.Is("("), tok.Is("["):
+			p.skipBalanced()
+		default:
+			p.advance()
+		}
+	}
+}
+
+// --- Annotations ---
+
+// parseAnnotation parses @Name, @Name(value) or @Name(key = value, ...)
+func (p *declParser) parseAnnotation() Annotation {
+	at := p.advance() // @
+	name := p.parseQualifiedName()
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		name = name[idx+1:]
+	}
+
+	annotation := Annotation{
+		Name:        name,
+		Attributes:  make(map[string]string),
+		Expressions: make(map[string]string),
+	}
+
+	last := p.prev()
+	if p.peek().Is("(") {
+		openIdx := p.pos
+		closeIdx := p.matchClose(openIdx)
+		p.parseAnnotationArguments(&annotation, openIdx+1, closeIdx)
+		p.pos = closeIdx
+		last = p.advance()
+	}
+	annotation.Raw = p.text(at, last)
+	return annotation
+}
+
+// parseAnnotationArguments splits the tokens between the parentheses into
+// element-value pairs. A lone value is stored under "value"
+func (p *declParser) parseAnnotationArguments(annotation *Annotation, from, to int) {
+	if from >= to {
+		return
+	}
+	depth := 0
+	elemStart := from
+	for i := from; i <= to; i++ {
+		tok := p.toks[i]
+		if i < to && tok.Kind == TokenOperator {
+			switch tok.Text {
+			case "(", "{", "[":
+				depth++
+			case ")", "}", "]":
+				depth--
+			}
+			if !(tok.Text == "," && depth == 0) {
+				continue
+			}
+		} else if i < to {
+			continue
+		}
+
+		// toks[elemStart:i] is one element
+		if i > elemStart {
+			key := "value"
+			valueStart := elemStart
+			if p.toks[elemStart].Kind == TokenIdent && i-elemStart > 2 && p.toks[elemStart+1].Is("=") {
+				key = p.toks[elemStart].Text
+				valueStart = elemStart + 2
+			}
+			annotation.Attributes[key] = annotationValueText(p.toks[valueStart:i], p.src)
+			if valueStart < i {
+				annotation.Expressions[key] = p.src[p.toks[valueStart].Pos:p.toks[i-1].End]
+			}
+		}
+		elemStart = i + 1
+	}
+}
+
+// annotationValueText renders an element value: a single string literal is
+// unquoted, anything else (constants, arrays, concatenations) is kept as source
+func annotationValueText(toks []Token, src string) string {
+	if len(toks) == 0 {
+		return ""
+	}
+	if len(toks) == 1 && (toks[0].Kind == TokenString || toks[0].Kind == TokenChar) {
+		return trimQuotes(toks[0].Text)
+	}
+	return strings.Join(strings.Fields(src[toks[0].Pos:toks[len(toks)-1].End]), " ")
+}
@@ -0,0 +1,234 @@
+package javaparser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Injection kinds describe how a dependency reaches a bean
+const (
+	InjectField       = "field"       // @Autowired / @Inject / @Resource on a field
+	InjectConstructor = "constructor" // Constructor parameter (explicit or Lombok-generated)
+	InjectSetter      = "setter"      // @Autowired / @Inject / @Resource on a setter
+)
+
+// InjectionPoint is a dependency injected into a bean
+type InjectionPoint struct {
+	Name      string // Field the dependency is stored in, e.g., "userService"
+	Type      string // Declared type, e.g., "UserService"
+	Qualifier string // Bean name from @Qualifier, @Named or @Resource(name), if any
+	Kind      string // field, constructor or setter
+	Line      int    // Line of the field or parameter
+}
+
+// stereotypeAnnotations mark classes that Spring instantiates as beans
+var stereotypeAnnotations = map[string]bool{
+	"Component": true, "Service": true, "Repository": true, "Controller": true,
+	"RestController": true, "Configuration": true, "ControllerAdvice": true,
+	"RestControllerAdvice": true, "Named": true,
+}
+
+// injectAnnotations request injection of a field or setter
+var injectAnnotations = map[string]bool{"Autowired": true, "Inject": true, "Resource": true}
+
+var fieldAssignRegex = regexp.MustCompile(`(?:this\s*\.\s*)?(\w+)\s*=\s*(\w+)\s*;`)
+
+// IsBean reports whether the class carries a Spring stereotype annotation
+func (jc *JavaClass) IsBean() bool {
+	for _, ann := range jc.Annotations {
+		if stereotypeAnnotations[ann.Name] {
+			return true
+		}
+	}
+	return false
+}
+
+// BeanName returns the bean name of a stereotype-annotated class: the annotation
+// value when given, otherwise the decapitalized class name ("userServiceImpl")
+// Returns an empty string for classes that are not beans
+func (jc *JavaClass) BeanName() string {
+	for _, ann := range jc.Annotations {
+		if stereotypeAnnotations[ann.Name] {
+			if name := ann.Attributes["value"]; name != "" {
+				return trimQuotes(name)
+			}
+			return Decapitalize(jc.Name)
+		}
+	}
+	return ""
+}
+
+// InjectionPoints returns every dependency injected into the class:
+// annotated fields, constructor parameters (the @Autowired/@Inject constructor,
+// the only constructor of a bean, or the Lombok @RequiredArgsConstructor /
+// @AllArgsConstructor fields) and annotated setters
+func (jc *JavaClass) InjectionPoints() []InjectionPoint {
+	var points []InjectionPoint
+	seen := make(map[string]bool)
+	add := func(p InjectionPoint) {
+		if p.Name == "" || seen[p.Name] {
+			return
+		}
+		seen[p.Name] = true
+		points = append(points, p)
+	}
+
+	for _, field := range jc.Fields {
+		if field.HasModifier("static") || !hasAnyAnnotation(field.Annotations, injectAnnotations) {
+			continue
+		}
+		add(InjectionPoint{
+			Name:      field.Name,
+			Type:      field.Type,
+			Qualifier: qualifierOf(field.Annotations),
+			Kind:      InjectField,
+			Line:      field.Line,
+		})
+	}
+
+	if !jc.IsBean() {
+		return points
+	}
+
+	if ctor := jc.injectionConstructor(); ctor != nil {
+		for _, param := range ctor.Parameters {
+			add(InjectionPoint{
+				Name:      assignedField(ctor.Body, param.Name),
+				Type:      param.Type,
+				Qualifier: qualifierOf(param.Annotations),
+				Kind:      InjectConstructor,
+				Line:      param.Line,
+			})
+		}
+	} else if len(jc.Constructors) == 0 {
+		requiredOnly := jc.hasAnnotation("RequiredArgsConstructor")
+		if requiredOnly || jc.hasAnnotation("AllArgsConstructor") {
+			for _, field := range jc.Fields {
+				if field.HasModifier("static") || field.Initializer != "" {
+					continue
+				}
+				if requiredOnly && !field.HasModifier("final") && !hasAnnotation(field.Annotations, "NonNull") {
+					continue
+				}
+				add(InjectionPoint{
+					Name:      field.Name,
+					Type:      field.Type,
+					Qualifier: qualifierOf(field.Annotations),
+					Kind:      InjectConstructor,
+					Line:      field.Line,
+				})
+			}
+		}
+	}
+
+	for _, method := range jc.Methods {
+		if !hasAnyAnnotation(method.Annotations, injectAnnotations) {
+			continue
+		}
+		for _, param := range method.Parameters {
+			name := assignedField(method.Body, param.Name)
+			if name == param.Name && len(method.Parameters) == 1 && strings.HasPrefix(method.Name, "set") && len(method.Name) > 3 {
+				name = Decapitalize(method.Name[3:])
+			}
+			qualifier := qualifierOf(param.Annotations)
+			if qualifier == "" && len(method.Parameters) == 1 {
+				qualifier = qualifierOf(method.Annotations)
+			}
+			add(InjectionPoint{
+				Name:      name,
+				Type:      param.Type,
+				Qualifier: qualifier,
+				Kind:      InjectSetter,
+				Line:      param.Line,
+			})
+		}
+	}
+
+	return points
+}
+
+// injectionConstructor returns the constructor Spring uses for injection:
+// the one annotated with @Autowired or @Inject, or the only declared one
+func (jc *JavaClass) injectionConstructor() *Method {
+	for i := range jc.Constructors {
+		if hasAnyAnnotation(jc.Constructors[i].Annotations, injectAnnotations) {
+			return &jc.Constructors[i]
+		}
+	}
+	if len(jc.Constructors) == 1 && len(jc.Constructors[0].Parameters) > 0 {
+		return &jc.Constructors[0]
+	}
+	return nil
+}
+
+func (jc *JavaClass) hasAnnotation(name string) bool {
+	return hasAnnotation(jc.Annotations, name)
+}
+
+// qualifierOf returns the bean name requested by @Qualifier, @Named or @Resource(name)
+func qualifierOf(annotations []Annotation) string {
+	for _, ann := range annotations {
+		switch ann.Name {
+		case "Qualifier", "Named":
+			if value := ann.Attributes["value"]; value != "" {
+				return trimQuotes(value)
+			}
+		case "Resource":
+			if name := ann.Attributes["name"]; name != "" {
+				return trimQuotes(name)
+			}
+		}
+	}
+	return ""
+}
+
+// AssignedField returns the field a parameter of a constructor or setter is
+// stored in, or the parameter name when the body has no such assignment
+func (m *Method) AssignedField(paramName string) string {
+	return assignedField(m.Body, paramName)
+}
+
+// assignedField returns the field a parameter is assigned to in a constructor
+// or setter body ("this.userService = service;"), or the parameter name itself
+func assignedField(body, paramName string) string {
+	for _, m := range fieldAssignRegex.FindAllStringSubmatch(body, -1) {
+		if m[2] == paramName {
+			return m[1]
+		}
+	}
+	return paramName
+}
+
+func hasAnnotation(annotations []Annotation, name string) bool {
+	for _, ann := range annotations {
+		if ann.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyAnnotation(annotations []Annotation, names map[string]bool) bool {
+	for _, ann := range annotations {
+		if names[ann.Name] {
+			return true
+		}
+	}
+	return false
+}
+
+// Decapitalize lowercases the first letter the way java.beans.Introspector does:
+// "UserService" -> "userService", but "URLService" stays "URLService"
+func Decapitalize(name string) string {
+	if name == "" {
+		return name
+	}
+	if len(name) > 1 && isUpper(name[0]) && isUpper(name[1]) {
+		return name
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+func isUpper(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
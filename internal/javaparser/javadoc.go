@@ -0,0 +1,152 @@
+package javaparser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// JavaDoc is a parsed documentation comment
+type JavaDoc struct {
+	Summary     string            // First sentence of the description
+	Description string            // Main description (text before the first block tag)
+	Params      map[string]string // @param name -> description
+	Return      string            // @return description
+	Throws      []ThrowsDoc       // @throws / @exception entries in declaration order
+}
+
+// ThrowsDoc documents one exception declared with @throws or @exception
+type ThrowsDoc struct {
+	Type        string // e.g., "UserNotFoundException"
+	Description string // e.g., "사용자가 존재하지 않는 경우"
+}
+
+var (
+	inlineTagRegex  = regexp.MustCompile(`\{@(?:code|literal|link|linkplain|value)\s*([^}]*)\}`)
+	paragraphRegex  = regexp.MustCompile(`(?i)<\s*(?:p|br)\s*/?>`)
+	htmlTagRegex    = regexp.MustCompile(`<[^>]+>`)
+	blockTagPattern = regexp.MustCompile(`^@(\w+)\s*(.*)$`)
+)
+
+// IsEmpty reports whether the comment carries no documentation at all
+func (d JavaDoc) IsEmpty() bool {
+	return d.Description == "" && d.Return == "" && len(d.Params) == 0 && len(d.Throws) == 0
+}
+
+// ParseJavaDoc parses a /** ... */ comment into its description and block tags
+// Unknown block tags (@author, @since, @see, ...) are ignored
+func ParseJavaDoc(comment string) JavaDoc {
+	doc := JavaDoc{Params: make(map[string]string)}
+	if comment == "" {
+		return doc
+	}
+
+	var description []string
+	var tag, tagText string
+
+	flush := func() {
+		text := strings.Join(strings.Fields(tagText), " ")
+		switch tag {
+		case "param":
+			name, rest := splitFirstWord(text)
+			if name != "" {
+				doc.Params[name] = rest
+			}
+		case "return":
+			doc.Return = text
+		case "throws", "exception":
+			name, rest := splitFirstWord(text)
+			if name != "" {
+				doc.Throws = append(doc.Throws, ThrowsDoc{Type: name, Description: rest})
+			}
+		}
+		tag, tagText = "", ""
+	}
+
+	for _, line := range javaDocLines(comment) {
+		if m := blockTagPattern.FindStringSubmatch(line); m != nil {
+			flush()
+			tag, tagText = m[1], m[2]
+			continue
+		}
+		if tag != "" {
+			tagText += " " + line
+			continue
+		}
+		description = append(description, line)
+	}
+	flush()
+
+	doc.Description = joinParagraphs(description)
+	doc.Summary = firstSentence(doc.Description)
+	return doc
+}
+
+// javaDocLines strips comment delimiters, leading asterisks, inline tags and HTML
+func javaDocLines(comment string) []string {
+	text := strings.TrimPrefix(comment, "/**")
+	text = strings.TrimSuffix(text, "*/")
+	text = inlineTagRegex.ReplaceAllStringFunc(text, func(tag string) string {
+		m := inlineTagRegex.FindStringSubmatch(tag)
+		target, label := splitFirstWord(strings.TrimSpace(m[1]))
+		if label != "" && strings.HasPrefix(tag, "{@link") {
+			return label
+		}
+		return strings.TrimSpace(target + " " + label)
+	})
+	text = paragraphRegex.ReplaceAllString(text, "\n\n")
+	text = htmlTagRegex.ReplaceAllString(text, "")
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(strings.TrimLeft(line, "*"))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// joinParagraphs joins lines into paragraphs separated by a single blank line
+func joinParagraphs(lines []string) string {
+	var paragraphs []string
+	var current []string
+	for _, line := range lines {
+		if line == "" {
+			if len(current) > 0 {
+				paragraphs = append(paragraphs, strings.Join(current, " "))
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		paragraphs = append(paragraphs, strings.Join(current, " "))
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// firstSentence returns the first sentence of the first paragraph
+// A sentence ends at '.', '。', '!' or '?' followed by whitespace or end of text
+func firstSentence(description string) string {
+	paragraph := description
+	if idx := strings.Index(paragraph, "\n"); idx >= 0 {
+		paragraph = paragraph[:idx]
+	}
+	runes := []rune(paragraph)
+	for i, r := range runes {
+		if r == '.' || r == '。' || r == '!' || r == '?' {
+			if i+1 == len(runes) || runes[i+1] == ' ' {
+				return string(runes[:i+1])
+			}
+		}
+	}
+	return paragraph
+}
+
+func splitFirstWord(text string) (string, string) {
+	text = strings.TrimSpace(text)
+	if idx := strings.IndexAny(text, " \t"); idx >= 0 {
+		return text[:idx], strings.TrimSpace(text[idx+1:])
+	}
+	return text, ""
+}
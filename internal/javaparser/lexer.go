@@ -0,0 +1,346 @@
+package javaparser
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenKind classifies a lexical token
+type TokenKind int
+
+const (
+	TokenEOF      TokenKind = iota
+	TokenIdent              // userService, String, non
+	TokenKeyword            // class, public, if, new
+	TokenString             // "text" or """text block"""
+	TokenChar               // 'c'
+	TokenNumber             // 42, 0x1F, 3.14f
+	TokenOperator           // punctuation and operators: { } ( ) . , ; @ < > ...
+)
+
+// Token is a single lexical unit of Java source
+type Token struct {
+	Kind TokenKind
+	Text string // Exact source text
+	Pos  int    // Byte offset of the first character
+	End  int    // Byte offset after the last character
+	Line int    // 1-based line number of the first character
+	Doc  string // JavaDoc comment (/** ... */) directly preceding the token, if any
+}
+
+// Is reports whether the token is the given keyword or operator
+func (t Token) Is(text string) bool {
+	return (t.Kind == TokenKeyword || t.Kind == TokenOperator) && t.Text == text
+}
+
+// javaKeywords are the reserved words of Java 17
+// Contextual keywords (record, sealed, permits, var, yield) are lexed as identifiers
+var javaKeywords = map[string]bool{
+	"abstract": true, "assert": true, "boolean": true, "break": true, "byte": true,
+	"case": true, "catch": true, "char": true, "class": true, "const": true,
+	"continue": true, "default": true, "do": true, "double": true, "else": true,
+	"enum": true, "extends": true, "final": true, "finally": true, "float": true,
+	"for": true, "goto": true, "if": true, "implements": true, "import": true,
+	"instanceof": true, "int": true, "interface": true, "long": true, "native": true,
+	"new": true, "package": true, "private": true, "protected": true, "public": true,
+	"return": true, "short": true, "static": true, "strictfp": true, "super": true,
+	"switch": true, "synchronized": true, "this": true, "throw": true, "throws": true,
+	"transient": true, "try": true, "void": true, "volatile": true, "while": true,
+	"true": true, "false": true, "null": true,
+}
+
+// multiCharOperators are the operators the parser needs to see as a single token
+// Everything else (including >> and >=) is emitted one character at a time so that
+// nested generics like List<Map<String, Object>> close cleanly
+var multiCharOperators = []string{"...", "::", "->"}
+
+// IsKeyword reports whether name is a reserved Java keyword
+func IsKeyword(name string) bool {
+	return javaKeywords[name]
+}
+
+// Tokenize splits Java source into tokens, skipping whitespace and comments
+func Tokenize(src string) []Token {
+	lx := &lexer{src: src, line: 1}
+	return lx.run()
+}
+
+type lexer struct {
+	src    string
+	pos    int
+	line   int
+	tokens []Token
+	doc    string // Pending JavaDoc, attached to the next emitted token
+}
+
+func (lx *lexer) run() []Token {
+	for {
+		lx.skipSpaceAndComments()
+		if lx.pos >= len(lx.src) {
+			break
+		}
+		lx.next()
+	}
+	lx.tokens = append(lx.tokens, Token{Kind: TokenEOF, Pos: len(lx.src), End: len(lx.src), Line: lx.line})
+	return lx.tokens
+}
+
+func (lx *lexer) skipSpaceAndComments() {
+	for lx.pos < len(lx.src) {
+		c := lx.src[lx.pos]
+		switch {
+		case c == '\n':
+			lx.line++
+			lx.pos++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f':
+			lx.pos++
+		case c == '/' && lx.peekAt(1) == '/':
+			for lx.pos < len(lx.src) && lx.src[lx.pos] != '\n' {
+				lx.pos++
+			}
+		case c == '/' && lx.peekAt(1) == '*':
+			end := strings.Index(lx.src[lx.pos+2:], "*/")
+			stop := len(lx.src)
+			if end >= 0 {
+				stop = lx.pos + 2 + end + 2
+			}
+			comment := lx.src[lx.pos:stop]
+			if strings.HasPrefix(comment, "/**") && comment != "/**/" {
+				lx.doc = comment
+			}
+			lx.line += strings.Count(comment, "\n")
+			lx.pos = stop
+		default:
+			if c >= utf8.RuneSelf {
+				r, size := utf8.DecodeRuneInString(lx.src[lx.pos:])
+				if unicode.IsSpace(r) || r == '\uFEFF' {
+					lx.pos += size
+					continue
+				}
+			}
+			return
+		}
+	}
+}
+
+func (lx *lexer) peekAt(offset int) byte {
+	if lx.pos+offset < len(lx.src) {
+		return lx.src[lx.pos+offset]
+	}
+	return 0
+}
+
+func (lx *lexer) emit(kind TokenKind, start, line int) {
+	lx.tokens = append(lx.tokens, Token{
+		Kind: kind,
+		Text: lx.src[start:lx.pos],
+		Pos:  start,
+		End:  lx.pos,
+		Line: line,
+		Doc:  lx.doc,
+	})
+	lx.doc = ""
+}
+
+func (lx *lexer) next() {
+	start, line := lx.pos, lx.line
+	c := lx.src[lx.pos]
+
+	switch {
+	case c == '"':
+		if strings.HasPrefix(lx.src[lx.pos:], `"""`) {
+			lx.scanTextBlock()
+		} else {
+			lx.scanQuoted('"')
+		}
+		lx.emit(TokenString, start, line)
+	case c == '\'':
+		lx.scanQuoted('\'')
+		lx.emit(TokenChar, start, line)
+	case isDigit(c) || (c == '.' && isDigit(lx.peekAt(1))):
+		lx.scanNumber()
+		lx.emit(TokenNumber, start, line)
+	case isIdentStart(lx.src[lx.pos:]):
+		lx.scanIdent()
+		kind := TokenIdent
+		if javaKeywords[lx.src[start:lx.pos]] {
+			kind = TokenKeyword
+		}
+		lx.emit(kind, start, line)
+	default:
+		for _, op := range multiCharOperators {
+			if strings.HasPrefix(lx.src[lx.pos:], op) {
+				lx.pos += len(op)
+				lx.emit(TokenOperator, start, line)
+				return
+			}
+		}
+		_, size := utf8.DecodeRuneInString(lx.src[lx.pos:])
+		lx.pos += size
+		lx.emit(TokenOperator, start, line)
+	}
+}
+
+// scanQuoted consumes a string or char literal, honoring escapes
+// An unterminated literal stops at the end of the line
+func (lx *lexer) scanQuoted(quote byte) {
+	lx.pos++ // opening quote
+	for lx.pos < len(lx.src) {
+		c := lx.src[lx.pos]
+		if c == '\\' {
+			lx.pos += 2
+			continue
+		}
+		if c == '\n' {
+			return
+		}
+		lx.pos++
+		if c == quote {
+			return
+		}
+	}
+	if lx.pos > len(lx.src) {
+		lx.pos = len(lx.src)
+	}
+}
+
+// scanTextBlock consumes a Java 15 text block: """ ... """
+func (lx *lexer) scanTextBlock() {
+	lx.pos += 3
+	for lx.pos < len(lx.src) {
+		if lx.src[lx.pos] == '\\' {
+			lx.pos += 2
+			continue
+		}
+		if lx.src[lx.pos] == '\n' {
+			lx.line++
+		}
+		if strings.HasPrefix(lx.src[lx.pos:], `"""`) {
+			lx.pos += 3
+			return
+		}
+		lx.pos++
+	}
+	if lx.pos > len(lx.src) {
+		lx.pos = len(lx.src)
+	}
+}
+
+func (lx *lexer) scanNumber() {
+	for lx.pos < len(lx.src) {
+		c := lx.src[lx.pos]
+		// A dot followed by a letter is member access (arr[0].length), not a fraction
+		if c == '.' && (isASCIILetter(lx.peekAt(1)) || lx.peekAt(1) == '_') {
+			return
+		}
+		if isDigit(c) || isASCIILetter(c) || c == '_' || c == '.' {
+			// Exponent sign: 1e-5, 0x1p+3
+			if (c == 'e' || c == 'E' || c == 'p' || c == 'P') && (lx.peekAt(1) == '+' || lx.peekAt(1) == '-') {
+				lx.pos += 2
+				continue
+			}
+			lx.pos++
+			continue
+		}
+		return
+	}
+}
+
+func (lx *lexer) scanIdent() {
+	for lx.pos < len(lx.src) {
+		r, size := utf8.DecodeRuneInString(lx.src[lx.pos:])
+		if r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			lx.pos += size
+			continue
+		}
+		return
+	}
+}
+
+func isIdentStart(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r == '_' || r == '$' || unicode.IsLetter(r)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// StringValue decodes a string (or char) literal token: escapes are applied and
+// text blocks lose their incidental indentation. Reports false for other text
+func StringValue(literal string) (string, bool) {
+	switch {
+	case strings.HasPrefix(literal, `"""`) && strings.HasSuffix(literal, `"""`) && len(literal) >= 6:
+		return unescapeJava(stripIndent(literal[3 : len(literal)-3])), true
+	case len(literal) >= 2 && (literal[0] == '"' || literal[0] == '\'') && literal[len(literal)-1] == literal[0]:
+		return unescapeJava(literal[1 : len(literal)-1]), true
+	}
+	return "", false
+}
+
+// stripIndent removes the incidental whitespace of a text block body: the line
+// break after the opening delimiter, the indentation common to all lines and
+// trailing spaces
+func stripIndent(body string) string {
+	if idx := strings.IndexByte(body, '\n'); idx >= 0 && strings.TrimSpace(body[:idx]) == "" {
+		body = body[idx+1:]
+	}
+	lines := strings.Split(body, "\n")
+	indent := -1
+	for i, line := range lines {
+		// The closing delimiter line counts even when blank
+		if strings.TrimSpace(line) == "" && i != len(lines)-1 {
+			continue
+		}
+		width := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent < 0 || width < indent {
+			indent = width
+		}
+	}
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			line = line[indent:]
+		}
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// unescapeJava applies the escape sequences of Java string literals; unknown
+// escapes keep the escaped character, and \<newline> joins lines
+func unescapeJava(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case 'r':
+			sb.WriteByte('\r')
+		case 's':
+			sb.WriteByte(' ')
+		case 'b':
+			sb.WriteByte('\b')
+		case 'f':
+			sb.WriteByte('\f')
+		case '\n':
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,94 @@
+package javaparser
+
+import "strings"
+
+// statementAnnotations maps the MyBatis statement annotations to statement kinds
+var statementAnnotations = map[string]string{
+	"Select": "select", "Insert": "insert", "Update": "update", "Delete": "delete",
+}
+
+// providerAnnotations maps the MyBatis SQL provider annotations to statement kinds
+var providerAnnotations = map[string]string{
+	"SelectProvider": "select", "InsertProvider": "insert", "UpdateProvider": "update", "DeleteProvider": "delete",
+}
+
+// MapperStatement is a statement declared on a mapper method with a MyBatis
+// annotation instead of a mapper XML element
+type MapperStatement struct {
+	Kind           string   // select, insert, update or delete
+	SQL            []string // Source expression of each element of the value array, joined with a space by MyBatis
+	ProviderType   string   // @...Provider class as written ("UserSqlProvider"); "" for inline SQL
+	ProviderMethod string   // @...Provider method; "" when omitted (see ComponentPool.AddAnnotatedStatements)
+	ResultMap      string   // @ResultMap ID, if any
+}
+
+// MapperStatement returns the statement a method declares with @Select,
+// @Insert, @Update, @Delete or their @...Provider forms, or nil
+func (m *Method) MapperStatement() *MapperStatement {
+	var stmt *MapperStatement
+	for _, ann := range m.Annotations {
+		if kind, ok := statementAnnotations[ann.Name]; ok {
+			stmt = &MapperStatement{Kind: kind, SQL: ArrayElements(ann.Expressions["value"])}
+		} else if kind, ok := providerAnnotations[ann.Name]; ok {
+			providerType := ann.Expressions["type"]
+			if providerType == "" {
+				providerType = ann.Expressions["value"]
+			}
+			stmt = &MapperStatement{
+				Kind:           kind,
+				ProviderType:   strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(providerType), ".class")),
+				ProviderMethod: ann.Attributes["method"],
+			}
+		}
+	}
+	if stmt == nil {
+		return nil
+	}
+	for _, ann := range m.Annotations {
+		if ann.Name == "ResultMap" {
+			if ids := ArrayElements(ann.Expressions["value"]); len(ids) > 0 {
+				stmt.ResultMap = trimQuotes(ids[0])
+			}
+		}
+	}
+	return stmt
+}
+
+// ArrayElements splits an annotation element value into the source text of
+// its elements: {"a", "b" + C} -> ["\"a\"", "\"b\" + C"]; a single value is
+// returned as the only element
+func ArrayElements(expr string) []string {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil
+	}
+	if !strings.HasPrefix(expr, "{") || !strings.HasSuffix(expr, "}") {
+		return []string{expr}
+	}
+
+	inner := expr[1 : len(expr)-1]
+	var elements []string
+	depth, start := 0, 0
+	for _, tok := range Tokenize(inner) {
+		switch {
+		case tok.Kind == TokenEOF:
+		case tok.Is("(") || tok.Is("{") || tok.Is("["):
+			depth++
+		case tok.Is(")") || tok.Is("}") || tok.Is("]"):
+			depth--
+		case tok.Is(",") && depth == 0:
+			elements = appendElement(elements, inner[start:tok.Pos])
+			start = tok.End
+		}
+	}
+	return appendElement(elements, inner[start:])
+}
+
+// appendElement appends a trimmed, non-empty array element (a trailing comma
+// leaves an empty one)
+func appendElement(elements []string, element string) []string {
+	if element = strings.TrimSpace(element); element != "" {
+		elements = append(elements, element)
+	}
+	return elements
+}
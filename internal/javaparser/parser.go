@@ -1,17 +1,44 @@
 package javaparser
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
-
-	"spec-recon/internal/logger"
 )
 
 // Annotation represents a Java annotation with its attributes
 type Annotation struct {
-	Name       string            // e.g., "RequestMapping", "Autowired"
-	Attributes map[string]string // e.g., {"value": "/users", "method": "GET"}
-	Raw        string            // Original annotation text
+	Name        string            // e.g., "RequestMapping", "Autowired"
+	Attributes  map[string]string // e.g., {"value": "/users", "method": "GET"}
+	Expressions map[string]string // Element values as source, literals quoted: {"value": "BASE + \"/users\""}
+	Raw         string            // Original annotation text
+}
+
+// Kind constants describe what sort of type declaration a JavaClass is
+const (
+	KindClass      = "class"
+	KindInterface  = "interface"
+	KindEnum       = "enum"
+	KindRecord     = "record"
+	KindAnnotation = "annotation"
+)
+
+// Span is the source range of a declaration, including its annotations
+type Span struct {
+	Start     int // Byte offset of the first character
+	End       int // Byte offset after the last character
+	StartLine int // 1-based line of the first character
+	EndLine   int // 1-based line of the last character
+}
+
+// Parameter represents a single formal parameter of a method or constructor
+type Parameter struct {
+	Name        string       // e.g., "userId"
+	Type        string       // e.g., "Long", "List<String>", "String..."
+	Annotations []Annotation // e.g., @RequestParam("id")
+	Varargs     bool         // Declared with ...
+	Raw         string       // Original declaration text, e.g., "@RequestParam(\"id\") Long userId"
+	Line        int          // Line of the parameter name
 }
 
 // Field represents a class field (for dependency injection detection)
@@ -19,387 +46,123 @@ type Field struct {
 	Name        string       // e.g., "userService"
 	Type        string       // e.g., "UserService"
 	Annotations []Annotation // e.g., @Autowired
+	Modifiers   []string     // e.g., ["private", "final"]
+	Initializer string       // Initializer expression text, if any
+	JavaDoc     string       // Field documentation
+	Span        Span         // Source range of the declaration
+	Line        int          // Line of the field name
 }
 
-// Method represents a Java method
+// Method represents a Java method or constructor
 type Method struct {
-	Name        string       // e.g., "login"
-	Params      string       // e.g., "String username, String password"
-	ParamsList  []string     // e.g., ["String username", "String password"]
-	ReturnType  string       // e.g., "ModelAndView", "ResponseEntity<String>"
-	Annotations []Annotation // e.g., @PostMapping("/login")
-	JavaDoc     string       // Method documentation
-	Body        string       // Method body (for call tracing)
+	Name           string       // e.g., "login"
+	Params         string       // e.g., "String username, String password"
+	ParamsList     []string     // e.g., ["String username", "String password"]
+	Parameters     []Parameter  // Structured parameters
+	ReturnType     string       // e.g., "ModelAndView", "ResponseEntity<String>" (empty for constructors)
+	TypeParameters string       // e.g., "<T extends Serializable>"
+	Throws         []string     // e.g., ["IOException"]
+	Annotations    []Annotation // e.g., @PostMapping("/login")
+	Modifiers      []string     // e.g., ["public", "static"]
+	JavaDoc        string       // Method documentation
+	Body           string       // Method body (for call tracing)
+	HasBody        bool         // False for abstract and interface methods
+	Span           Span         // Source range of the declaration
+	Line           int          // Line of the method name
+	BodyLine       int          // Line of the body's opening brace (0 without a body)
 }
 
-// JavaClass represents a parsed Java class
+// JavaClass represents a parsed Java type declaration
 type JavaClass struct {
-	Package     string       // e.g., "com.company.legacy"
-	Name        string       // e.g., "UserController"
-	Imports     []string     // Import statements
-	Annotations []Annotation // Class-level annotations
-	Fields      []Field      // Class fields (for @Autowired detection)
-	Methods     []Method     // Class methods
+	Package       string       // e.g., "com.company.legacy"
+	Name          string       // e.g., "UserController"
+	Kind          string       // class, interface, enum, record or annotation
+	Imports       []string     // Import statements
+	StaticImports []string     // import static members, e.g., "com.x.Consts.NAMESPACE" or "com.x.Consts.*"
+	Annotations   []Annotation // Class-level annotations
+	Modifiers     []string     // e.g., ["public", "abstract"]
+	SuperClass    string       // extends clause of a class, e.g., "AbstractService<User>"
+	Interfaces    []string     // implements clause (extends clause for interfaces)
+	Fields        []Field      // Class fields (for @Autowired detection)
+	Methods       []Method     // Class methods (constructors excluded)
+	Constructors  []Method     // Declared constructors
+	EnumConstants []string     // Constant names for enums
+	InnerClasses  []*JavaClass // Nested type declarations
+	Enclosing     string       // Enclosing types of a nested type, e.g. "Outer" for Outer.Inner
+	JavaDoc       string       // Class documentation
+	File          string       // Source file path (set by ParseJavaFileWithPath)
+	Span          Span         // Source range of the declaration
+	Line          int          // Line of the type name
 }
 
 // ParseJavaFile parses a Java source file and extracts metadata
+// The returned class is the primary type of the file: the first public
+// top-level type, or the first top-level type when none is public
 func ParseJavaFile(content string) (*JavaClass, error) {
-	javaClass := &JavaClass{
-		Imports:     []string{},
-		Annotations: []Annotation{},
-		Fields:      []Field{},
-		Methods:     []Method{},
-	}
-
-	// Extract package
-	javaClass.Package = extractPackage(content)
-
-	// Extract class name
-	javaClass.Name = extractClassName(content)
-
-	// Extract imports
-	javaClass.Imports = extractImports(content)
-
-	// Extract class-level annotations
-	javaClass.Annotations = extractClassAnnotations(content)
-
-	// Extract fields (for @Autowired detection)
-	javaClass.Fields = extractFields(content)
-
-	// Extract methods
-	javaClass.Methods = extractMethods(content)
-
-	return javaClass, nil
+	return ParseJavaFileWithPath("", content)
 }
 
-// extractPackage extracts the package declaration
-func extractPackage(content string) string {
-	packageRegex := regexp.MustCompile(`package\s+([\w.]+)\s*;`)
-	matches := packageRegex.FindStringSubmatch(content)
-	if len(matches) > 1 {
-		return matches[1]
+// ParseJavaFileWithPath parses a Java source file and records path as the
+// source file of the class and its nested types (use a repository-relative path)
+func ParseJavaFileWithPath(path, content string) (*JavaClass, error) {
+	types, err := ParseJavaTypes(path, content)
+	if err != nil {
+		return nil, err
 	}
-	return ""
-}
-
-// extractClassName extracts the class name from class declaration
-func extractClassName(content string) string {
-	// Match: public class ClassName or @Controller public class ClassName, or public interface
-	classRegex := regexp.MustCompile(`(?:public\s+)?(?:class|interface)\s+(\w+)`)
-	matches := classRegex.FindStringSubmatch(content)
-	if len(matches) > 1 {
-		return matches[1]
-	}
-	return ""
-}
 
-// extractImports extracts all import statements
-func extractImports(content string) []string {
-	imports := []string{}
-	importRegex := regexp.MustCompile(`import\s+([\w.]+)\s*;`)
-	matches := importRegex.FindAllStringSubmatch(content, -1)
-	for _, match := range matches {
-		if len(match) > 1 {
-			imports = append(imports, match[1])
+	primary := types[0]
+	for _, t := range types {
+		if t.HasModifier("public") {
+			primary = t
+			break
 		}
 	}
-	return imports
+	return primary, nil
 }
 
-// extractClassAnnotations extracts class-level annotations
-func extractClassAnnotations(content string) []Annotation {
-	annotations := []Annotation{}
+// ParseJavaTypes parses a Java source file and returns every top-level type
+// in declaration order, the primary type included; nested types are reached
+// through InnerClasses
+func ParseJavaTypes(path, content string) ([]*JavaClass, error) {
+	p := newDeclParser(content)
+	pkg, imports, staticImports, types := p.parseCompilationUnit()
 
-	// Find class declaration position
-	classRegex := regexp.MustCompile(`(?:public\s+)?(?:class|interface)\s+\w+`)
-	classMatch := classRegex.FindStringIndex(content)
-	if classMatch == nil {
-		return annotations
+	if len(types) == 0 {
+		return nil, fmt.Errorf("no type declaration found")
 	}
 
-	// Look for annotations before class declaration
-	beforeClass := content[:classMatch[0]]
-
-	// Extract annotations
-	annotationRegex := regexp.MustCompile(`@(\w+)(?:\s*\(([^)]*)\))?`)
-	matches := annotationRegex.FindAllStringSubmatch(beforeClass, -1)
-
-	for _, match := range matches {
-		annotation := Annotation{
-			Name:       match[1],
-			Attributes: make(map[string]string),
-			Raw:        match[0],
-		}
-
-		// Parse attributes if present
-		if len(match) > 2 && match[2] != "" {
-			parseAnnotationAttributes(&annotation, match[2])
-		}
-
-		annotations = append(annotations, annotation)
+	for _, t := range types {
+		setPackage(t, pkg, imports, staticImports, path)
 	}
-
-	return annotations
+	return types, nil
 }
 
-// extractFields extracts class fields (for @Autowired detection)
-func extractFields(content string) []Field {
-	fields := []Field{}
-
-	// Pattern: @Annotation? modifiers Type Name (= ...)?;
-	// Supports:
-	// - @Autowired private List<String> name;
-	// - private static final Map<String, Object> map = new HashMap<>();
-	// - protected String[] array;
-	fieldRegex := regexp.MustCompile(`(?s)(@\w+(?:\([^)]*\))?\s+)?(?:private|public|protected)(?:\s+(?:static|final|transient|volatile))*\s+([\w<>,\s?\[\]]+)\s+(\w+)\s*(?:=.*?)?;`)
-	matches := fieldRegex.FindAllStringSubmatch(content, -1)
-
-	for _, match := range matches {
-		rawType := strings.TrimSpace(match[2])
-		fieldName := match[3]
-
-		field := Field{
-			Type:        rawType,
-			Name:        fieldName,
-			Annotations: []Annotation{},
-		}
-
-		// Check if field has @Autowired annotation
-		if match[1] != "" {
-			annotationText := strings.TrimSpace(match[1])
-			if strings.HasPrefix(annotationText, "@") {
-				// Simple parsing for annotation name (removes attributes)
-				parts := strings.Split(annotationText, "(")
-				nameRaw := parts[0]
-				annotationName := strings.TrimPrefix(nameRaw, "@")
-
-				field.Annotations = append(field.Annotations, Annotation{
-					Name: annotationName,
-					Raw:  annotationText,
-				})
-			}
-		}
-
-		fields = append(fields, field)
-	}
-
-	return fields
-}
-
-// extractMethods extracts all methods from the class with their bodies
-func extractMethods(content string) []Method {
-	methods := []Method{}
-
-	// Find method signatures and match with their bodies or terminator (;)
-	// Pattern supports both { (body) and ; (abstract/interface)
-	// Groups:
-	// 1: Annotations
-	// 2: Access Modifier (optional for interfaces)
-	// 3: Return Type
-	// 4: Method Name
-	// 5: Params
-	// 6: Body Start ({) OR Terminator (;)
-
-	// Complex regex explanation:
-	// (?s) : dot matches newline
-	// ((?:@\w+(?:\([^)]*\))?\s+)*) : Group 1 - Annotations
-	// (public|private|protected)?\s* : Group 2 - Access modifier (can be empty/implicit in interface)
-	// ([\w<>,\[\]\s]+)\s+ : Group 3 - Return Type
-	// (\w+)\s* : Group 4 - Method Name
-	// \(([^)]*)\)\s* : Group 5 - Params
-	// (?:throws\s+[\w,\s]+)?\s* : throws declaration
-	// (\{|;) : Group 6 - Body start brace OR semi-colon
-
-	methodRegex := regexp.MustCompile(`(?s)((?:@\w+(?:\([^)]*\))?\s+)*)(public|private|protected)?\s*([\w<>,\[\]\s]+)\s+(\w+)\s*\(([^)]*)\)\s*(?:throws\s+[\w,\s]+)?\s*(\{|;)`)
-
-	matches := methodRegex.FindAllStringSubmatchIndex(content, -1)
-
-	for _, matchIdx := range matches {
-		// Extract matched groups using indices
-		annotationsText := content[matchIdx[2]:matchIdx[3]] // Group 1
-		// Group 2 is access modifier, we ignore it for now
-		returnType := strings.TrimSpace(content[matchIdx[6]:matchIdx[7]]) // Group 3
-		methodName := content[matchIdx[8]:matchIdx[9]]                    // Group 4
-		params := strings.TrimSpace(content[matchIdx[10]:matchIdx[11]])   // Group 5
-
-		terminatorOrBrace := content[matchIdx[12]:matchIdx[13]] // Group 6
-
-		methodBody := ""
-		if terminatorOrBrace == "{" {
-			// Find closing brace
-			bodyStart := matchIdx[13] // Position right after the opening {
-			bodyEnd := findClosingBrace(content, bodyStart)
-			if bodyEnd > bodyStart && bodyEnd <= len(content) {
-				methodBody = content[bodyStart : bodyEnd-1]
-			}
-			logger.Debug("[PARSER] Captured Body for %s: %d chars", methodName, len(methodBody))
-		}
-
-		method := Method{
-			ReturnType:  returnType,
-			Name:        methodName,
-			Params:      params,
-			Body:        methodBody, // Check later if we need to store it
-			Annotations: []Annotation{},
-		}
-
-		// Parse parameters
-		if method.Params != "" {
-			method.ParamsList = parseMethodParams(method.Params)
-		}
-
-		// Parse annotations
-		if annotationsText != "" {
-			method.Annotations = parseMethodAnnotations(annotationsText)
-		}
-
-		methods = append(methods, method)
+// QualifiedName returns the full name of the type: package, enclosing types
+// and name, e.g. "com.company.user.UserDto.Address"
+func (jc *JavaClass) QualifiedName() string {
+	name := jc.Name
+	if jc.Enclosing != "" {
+		name = jc.Enclosing + "." + name
 	}
-
-	return methods
-}
-
-// findClosingBrace finds the matching closing brace for an opening brace
-// Handles nested braces, strings, chars, and comments
-func findClosingBrace(content string, start int) int {
-	depth := 1
-	inString := false
-	inChar := false
-	inLineComment := false
-	inBlockComment := false
-	escaped := false
-
-	for i := start; i < len(content); i++ {
-		char := content[i]
-
-		if inLineComment {
-			if char == '\n' {
-				inLineComment = false
-			}
-			continue
-		}
-
-		if inBlockComment {
-			if char == '*' && i+1 < len(content) && content[i+1] == '/' {
-				inBlockComment = false
-				i++ // Skip /
-			}
-			continue
-		}
-
-		if inString {
-			if escaped {
-				escaped = false
-				continue
-			}
-			if char == '\\' {
-				escaped = true
-				continue
-			}
-			if char == '"' {
-				inString = false
-			}
-			continue
-		}
-
-		if inChar {
-			if escaped {
-				escaped = false
-				continue
-			}
-			if char == '\\' {
-				escaped = true
-				continue
-			}
-			if char == '\'' {
-				inChar = false
-			}
-			continue
-		}
-
-		// Check for comments start
-		if char == '/' && i+1 < len(content) {
-			if content[i+1] == '/' {
-				inLineComment = true
-				i++
-				continue
-			}
-			if content[i+1] == '*' {
-				inBlockComment = true
-				i++
-				continue
-			}
-		}
-
-		if char == '"' {
-			inString = true
-			escaped = false
-			continue
-		}
-
-		if char == '\'' {
-			inChar = true
-			escaped = false
-			continue
-		}
-
-		if char == '{' {
-			depth++
-		} else if char == '}' {
-			depth--
-			if depth == 0 {
-				return i + 1
-			}
-		}
+	if jc.Package == "" {
+		return name
 	}
-	return len(content)
+	return jc.Package + "." + name
 }
 
-// parseMethodAnnotations parses method-level annotations
-func parseMethodAnnotations(annotationsText string) []Annotation {
-	annotations := []Annotation{}
-
-	// Match individual annotations
-	annotationRegex := regexp.MustCompile(`@(\w+)(?:\s*\(([^)]*)\))?`)
-	matches := annotationRegex.FindAllStringSubmatch(annotationsText, -1)
-
-	for _, match := range matches {
-		annotation := Annotation{
-			Name:       match[1],
-			Attributes: make(map[string]string),
-			Raw:        match[0],
-		}
-
-		// Parse attributes if present
-		if len(match) > 2 && match[2] != "" {
-			parseAnnotationAttributes(&annotation, match[2])
+// setPackage propagates package, imports and file path into a type and its
+// nested types, and records each nested type's enclosing types
+func setPackage(jc *JavaClass, pkg string, imports, staticImports []string, path string) {
+	jc.Package = pkg
+	jc.Imports = imports
+	jc.StaticImports = staticImports
+	jc.File = path
+	for _, inner := range jc.InnerClasses {
+		inner.Enclosing = jc.Name
+		if jc.Enclosing != "" {
+			inner.Enclosing = jc.Enclosing + "." + jc.Name
 		}
-
-		annotations = append(annotations, annotation)
-	}
-
-	return annotations
-}
-
-// parseAnnotationAttributes parses annotation attributes into a map
-func parseAnnotationAttributes(annotation *Annotation, attributesText string) {
-	attributesText = strings.TrimSpace(attributesText)
-
-	// Check for simple value: @Annotation("value")
-	if strings.HasPrefix(attributesText, "\"") || strings.HasPrefix(attributesText, "'") {
-		annotation.Attributes["value"] = trimQuotes(attributesText)
-		return
-	}
-
-	// Parse key-value pairs: value = "/users", method = RequestMethod.POST
-	attrRegex := regexp.MustCompile(`(\w+)\s*=\s*([^,]+)`)
-	matches := attrRegex.FindAllStringSubmatch(attributesText, -1)
-
-	for _, match := range matches {
-		key := match[1]
-		value := strings.TrimSpace(match[2])
-		value = trimQuotes(value)
-		annotation.Attributes[key] = value
+		setPackage(inner, pkg, imports, staticImports, path)
 	}
 }
 
@@ -415,37 +178,6 @@ func trimQuotes(s string) string {
 	return s
 }
 
-func parseMethodParams(params string) []string {
-	params = strings.TrimSpace(params)
-	if params == "" {
-		return []string{}
-	}
-
-	// Split by comma, but respect generics
-	result := []string{}
-	current := ""
-	depth := 0
-
-	for _, char := range params {
-		if char == '<' {
-			depth++
-		} else if char == '>' {
-			depth--
-		} else if char == ',' && depth == 0 {
-			result = append(result, strings.TrimSpace(current))
-			current = ""
-			continue
-		}
-		current += string(char)
-	}
-
-	if current != "" {
-		result = append(result, strings.TrimSpace(current))
-	}
-
-	return result
-}
-
 func extractAnnotationValue(annotation string) string {
 	// Try simple pattern: @Annotation("value")
 	simpleRegex := regexp.MustCompile(`@\w+\s*\(\s*"([^"]+)"\s*\)`)
@@ -468,7 +200,8 @@ func extractAnnotationValue(annotation string) string {
 	return ""
 }
 
-func combineURLPaths(classPath, methodPath string) string {
+// CombineURLPaths joins a class-level and a method-level mapping path
+func CombineURLPaths(classPath, methodPath string) string {
 	classPath = strings.TrimSpace(classPath)
 	methodPath = strings.TrimSpace(methodPath)
 
@@ -486,15 +219,136 @@ func combineURLPaths(classPath, methodPath string) string {
 	return classPath + "/" + methodPath
 }
 
+// firstAttributeValue returns the first element of an annotation attribute value
+// Array values such as {"/a", "/b"} yield "/a"; string literals are unquoted
+func firstAttributeValue(value string) string {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}") {
+		value = strings.TrimSpace(value[1 : len(value)-1])
+		if idx := strings.Index(value, ","); idx >= 0 {
+			value = value[:idx]
+		}
+	}
+	return trimQuotes(value)
+}
+
+// mappingPath returns the path declared on a mapping annotation (value or path attribute)
+func mappingPath(ann Annotation) string {
+	for _, key := range []string{"value", "path"} {
+		if value, ok := ann.Attributes[key]; ok {
+			return firstAttributeValue(value)
+		}
+	}
+	// Try to extract from raw annotation
+	return extractAnnotationValue(ann.Raw)
+}
+
+// ParamTypes returns the erased parameter types, e.g. ["Map", "String..."]
+func (m *Method) ParamTypes() []string {
+	params := m.parameters()
+	types := make([]string, 0, len(params))
+	for _, param := range params {
+		types = append(types, EraseType(param.Type))
+	}
+	return types
+}
+
+// parameters returns the structured parameters, parsing Params when the
+// method was built by hand without them
+func (m *Method) parameters() []Parameter {
+	if len(m.Parameters) > 0 || strings.TrimSpace(m.Params) == "" {
+		return m.Parameters
+	}
+	p := newDeclParser(m.Params + ")")
+	params, err := p.parseParameterList()
+	if err != nil {
+		return nil
+	}
+	return params
+}
+
+// Signature returns the method name with its erased parameter types,
+// e.g. "save(UserDto,boolean)". Overloads of a method have distinct signatures
+func (m *Method) Signature() string {
+	return m.Name + "(" + strings.Join(m.ParamTypes(), ",") + ")"
+}
+
+// IsVarargs reports whether the last parameter is declared with ...
+func (m *Method) IsVarargs() bool {
+	params := m.parameters()
+	return len(params) > 0 && params[len(params)-1].Varargs
+}
+
+// EraseType strips generics and package qualifiers from a type, keeping array
+// and varargs suffixes: "java.util.List<Map<String, Object>>[]" -> "List[]"
+func EraseType(typeName string) string {
+	var sb strings.Builder
+	depth := 0
+	for _, r := range typeName {
+		switch {
+		case r == '<':
+			depth++
+		case r == '>':
+			depth--
+		case depth == 0 && r != ' ':
+			sb.WriteRune(r)
+		}
+	}
+	erased := sb.String()
+
+	suffix := ""
+	for _, s := range []string{"...", "[]"} {
+		for strings.HasSuffix(erased, s) {
+			erased = strings.TrimSuffix(erased, s)
+			suffix = s + suffix
+		}
+	}
+	if idx := strings.LastIndex(erased, "."); idx >= 0 {
+		erased = erased[idx+1:]
+	}
+	return erased + suffix
+}
+
+// HasModifier reports whether the type was declared with the given modifier
+func (jc *JavaClass) HasModifier(modifier string) bool {
+	return containsModifier(jc.Modifiers, modifier)
+}
+
+// IsConcrete reports whether the type can be instantiated (a non-abstract class, enum or record)
+func (jc *JavaClass) IsConcrete() bool {
+	switch jc.Kind {
+	case KindClass:
+		return !jc.HasModifier("abstract")
+	case KindEnum, KindRecord:
+		return true
+	}
+	return false
+}
+
+// HasModifier reports whether the method was declared with the given modifier
+func (m *Method) HasModifier(modifier string) bool {
+	return containsModifier(m.Modifiers, modifier)
+}
+
+// HasModifier reports whether the field was declared with the given modifier
+func (f *Field) HasModifier(modifier string) bool {
+	return containsModifier(f.Modifiers, modifier)
+}
+
+func containsModifier(modifiers []string, modifier string) bool {
+	for _, m := range modifiers {
+		if m == modifier {
+			return true
+		}
+	}
+	return false
+}
+
 // GetClassLevelURL returns the class-level URL from @RequestMapping
 func (jc *JavaClass) GetClassLevelURL() string {
 	for _, ann := range jc.Annotations {
 		if ann.Name == "RequestMapping" {
-			if value, ok := ann.Attributes["value"]; ok {
-				return value
-			}
-			// Try to extract from raw annotation
-			return extractAnnotationValue(ann.Raw)
+			return mappingPath(ann)
 		}
 	}
 	return ""
@@ -520,15 +374,11 @@ func (jc *JavaClass) IsService() bool {
 	return false
 }
 
-// GetInjectedServices returns the names of @Autowired service fields
+// GetInjectedServices returns the names of injected dependencies (see InjectionPoints)
 func (jc *JavaClass) GetInjectedServices() []string {
 	services := []string{}
-	for _, field := range jc.Fields {
-		for _, ann := range field.Annotations {
-			if ann.Name == "Autowired" {
-				services = append(services, field.Name)
-			}
-		}
+	for _, point := range jc.InjectionPoints() {
+		services = append(services, point.Name)
 	}
 	return services
 }
@@ -540,47 +390,51 @@ func (m *Method) GetMethodURL(classPath string) string {
 	for _, ann := range m.Annotations {
 		// Check for mapping annotations
 		if strings.HasSuffix(ann.Name, "Mapping") {
-			if value, ok := ann.Attributes["value"]; ok {
-				methodPath = value
-				break
-			}
-			// Try to extract from raw
-			methodPath = extractAnnotationValue(ann.Raw)
+			methodPath = mappingPath(ann)
 			if methodPath != "" {
 				break
 			}
 		}
 	}
 
-	return combineURLPaths(classPath, methodPath)
+	return CombineURLPaths(classPath, methodPath)
 }
 
-// GetHTTPMethod returns the HTTP method for this method
+// composedMappings maps the composed mapping annotations to their HTTP method
+var composedMappings = map[string]string{
+	"GetMapping":    "GET",
+	"PostMapping":   "POST",
+	"PutMapping":    "PUT",
+	"DeleteMapping": "DELETE",
+	"PatchMapping":  "PATCH",
+}
+
+// HTTPMethods returns the HTTP methods a mapping annotation restricts requests
+// to: the method of @GetMapping and the like, or every method of
+// @RequestMapping(method = {GET, POST}). Empty when any method is accepted
+func (a Annotation) HTTPMethods() []string {
+	if method, ok := composedMappings[a.Name]; ok {
+		return []string{method}
+	}
+	var methods []string
+	for _, element := range ArrayElements(a.Attributes["method"]) {
+		// RequestMethod.POST, or POST when statically imported
+		methods = append(methods, strings.ToUpper(element[strings.LastIndex(element, ".")+1:]))
+	}
+	return methods
+}
+
+// GetHTTPMethod returns the HTTP method for this method: the first one its
+// mapping declares, GET for a @RequestMapping accepting any (see HTTPMethods)
 func (m *Method) GetHTTPMethod() string {
 	for _, ann := range m.Annotations {
-		switch ann.Name {
-		case "GetMapping":
-			return "GET"
-		case "PostMapping":
-			return "POST"
-		case "PutMapping":
-			return "PUT"
-		case "DeleteMapping":
-			return "DELETE"
-		case "PatchMapping":
-			return "PATCH"
-		case "RequestMapping":
-			// Check method attribute
-			if method, ok := ann.Attributes["method"]; ok {
-				// Handle "RequestMethod.POST"
-				if strings.Contains(method, ".") {
-					parts := strings.Split(method, ".")
-					method = parts[len(parts)-1]
-				}
-				return strings.ToUpper(method)
-			}
-			return "GET" // Default
+		if _, ok := composedMappings[ann.Name]; !ok && ann.Name != "RequestMapping" {
+			continue
+		}
+		if methods := ann.HTTPMethods(); len(methods) > 0 {
+			return methods[0]
 		}
+		return "GET" // Default
 	}
 	return ""
 }
@@ -0,0 +1,508 @@
+package javaparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const modernController = `package com.company.modern;
+
+import java.util.List;
+import java.util.Map;
+import static java.util.Collections.emptyList;
+import org.springframework.web.bind.annotation.*;
+
+@RestController
+@RequestMapping(value = {"/api/products", "/api/v1/products"}, produces = "application/json")
+public class ProductApiController extends BaseController implements Auditable<Long> {
+
+    private static final String PREFIX = "product.", SUFFIX = ".list";
+
+    @Autowired
+    private ProductService productService;
+
+    private final Map<String, List<Long>> cache = new HashMap<String, List<Long>>();
+
+    private final Comparator<Product> byName = (a, b) -> {
+        return a.getName().compareTo(b.getName());
+    };
+
+    private final Runnable task = new Runnable() {
+        @Override
+        public void run() {
+            System.out.println("}");
+        }
+    };
+
+    public ProductApiController(ProductService productService) {
+        this.productService = productService;
+    }
+
+    @GetMapping(path = "/{id}", params = {"type=(a)"})
+    public ResponseEntity<Map<String, List<Product>>> getProduct(
+            @PathVariable("id") final Long id,
+            @RequestParam(value = "q", required = false) String... keywords) throws IOException, ServiceException {
+        Runnable r = () -> productService.touch(id);
+        return ResponseEntity.ok(productService.find(id));
+    }
+
+    @RequestMapping(value = "/save", method = {RequestMethod.POST, RequestMethod.PUT})
+    public <T extends Serializable> void save(@RequestBody T body) {
+        productService.save(body);
+    }
+
+    public static class Page {
+        private int page;
+        private int size;
+    }
+}
+`
+
+// TestParseControllerDeclarations verifies that only real methods are reported
+func TestParseControllerDeclarations(t *testing.T) {
+	cls, err := ParseJavaFile(modernController)
+	if err != nil {
+		t.Fatalf("ParseJavaFile failed: %v", err)
+	}
+
+	if cls.Package != "com.company.modern" || cls.Name != "ProductApiController" || cls.Kind != KindClass {
+		t.Errorf("Unexpected class header: %s.%s (%s)", cls.Package, cls.Name, cls.Kind)
+	}
+	if len(cls.Imports) != 3 {
+		t.Errorf("Expected 3 non-static imports, got %v", cls.Imports)
+	}
+	if !cls.IsController() {
+		t.Error("Expected class to be detected as controller")
+	}
+	if got := cls.GetClassLevelURL(); got != "/api/products" {
+		t.Errorf("Expected class URL /api/products, got %q", got)
+	}
+
+	// Lambdas, anonymous classes and constructors must not show up as methods
+	names := []string{}
+	for _, m := range cls.Methods {
+		names = append(names, m.Name)
+	}
+	if strings.Join(names, ",") != "getProduct,save" {
+		t.Errorf("Expected methods [getProduct save], got %v", names)
+	}
+	if len(cls.Constructors) != 1 {
+		t.Errorf("Expected 1 constructor, got %d", len(cls.Constructors))
+	}
+	if len(cls.InnerClasses) != 1 || cls.InnerClasses[0].Name != "Page" || len(cls.InnerClasses[0].Fields) != 2 {
+		t.Errorf("Expected nested class Page with 2 fields")
+	}
+
+	fieldNames := []string{}
+	for _, f := range cls.Fields {
+		fieldNames = append(fieldNames, f.Name)
+	}
+	if strings.Join(fieldNames, ",") != "PREFIX,SUFFIX,productService,cache,byName,task" {
+		t.Errorf("Unexpected fields: %v", fieldNames)
+	}
+	if cls.Fields[3].Type != "Map<String, List<Long>>" {
+		t.Errorf("Expected generic field type, got %q", cls.Fields[3].Type)
+	}
+	if services := cls.GetInjectedServices(); len(services) != 1 || services[0] != "productService" {
+		t.Errorf("Expected injected productService, got %v", services)
+	}
+}
+
+// TestParseMethodSignature verifies parameters, throws, spans and body capture
+func TestParseMethodSignature(t *testing.T) {
+	cls, err := ParseJavaFile(modernController)
+	if err != nil {
+		t.Fatalf("ParseJavaFile failed: %v", err)
+	}
+
+	m := cls.Methods[0]
+	if m.ReturnType != "ResponseEntity<Map<String, List<Product>>>" {
+		t.Errorf("Unexpected return type %q", m.ReturnType)
+	}
+	if len(m.Parameters) != 2 {
+		t.Fatalf("Expected 2 parameters, got %d", len(m.Parameters))
+	}
+	if p := m.Parameters[0]; p.Name != "id" || p.Type != "Long" || p.Annotations[0].Attributes["value"] != "id" {
+		t.Errorf("Unexpected first parameter: %+v", p)
+	}
+	if p := m.Parameters[1]; !p.Varargs || p.Type != "String..." || p.Annotations[0].Attributes["required"] != "false" {
+		t.Errorf("Unexpected varargs parameter: %+v", p)
+	}
+	if len(m.ParamsList) != 2 || !strings.HasPrefix(m.ParamsList[0], "@PathVariable") {
+		t.Errorf("Unexpected ParamsList: %v", m.ParamsList)
+	}
+	if strings.Join(m.Throws, ",") != "IOException,ServiceException" {
+		t.Errorf("Unexpected throws: %v", m.Throws)
+	}
+	if m.GetHTTPMethod() != "GET" || m.GetMethodURL(cls.GetClassLevelURL()) != "/api/products/{id}" {
+		t.Errorf("Unexpected mapping: %s %s", m.GetHTTPMethod(), m.GetMethodURL(cls.GetClassLevelURL()))
+	}
+	if !strings.Contains(m.Body, "productService.find(id)") || strings.Contains(m.Body, "@GetMapping") {
+		t.Errorf("Body not captured correctly: %q", m.Body)
+	}
+
+	// Span covers annotations through the closing brace
+	text := modernController[m.Span.Start:m.Span.End]
+	if !strings.HasPrefix(text, "@GetMapping") || !strings.HasSuffix(text, "}") {
+		t.Errorf("Unexpected span text: %q", text)
+	}
+	if m.Line != 35 || m.Span.StartLine != 34 {
+		t.Errorf("Expected method at line 35 (span from 34), got %d (%d)", m.Line, m.Span.StartLine)
+	}
+
+	save := cls.Methods[1]
+	if save.GetHTTPMethod() != "POST" || save.TypeParameters != "<T extends Serializable>" {
+		t.Errorf("Unexpected save method: %s %s", save.GetHTTPMethod(), save.TypeParameters)
+	}
+}
+
+// TestParseModernTypes covers records, enums, interfaces and sealed types
+func TestParseModernTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		kind    string
+		fields  int
+		methods int
+	}{
+		{
+			name:    "record",
+			source:  `public record UserDto(@NotNull String name, int age) { public UserDto { Objects.requireNonNull(name); } public String label() { return name + age; } }`,
+			kind:    KindRecord,
+			fields:  2,
+			methods: 1,
+		},
+		{
+			name:    "enum",
+			source:  `public enum Status { ACTIVE("A") { @Override String code() { return "a"; } }, INACTIVE("I"); private final String c; Status(String c) { this.c = c; } String code() { return c; } }`,
+			kind:    KindEnum,
+			fields:  1,
+			methods: 1,
+		},
+		{
+			name:    "interface",
+			source:  `public interface UserMapper extends BaseMapper<User> { List<User> selectList(Map<String, Object> param); default int count() { return 0; } }`,
+			kind:    KindInterface,
+			fields:  0,
+			methods: 2,
+		},
+		{
+			name:    "sealed",
+			source:  `public sealed abstract class Shape permits Circle, Square { abstract double area(); }`,
+			kind:    KindClass,
+			fields:  0,
+			methods: 1,
+		},
+		{
+			name:    "annotation",
+			source:  `public @interface Audit { String value() default ""; int level() default 1; }`,
+			kind:    KindAnnotation,
+			fields:  0,
+			methods: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cls, err := ParseJavaFile(tt.source)
+			if err != nil {
+				t.Fatalf("ParseJavaFile failed: %v", err)
+			}
+			if cls.Kind != tt.kind {
+				t.Errorf("Expected kind %s, got %s", tt.kind, cls.Kind)
+			}
+			if len(cls.Fields) != tt.fields {
+				t.Errorf("Expected %d fields, got %d", tt.fields, len(cls.Fields))
+			}
+			if len(cls.Methods) != tt.methods {
+				t.Errorf("Expected %d methods, got %d", tt.methods, len(cls.Methods))
+			}
+		})
+	}
+}
+
+// TestParseSupertypes verifies extends/implements clauses are kept
+func TestParseSupertypes(t *testing.T) {
+	cls, err := ParseJavaFile(`public class UserServiceImpl extends AbstractService<User, Long> implements UserService, Serializable { }`)
+	if err != nil {
+		t.Fatalf("ParseJavaFile failed: %v", err)
+	}
+	if cls.SuperClass != "AbstractService<User, Long>" {
+		t.Errorf("Unexpected superclass %q", cls.SuperClass)
+	}
+	if len(cls.Interfaces) != 2 || cls.Interfaces[0] != "UserService" || cls.Interfaces[1] != "Serializable" {
+		t.Errorf("Unexpected interfaces %v", cls.Interfaces)
+	}
+
+	iface, err := ParseJavaFile(`public interface UserMapper extends BaseMapper<User>, Auditable { }`)
+	if err != nil {
+		t.Fatalf("ParseJavaFile failed: %v", err)
+	}
+	if iface.SuperClass != "" || len(iface.Interfaces) != 2 {
+		t.Errorf("Interface extends should populate Interfaces, got super=%q interfaces=%v", iface.SuperClass, iface.Interfaces)
+	}
+}
+
+// TestTokenizeSkipsCommentsAndLiterals verifies braces inside literals and comments are ignored
+func TestTokenizeSkipsCommentsAndLiterals(t *testing.T) {
+	src := "a /* { */ \"}\" // }\n '{' \"\"\"\n  text { block\n  \"\"\" b"
+	toks := Tokenize(src)
+
+	kinds := []TokenKind{TokenIdent, TokenString, TokenChar, TokenString, TokenIdent, TokenEOF}
+	if len(toks) != len(kinds) {
+		t.Fatalf("Expected %d tokens, got %d: %+v", len(kinds), len(toks), toks)
+	}
+	for i, kind := range kinds {
+		if toks[i].Kind != kind {
+			t.Errorf("Token %d: expected kind %d, got %d (%q)", i, kind, toks[i].Kind, toks[i].Text)
+		}
+	}
+	if toks[4].Line != 4 {
+		t.Errorf("Expected last identifier on line 4, got %d", toks[4].Line)
+	}
+}
+
+// TestParseJavaDoc verifies JavaDoc is attached to declarations and split into tags
+func TestParseJavaDoc(t *testing.T) {
+	src := `package com.company.legacy;
+
+/**
+ * 사용자 관리 컨트롤러
+ */
+@Controller
+public class UserController {
+
+    /** 사용자 서비스 */
+    @Autowired
+    private UserService userService;
+
+    /**
+     * 사용자 목록을 조회한다. 관리자 화면에서 사용된다.
+     * <p>
+     * 검색 조건은 {@code searchKeyword}로 전달한다.
+     *
+     * @param searchKeyword 검색어
+     * @param pageNo 페이지 번호
+     *        (1부터 시작)
+     * @return 사용자 목록 화면
+     * @throws UserNotFoundException 사용자가 없는 경우
+     * @since 1.2
+     */
+    // Legacy mapping, kept for old menus
+    @RequestMapping("/user/list.do")
+    public ModelAndView list(String searchKeyword, int pageNo) {
+        /* 주석 처리된 코드: model.put("x", 1); */
+        return new ModelAndView("user/list");
+    }
+}
+`
+	cls, err := ParseJavaFile(src)
+	if err != nil {
+		t.Fatalf("ParseJavaFile failed: %v", err)
+	}
+
+	if doc := ParseJavaDoc(cls.JavaDoc); doc.Summary != "사용자 관리 컨트롤러" {
+		t.Errorf("Unexpected class summary %q", doc.Summary)
+	}
+	if doc := ParseJavaDoc(cls.Fields[0].JavaDoc); doc.Summary != "사용자 서비스" {
+		t.Errorf("Unexpected field summary %q", doc.Summary)
+	}
+
+	m := cls.Methods[0]
+	doc := ParseJavaDoc(m.JavaDoc)
+	if doc.Summary != "사용자 목록을 조회한다." {
+		t.Errorf("Unexpected summary %q", doc.Summary)
+	}
+	if doc.Description != "사용자 목록을 조회한다. 관리자 화면에서 사용된다.\n\n검색 조건은 searchKeyword로 전달한다." {
+		t.Errorf("Unexpected description %q", doc.Description)
+	}
+	if doc.Params["searchKeyword"] != "검색어" || doc.Params["pageNo"] != "페이지 번호 (1부터 시작)" {
+		t.Errorf("Unexpected params %v", doc.Params)
+	}
+	if doc.Return != "사용자 목록 화면" {
+		t.Errorf("Unexpected return %q", doc.Return)
+	}
+	if len(doc.Throws) != 1 || doc.Throws[0].Type != "UserNotFoundException" || doc.Throws[0].Description != "사용자가 없는 경우" {
+		t.Errorf("Unexpected throws %v", doc.Throws)
+	}
+	if strings.Contains(m.Body, "model.put") {
+		t.Errorf("Comments should be stripped from the body: %q", m.Body)
+	}
+}
+
+func TestMethodSignature(t *testing.T) {
+	cases := map[string]string{
+		"java.util.List<Map<String, Object>>[]": "List[]",
+		"String...":                             "String...",
+		"Map<String, List<Long>>":               "Map",
+		"int":                                   "int",
+	}
+	for input, want := range cases {
+		if got := EraseType(input); got != want {
+			t.Errorf("EraseType(%q) = %q, want %q", input, got, want)
+		}
+	}
+
+	m := Method{Name: "save", Params: "@RequestBody List<UserDto> users, final boolean draft"}
+	if got := m.Signature(); got != "save(List,boolean)" {
+		t.Errorf("Unexpected signature %q", got)
+	}
+}
+
+// TestHTTPMethods verifies every verb of a mapping, not just the first
+func TestHTTPMethods(t *testing.T) {
+	cls, err := ParseJavaFile(`package com.company.web;
+
+import static org.springframework.web.bind.annotation.RequestMethod.POST;
+
+@Controller
+public class BoardController {
+    @RequestMapping(value = "/save.do", method = {RequestMethod.GET, POST})
+    public String save() { return "board/save"; }
+
+    @RequestMapping("/any.do")
+    public String any() { return "board/any"; }
+
+    @DeleteMapping("/{id}")
+    public void delete(@PathVariable Long id) {}
+}
+`)
+	if err != nil {
+		t.Fatalf("ParseJavaFile failed: %v", err)
+	}
+	want := [][]string{{"GET", "POST"}, nil, {"DELETE"}}
+	for i, m := range cls.Methods {
+		if got := m.Annotations[0].HTTPMethods(); !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("%s: HTTPMethods() = %v, want %v", m.Name, got, want[i])
+		}
+	}
+	if got := cls.Methods[0].GetHTTPMethod(); got != "GET" {
+		t.Errorf("GetHTTPMethod() = %q, want the first method", got)
+	}
+}
+
+// TestInjectionPoints verifies field, Lombok, constructor and setter injection with qualifiers
+func TestInjectionPoints(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []InjectionPoint
+	}{
+		{
+			name: "lombok",
+			source: `@Service @RequiredArgsConstructor
+public class OrderService {
+    private static final Logger log = null;
+    private final OrderMapper orderMapper;
+    @Qualifier("fastPayment") private final PaymentService paymentService;
+    private final Clock clock = Clock.systemUTC();
+    private String cache;
+}`,
+			want: []InjectionPoint{
+				{Name: "orderMapper", Type: "OrderMapper", Kind: InjectConstructor},
+				{Name: "paymentService", Type: "PaymentService", Qualifier: "fastPayment", Kind: InjectConstructor},
+			},
+		},
+		{
+			name: "constructor",
+			source: `@RestController
+public class OrderController {
+    private final OrderService service;
+    public OrderController(@Qualifier("orderServiceV2") OrderService orderService) {
+        this.service = orderService;
+    }
+}`,
+			want: []InjectionPoint{
+				{Name: "service", Type: "OrderService", Qualifier: "orderServiceV2", Kind: InjectConstructor},
+			},
+		},
+		{
+			name: "field and setter",
+			source: `@Component
+public class ReportJob {
+    @Resource(name = "legacyReportDao") private ReportDao reportDao;
+    @Inject @Named("mailer") Mailer mailer;
+    private AuditService audit;
+    public ReportJob() {}
+    @Autowired
+    public void setAuditService(AuditService auditService) { this.audit = auditService; }
+}`,
+			want: []InjectionPoint{
+				{Name: "reportDao", Type: "ReportDao", Qualifier: "legacyReportDao", Kind: InjectField},
+				{Name: "mailer", Type: "Mailer", Qualifier: "mailer", Kind: InjectField},
+				{Name: "audit", Type: "AuditService", Kind: InjectSetter},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cls, err := ParseJavaFile(tt.source)
+			if err != nil {
+				t.Fatalf("ParseJavaFile failed: %v", err)
+			}
+			got := cls.InjectionPoints()
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d injection points, got %+v", len(tt.want), got)
+			}
+			for i, want := range tt.want {
+				g := got[i]
+				if g.Name != want.Name || g.Type != want.Type || g.Qualifier != want.Qualifier || g.Kind != want.Kind {
+					t.Errorf("Point %d: expected %+v, got %+v", i, want, g)
+				}
+			}
+		})
+	}
+
+	if name := Decapitalize("URLService"); name != "URLService" {
+		t.Errorf("Decapitalize kept acronym as %q", name)
+	}
+}
+
+func TestMapperStatement(t *testing.T) {
+	source := `package com.company.mapper;
+
+public interface UserMapper {
+    @Select({"SELECT * FROM TB_USER", "WHERE ID = #{id}", })
+    @ResultMap("userMap")
+    UserVO selectUser(Long id);
+
+    @Update("""
+        UPDATE TB_USER
+           SET NAME = #{name}\
+         WHERE ID = #{id}
+        """)
+    int updateName(UserVO user);
+
+    @SelectProvider(type = UserSqlProvider.class)
+    List<UserVO> search(UserSearch search);
+
+    @DeleteProvider(value = UserSqlProvider.class, method = "deleteAll")
+    int delete();
+
+    List<UserVO> selectAll();
+}`
+	cls, err := ParseJavaFile(source)
+	if err != nil {
+		t.Fatalf("ParseJavaFile failed: %v", err)
+	}
+
+	want := []*MapperStatement{
+		{Kind: "select", SQL: []string{`"SELECT * FROM TB_USER"`, `"WHERE ID = #{id}"`}, ResultMap: "userMap"},
+		{Kind: "update", SQL: []string{"\"\"\"\n        UPDATE TB_USER\n           SET NAME = #{name}\\\n         WHERE ID = #{id}\n        \"\"\""}},
+		{Kind: "select", ProviderType: "UserSqlProvider"},
+		{Kind: "delete", ProviderType: "UserSqlProvider", ProviderMethod: "deleteAll"},
+		nil,
+	}
+	for i, method := range cls.Methods {
+		if got := method.MapperStatement(); !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("%s: MapperStatement() = %+v, want %+v", method.Name, got, want[i])
+		}
+	}
+
+	text, ok := StringValue(want[1].SQL[0])
+	if wantText := "UPDATE TB_USER\n   SET NAME = #{name} WHERE ID = #{id}\n"; !ok || text != wantText {
+		t.Errorf("StringValue(text block) = %q, %v; want %q", text, ok, wantText)
+	}
+}
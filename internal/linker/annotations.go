@@ -0,0 +1,307 @@
+package linker
+
+import (
+	"fmt"
+	"strings"
+
+	"spec-recon/internal/javaparser"
+	"spec-recon/internal/xmlparser"
+)
+
+// resultContainers are the return types MyBatis maps one row at a time into
+// their element type
+var resultContainers = map[string]bool{
+	"List": true, "ArrayList": true, "LinkedList": true, "Collection": true, "Iterable": true,
+	"Set": true, "HashSet": true, "LinkedHashSet": true, "Optional": true, "Cursor": true, "Stream": true,
+}
+
+// sqlBuilderClauses are the org.apache.ibatis.jdbc.SQL methods a provider
+// builds statements with, as SQL keywords
+var sqlBuilderClauses = map[string]string{
+	"SELECT": "SELECT", "SELECT_DISTINCT": "SELECT DISTINCT", "FROM": "FROM",
+	"JOIN": "JOIN", "INNER_JOIN": "INNER JOIN", "LEFT_OUTER_JOIN": "LEFT OUTER JOIN",
+	"RIGHT_OUTER_JOIN": "RIGHT OUTER JOIN", "OUTER_JOIN": "OUTER JOIN",
+	"WHERE": "WHERE", "OR": "OR", "AND": "AND", "GROUP_BY": "GROUP BY", "HAVING": "HAVING",
+	"ORDER_BY": "ORDER BY", "LIMIT": "LIMIT", "OFFSET": "OFFSET",
+	"INSERT_INTO": "INSERT INTO", "VALUES": "VALUES", "INTO_COLUMNS": "INTO_COLUMNS", "INTO_VALUES": "INTO_VALUES",
+	"UPDATE": "UPDATE", "SET": "SET", "DELETE_FROM": "DELETE FROM",
+}
+
+// AddAnnotatedStatements reads the statements mapper interfaces declare with
+// MyBatis annotations (@Select, @Insert, ... and the @...Provider forms) into
+// SQL nodes, as a mapper file declaring them would. Constants in the SQL are
+// evaluated, so call it once every class is loaded; a statement a mapper file
+// also declares keeps the XML version, as MyBatis refuses the duplicate
+func (pool *ComponentPool) AddAnnotatedStatements() {
+	for className, decl := range pool.declMap {
+		for i := range decl.Methods {
+			method := &decl.Methods[i]
+			stmt := method.MapperStatement()
+			if stmt == nil {
+				continue
+			}
+			sqlKey := className + "." + method.Name
+			if pool.SQLMap[sqlKey] != nil {
+				fmt.Printf("[LINKER SKIP] %s is declared in both a mapper file and an annotation; keeping the mapper file\n", sqlKey)
+				continue
+			}
+
+			file, line := decl.File, method.Line
+			var script string
+			if stmt.ProviderType == "" {
+				parts := make([]string, len(stmt.SQL))
+				for j, part := range stmt.SQL {
+					parts[j] = pool.sqlText(className, part)
+				}
+				script = strings.Join(parts, " ")
+			} else {
+				providerClass := pool.ResolveTypeName(className, stmt.ProviderType)
+				providerName := stmt.ProviderMethod
+				if providerName == "" {
+					providerName = pool.defaultProviderMethod(providerClass, method.Name)
+				}
+				provider := pool.providerMethod(providerClass, providerName)
+				if provider == nil {
+					fmt.Printf("[LINKER SKIP] Unknown SQL provider %s.%s for %s\n", stmt.ProviderType, providerName, sqlKey)
+					continue
+				}
+				script = pool.providerSQL(providerClass, provider)
+				file, line = pool.declMap[providerClass].File, provider.Line
+				pool.sqlProviders[sqlKey] = providerClass + "." + providerName
+			}
+
+			sql, err := xmlparser.ParseScript(stmt.Kind, method.Name, script)
+			if err != nil {
+				fmt.Printf("[LINKER SKIP] Unreadable @%s SQL in %s: %v\n", stmt.Kind, sqlKey, err)
+				continue
+			}
+			sql.ResultMap = stmt.ResultMap
+			if stmt.Kind == "select" && stmt.ResultMap == "" {
+				if resultType := statementResultType(method.ReturnType); resultType != "" {
+					sql.ResultType = pool.ResolveTypeName(className, resultType)
+				}
+			}
+			if params := method.Parameters; len(params) == 1 && !hasAnnotation(params[0].Annotations, "Param") {
+				sql.ParameterType = pool.ResolveTypeName(className, params[0].Type)
+			}
+
+			pool.AddMapperXML(&xmlparser.MapperXML{Namespace: className, File: decl.File, SQLs: []xmlparser.SQL{sql}})
+			pool.SQLMap[sqlKey].File = file
+			pool.SQLMap[sqlKey].Line = line
+		}
+	}
+}
+
+// sqlText evaluates one SQL string expression of a class. An expression that
+// is not constant (a provider concatenating arguments) keeps its literal parts
+func (pool *ComponentPool) sqlText(fromClass, expr string) string {
+	if value, ok := pool.EvaluateString(fromClass, expr); ok {
+		return value
+	}
+	var parts []string
+	for _, tok := range javaparser.Tokenize(expr) {
+		if tok.Kind == javaparser.TokenString {
+			if value, ok := javaparser.StringValue(tok.Text); ok {
+				parts = append(parts, value)
+			}
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// defaultProviderMethod returns the SQL provider method MyBatis calls when the
+// annotation names none: the mapper method's name when the provider implements
+// ProviderMethodResolver, otherwise provideSql
+func (pool *ComponentPool) defaultProviderMethod(providerClass, mapperMethod string) string {
+	for _, superType := range pool.AllSuperTypes(providerClass) {
+		if simpleTypeName(superType) == "ProviderMethodResolver" {
+			return mapperMethod
+		}
+	}
+	return "provideSql"
+}
+
+// providerMethod finds an SQL provider method by class and name
+func (pool *ComponentPool) providerMethod(providerClass, methodName string) *javaparser.Method {
+	decl := pool.declMap[providerClass]
+	if decl == nil {
+		return nil
+	}
+	for i := range decl.Methods {
+		if decl.Methods[i].Name == methodName {
+			return &decl.Methods[i]
+		}
+	}
+	return nil
+}
+
+// providerSQL reconstructs the SQL an SQL provider method returns, with every
+// conditional clause included (the way PlainSQL reads dynamic SQL). Statements
+// built with the MyBatis SQL builder are assembled in clause order; otherwise
+// the string literals and constants of the body are joined in source order
+func (pool *ComponentPool) providerSQL(providerClass string, method *javaparser.Method) string {
+	body := method.Body
+	tokens := javaparser.Tokenize(body)
+
+	clauses := make(map[string][]string)
+	var where [][]string // WHERE conditions, grouped by OR()
+	builder := false
+	for i := 0; i+1 < len(tokens); i++ {
+		keyword, ok := sqlBuilderClauses[tokens[i].Text]
+		if !ok || tokens[i].Kind != javaparser.TokenIdent || !tokens[i+1].Is("(") {
+			continue
+		}
+		builder = true
+		var args []string
+		for _, arg := range splitArguments(body, tokens, i+1) {
+			if text := strings.TrimSpace(pool.sqlText(providerClass, arg)); text != "" {
+				args = append(args, text)
+			}
+		}
+		switch keyword {
+		case "OR":
+			where = append(where, nil)
+		case "AND":
+		case "WHERE":
+			if len(where) == 0 {
+				where = append(where, nil)
+			}
+			where[len(where)-1] = append(where[len(where)-1], args...)
+		default:
+			clauses[keyword] = append(clauses[keyword], args...)
+		}
+	}
+	if !builder {
+		return pool.concatenatedSQL(providerClass, body, tokens)
+	}
+
+	var sb strings.Builder
+	clause := func(keyword, separator string) {
+		if values := clauses[keyword]; len(values) > 0 {
+			sb.WriteString(" " + keyword + " " + strings.Join(values, separator))
+		}
+	}
+	whereClause := func() {
+		var groups []string
+		for _, conditions := range where {
+			if len(conditions) > 0 {
+				groups = append(groups, "("+strings.Join(conditions, " AND ")+")")
+			}
+		}
+		if len(groups) > 0 {
+			sb.WriteString(" WHERE " + strings.Join(groups, " OR "))
+		}
+	}
+
+	switch {
+	case len(clauses["INSERT INTO"]) > 0:
+		columns := clauses["INTO_COLUMNS"]
+		values := clauses["INTO_VALUES"]
+		for j, value := range clauses["VALUES"] {
+			if j%2 == 0 {
+				columns = append(columns, value)
+			} else {
+				values = append(values, value)
+			}
+		}
+		sb.WriteString("INSERT INTO " + clauses["INSERT INTO"][0])
+		sb.WriteString(" (" + strings.Join(columns, ", ") + ") VALUES (" + strings.Join(values, ", ") + ")")
+	case len(clauses["UPDATE"]) > 0:
+		sb.WriteString("UPDATE " + clauses["UPDATE"][0])
+		clause("SET", ", ")
+		whereClause()
+	case len(clauses["DELETE FROM"]) > 0:
+		sb.WriteString("DELETE FROM " + clauses["DELETE FROM"][0])
+		whereClause()
+	default:
+		clause("SELECT", ", ")
+		clause("SELECT DISTINCT", ", ")
+		clause("FROM", ", ")
+		for _, join := range []string{"JOIN", "INNER JOIN", "LEFT OUTER JOIN", "RIGHT OUTER JOIN", "OUTER JOIN"} {
+			for _, table := range clauses[join] {
+				sb.WriteString(" " + join + " " + table)
+			}
+		}
+		whereClause()
+		clause("GROUP BY", ", ")
+		clause("HAVING", " AND ")
+		clause("ORDER BY", ", ")
+		clause("LIMIT", " ")
+		clause("OFFSET", " ")
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// concatenatedSQL joins the string literals and constants of a provider body
+// that builds its SQL by hand (StringBuilder, '+')
+func (pool *ComponentPool) concatenatedSQL(providerClass, body string, tokens []javaparser.Token) string {
+	var parts []string
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case tok.Kind == javaparser.TokenString:
+			if value, ok := javaparser.StringValue(tok.Text); ok {
+				parts = append(parts, value)
+			}
+		case tok.Kind == javaparser.TokenIdent && (i == 0 || !tokens[i-1].Is(".")):
+			end := i + 1
+			for end+1 < len(tokens) && tokens[end].Is(".") && tokens[end+1].Kind == javaparser.TokenIdent {
+				end += 2
+			}
+			if end < len(tokens) && tokens[end].Is("(") {
+				continue // Method call
+			}
+			if value, ok := pool.EvaluateString(providerClass, tokenText(body, tokens, i, end)); ok {
+				parts = append(parts, value)
+				i = end - 1
+			}
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// statementResultType is the resultType MyBatis infers from a mapper method's
+// return type: the element type of collections, Optional and Cursor
+// "List<UserVO>" -> "UserVO", "Optional<Map<String, Object>>" -> "Map"
+func statementResultType(returnType string) string {
+	typeName := strings.TrimSpace(returnType)
+	for {
+		erased := javaparser.EraseType(typeName)
+		if strings.HasSuffix(erased, "[]") {
+			typeName = strings.TrimSpace(strings.TrimSuffix(typeName, "[]"))
+			continue
+		}
+		open, close := strings.Index(typeName, "<"), strings.LastIndex(typeName, ">")
+		if !resultContainers[erased] || open < 0 || close < open {
+			break
+		}
+		typeName = strings.TrimSpace(typeName[open+1 : close])
+	}
+	if idx := strings.Index(typeName, "<"); idx >= 0 {
+		typeName = typeName[:idx]
+	}
+	if typeName == "void" || typeName == "Void" {
+		return ""
+	}
+	return typeName
+}
+
+// hasAnnotation reports whether annotations include one with the given name
+func hasAnnotation(annotations []javaparser.Annotation, name string) bool {
+	for _, ann := range annotations {
+		if ann.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// findAnnotation returns the annotation with the given simple name
+func findAnnotation(annotations []javaparser.Annotation, name string) (javaparser.Annotation, bool) {
+	for _, ann := range annotations {
+		if ann.Name == name {
+			return ann, true
+		}
+	}
+	return javaparser.Annotation{}, false
+}
@@ -0,0 +1,193 @@
+package linker
+
+import (
+	"reflect"
+	"testing"
+
+	"spec-recon/internal/javaparser"
+	"spec-recon/internal/model"
+	"spec-recon/internal/xmlparser"
+)
+
+// TestAnnotatedStatements verifies that MyBatis annotation statements and SQL
+// providers become SQL nodes linked like mapper file statements, and that a
+// provider named without a method is resolved as MyBatis does
+func TestAnnotatedStatements(t *testing.T) {
+	sources := map[string]string{
+		"UserMapper.java": `package com.company.user;
+
+public interface UserMapper {
+    String TABLE = "TB_USER";
+
+    @Select({"SELECT USER_ID, NAME FROM " + TABLE, "WHERE USER_ID = #{userId}"})
+    UserVO selectUser(@Param("userId") Long userId);
+
+    @Select("<script>SELECT * FROM TB_USER <where><if test='name != null'>AND NAME LIKE #{name}</if></where></script>")
+    List<UserVO> searchUsers(UserSearch search);
+
+    @Update("""
+        UPDATE TB_USER
+           SET NAME = #{name}
+         WHERE USER_ID = #{userId}
+        """)
+    int updateName(UserVO user);
+
+    @SelectProvider(type = UserSqlProvider.class, method = "byDept")
+    List<Map<String, Object>> selectByDept(String deptId);
+
+    @InsertProvider(type = UserSqlProvider.class)
+    int insertUser(UserVO user);
+
+    @Delete("DELETE FROM TB_USER WHERE USER_ID = #{userId}")
+    int deleteUser(Long userId);
+
+    @DeleteProvider(type = AuditSqlProvider.class)
+    int deleteAudit(Long userId);
+}
+`,
+		"UserSqlProvider.java": `package com.company.user;
+
+public class UserSqlProvider implements ProviderMethodResolver {
+    public String byDept(String deptId) {
+        return new SQL() {{
+            SELECT("U.USER_ID, U.NAME");
+            FROM("TB_USER U");
+            LEFT_OUTER_JOIN("TB_DEPT D ON D.DEPT_ID = U.DEPT_ID");
+            if (deptId != null) {
+                WHERE("U.DEPT_ID = #{deptId}");
+            }
+            ORDER_BY("U.NAME");
+        }}.toString();
+    }
+
+    public String insertUser(UserVO user) {
+        StringBuilder sql = new StringBuilder("INSERT INTO TB_USER (USER_ID, NAME)");
+        sql.append(" VALUES (#{userId}, #{name})");
+        return sql.toString();
+    }
+}
+`,
+		"AuditSqlProvider.java": `package com.company.user;
+
+public class AuditSqlProvider {
+    public String deleteAudit(Long userId) {
+        return "DELETE FROM TB_AUDIT_OLD WHERE USER_ID = #{userId}";
+    }
+
+    public String provideSql(Long userId) {
+        return "DELETE FROM TB_AUDIT WHERE USER_ID = #{userId}";
+    }
+}
+`,
+		"UserVO.java": `package com.company.user;
+
+public class UserVO {
+    private Long userId;
+    private String name;
+}
+`,
+	}
+	mapperXML := `<?xml version="1.0" encoding="UTF-8"?>
+<mapper namespace="com.company.user.UserMapper">
+    <delete id="deleteUser">
+        DELETE FROM TB_USER_HIST WHERE USER_ID = #{userId}
+    </delete>
+</mapper>
+`
+
+	pool := NewComponentPool()
+	for file, source := range sources {
+		cls, err := javaparser.ParseJavaFile(source)
+		if err != nil {
+			t.Fatalf("ParseJavaFile failed: %v", err)
+		}
+		cls.File = file
+		pool.AddJavaClass(cls, source)
+	}
+	mapper, err := xmlparser.ParseXMLFile(mapperXML)
+	if err != nil {
+		t.Fatalf("ParseXMLFile failed: %v", err)
+	}
+	pool.AddMapperXML(mapper)
+	NewLinker(pool).BuildCallGraph()
+
+	if classType := pool.ClassMap["com.company.user.UserMapper"].Type; classType != model.NodeTypeMapper {
+		t.Errorf("UserMapper type = %s, want %s", classType, model.NodeTypeMapper)
+	}
+
+	type statement struct {
+		File, ResultType, ParameterType string
+		Line                            int
+		Tables                          []model.TableAccess
+		Conditions, Placeholders        []string
+	}
+	want := map[string]statement{
+		"selectUser": {
+			File: "UserMapper.java", Line: 7, ResultType: "com.company.user.UserVO",
+			Tables:       []model.TableAccess{{Table: "TB_USER", Operations: "R", Columns: []string{"NAME", "USER_ID"}}},
+			Placeholders: []string{"#{userId}"},
+		},
+		"searchUsers": {
+			File: "UserMapper.java", Line: 10, ResultType: "com.company.user.UserVO", ParameterType: "UserSearch",
+			Tables:       []model.TableAccess{{Table: "TB_USER", Operations: "R", Columns: []string{"NAME"}}},
+			Conditions:   []string{"name != null"},
+			Placeholders: []string{"#{name}"},
+		},
+		"updateName": {
+			File: "UserMapper.java", Line: 17, ParameterType: "com.company.user.UserVO",
+			Tables:       []model.TableAccess{{Table: "TB_USER", Operations: "U", Columns: []string{"NAME", "USER_ID"}}},
+			Placeholders: []string{"#{name}", "#{userId}"},
+		},
+		"selectByDept": {
+			File: "UserSqlProvider.java", Line: 4, ResultType: "Map", ParameterType: "String",
+			Tables: []model.TableAccess{
+				{Table: "TB_DEPT", Operations: "R", Columns: []string{"DEPT_ID"}},
+				{Table: "TB_USER", Operations: "R", Columns: []string{"DEPT_ID", "NAME", "USER_ID"}},
+			},
+			Placeholders: []string{"#{deptId}"},
+		},
+		"insertUser": {
+			File: "UserSqlProvider.java", Line: 16, ParameterType: "com.company.user.UserVO",
+			Tables:       []model.TableAccess{{Table: "TB_USER", Operations: "C", Columns: []string{"NAME", "USER_ID"}}},
+			Placeholders: []string{"#{userId}", "#{name}"},
+		},
+		"deleteAudit": { // No method and no ProviderMethodResolver: provideSql
+			File: "AuditSqlProvider.java", Line: 8, ParameterType: "Long",
+			Tables:       []model.TableAccess{{Table: "TB_AUDIT", Operations: "D", Columns: []string{"USER_ID"}}},
+			Placeholders: []string{"#{userId}"},
+		},
+		"deleteUser": { // Declared in the mapper file too: the XML statement wins
+			Line:         3,
+			Tables:       []model.TableAccess{{Table: "TB_USER_HIST", Operations: "D", Columns: []string{"USER_ID"}}},
+			Placeholders: []string{"#{userId}"},
+		},
+	}
+	for id, w := range want {
+		sqlNode := pool.GetSQL("com.company.user.UserMapper", id)
+		if sqlNode == nil {
+			t.Errorf("%s: no SQL node", id)
+			continue
+		}
+		got := statement{
+			File: sqlNode.File, Line: sqlNode.Line,
+			ResultType: sqlNode.ResultType, ParameterType: sqlNode.ParameterType,
+			Tables: sqlNode.Tables, Conditions: sqlNode.Conditions,
+		}
+		for _, p := range sqlNode.Placeholders {
+			got.Placeholders = append(got.Placeholders, p.Syntax())
+		}
+		if !reflect.DeepEqual(got, w) {
+			t.Errorf("%s: got\n%+v\nwant\n%+v\nSQL: %s", id, got, w, sqlNode.Comment)
+		}
+
+		methodNode := pool.GetMethod("com.company.user.UserMapper." + id)
+		if !containsNode(methodNode.Children, sqlNode) {
+			t.Errorf("%s: SQL node not linked to the mapper method", id)
+		}
+	}
+
+	providerNode := pool.GetMethod("com.company.user.UserSqlProvider.byDept")
+	if !containsNode(pool.GetMethod("com.company.user.UserMapper.selectByDept").Children, providerNode) {
+		t.Errorf("selectByDept: provider method not linked to the mapper method")
+	}
+}
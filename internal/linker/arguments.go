@@ -0,0 +1,156 @@
+package linker
+
+import (
+	"strings"
+
+	"spec-recon/internal/javaparser"
+)
+
+// collectDeclaredTypes finds "Type name" declarations in a method body or
+// parameter list and returns name -> erased type
+// It recognizes local variables, parameters, for-each and catch variables
+func collectDeclaredTypes(source string) map[string]string {
+	types := make(map[string]string)
+	tokens := javaparser.Tokenize(source)
+
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.Kind != javaparser.TokenIdent && !isPrimitiveKeyword(tok) {
+			continue
+		}
+		// The type must not be the tail of a qualified expression (a.b c is not a declaration)
+		if i > 0 && tokens[i-1].Is(".") {
+			continue
+		}
+
+		// Type [<...>] [[]]* name
+		j := i + 1
+		if j < len(tokens) && tokens[j].Is("<") {
+			j = skipAngles(tokens, j)
+		}
+		for j+1 < len(tokens) && tokens[j].Is("[") && tokens[j+1].Is("]") {
+			j += 2
+		}
+		if j+1 >= len(tokens) || tokens[j].Kind != javaparser.TokenIdent {
+			continue
+		}
+
+		next := tokens[j+1]
+		if next.Is("=") || next.Is(";") || next.Is(":") || next.Is(",") || next.Is(")") || next.Kind == javaparser.TokenEOF {
+			typeText := tokenText(source, tokens, i, j)
+			types[tokens[j].Text] = javaparser.EraseType(typeText)
+		}
+	}
+
+	return types
+}
+
+// skipAngles returns the index after the '>' closing the '<' at tokens[open]
+// It returns open+1 when the brackets are not balanced on a type-like sequence
+func skipAngles(tokens []javaparser.Token, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case tok.Is("<"):
+			depth++
+		case tok.Is(">"):
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		case tok.Kind == javaparser.TokenIdent, tok.Is(","), tok.Is("?"), tok.Is("."), tok.Is("["), tok.Is("]"),
+			tok.Is("extends"), tok.Is("super"), isPrimitiveKeyword(tok):
+			// Still inside a type argument list
+		default:
+			return open + 1
+		}
+	}
+	return open + 1
+}
+
+func isPrimitiveKeyword(tok javaparser.Token) bool {
+	if tok.Kind != javaparser.TokenKeyword {
+		return false
+	}
+	_, ok := boxedTypes[tok.Text]
+	return ok
+}
+
+// inferArgumentType guesses the static type of an argument expression
+// Returns "" when the type cannot be determined
+// lookup resolves a plain identifier (local, parameter or field) to its type
+func inferArgumentType(arg string, lookup func(name string) string) string {
+	arg = strings.TrimSpace(arg)
+	tokens := javaparser.Tokenize(arg)
+	if len(tokens) < 2 {
+		return ""
+	}
+	first := tokens[0]
+
+	switch first.Kind {
+	case javaparser.TokenString:
+		if len(tokens) == 2 || tokens[1].Is("+") {
+			return "String"
+		}
+	case javaparser.TokenChar:
+		if len(tokens) == 2 {
+			return "char"
+		}
+	case javaparser.TokenNumber:
+		if len(tokens) == 2 {
+			return numberLiteralType(first.Text)
+		}
+	}
+
+	switch {
+	case first.Is("true"), first.Is("false"):
+		if len(tokens) == 2 {
+			return "boolean"
+		}
+	case first.Is("new") && len(tokens) > 2:
+		// new Type<...>(...) or new Type[...]
+		end := 2
+		for end < len(tokens) && (tokens[end].Kind == javaparser.TokenIdent || tokens[end].Is(".")) {
+			end++
+		}
+		typeName := javaparser.EraseType(tokenText(arg, tokens, 1, end))
+		if end < len(tokens) && tokens[end].Is("[") {
+			typeName += "[]"
+		}
+		return typeName
+	case first.Is("(") && len(tokens) > 3 && tokens[2].Is(")") && (tokens[1].Kind == javaparser.TokenIdent || isPrimitiveKeyword(tokens[1])):
+		// Cast: (Type) expr
+		return tokens[1].Text
+	case first.Is("this") && len(tokens) == 4 && tokens[1].Is(".") && tokens[2].Kind == javaparser.TokenIdent:
+		return lookup(tokens[2].Text)
+	case first.Kind == javaparser.TokenIdent && len(tokens) == 2:
+		return lookup(first.Text)
+	}
+
+	return ""
+}
+
+// numberLiteralType returns the primitive type of a numeric literal
+func numberLiteralType(literal string) string {
+	lower := strings.ToLower(literal)
+	isHex := strings.HasPrefix(lower, "0x")
+	switch {
+	case strings.HasSuffix(lower, "l"):
+		return "long"
+	case !isHex && strings.HasSuffix(lower, "f"):
+		return "float"
+	case !isHex && (strings.HasSuffix(lower, "d") || strings.ContainsAny(lower, ".e")):
+		return "double"
+	}
+	return "int"
+}
+
+// argumentTypes infers the type of each argument of a call
+func argumentTypes(call MethodCall, lookup func(name string) string) []string {
+	types := make([]string, len(call.Args))
+	for i, arg := range call.Args {
+		types[i] = inferArgumentType(arg, lookup)
+	}
+	return types
+}
@@ -0,0 +1,133 @@
+package linker
+
+import (
+	"strings"
+
+	"spec-recon/internal/javaparser"
+)
+
+// EvaluateString evaluates a compile-time String expression as written in a class:
+// string literals and constants (NAMESPACE, UserConstants.NAMESPACE, statically
+// imported names) joined with '+'. Constants are static final (or interface)
+// fields with an initializer, looked up in the class, its supertypes and static
+// imports, and evaluated recursively. Returns false for anything else
+func (pool *ComponentPool) EvaluateString(fromClass, expr string) (string, bool) {
+	return pool.evaluateString(fromClass, expr, make(map[string]bool))
+}
+
+func (pool *ComponentPool) evaluateString(fromClass, expr string, visiting map[string]bool) (string, bool) {
+	tokens := javaparser.Tokenize(expr)
+	var sb strings.Builder
+	expectOperand := true
+
+	for i := 0; i < len(tokens) && tokens[i].Kind != javaparser.TokenEOF; {
+		tok := tokens[i]
+		if !expectOperand {
+			if !tok.Is("+") {
+				return "", false
+			}
+			expectOperand = true
+			i++
+			continue
+		}
+
+		switch tok.Kind {
+		case javaparser.TokenString, javaparser.TokenChar:
+			value, ok := javaparser.StringValue(tok.Text)
+			if !ok {
+				return "", false
+			}
+			sb.WriteString(value)
+			i++
+		case javaparser.TokenIdent:
+			name := tok.Text
+			i++
+			for i+1 < len(tokens) && tokens[i].Is(".") && tokens[i+1].Kind == javaparser.TokenIdent {
+				name += "." + tokens[i+1].Text
+				i += 2
+			}
+			value, ok := pool.lookupConstant(fromClass, name, visiting)
+			if !ok {
+				return "", false
+			}
+			sb.WriteString(value)
+		default:
+			return "", false
+		}
+		expectOperand = false
+	}
+
+	if expectOperand {
+		return "", false // Empty expression or trailing '+'
+	}
+	return sb.String(), true
+}
+
+// lookupConstant evaluates a (possibly qualified) constant name used in fromClass
+func (pool *ComponentPool) lookupConstant(fromClass, name string, visiting map[string]bool) (string, bool) {
+	var owners []string
+	fieldName := name
+
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		fieldName = name[idx+1:]
+		owners = append(owners, pool.ResolveTypeName(fromClass, name[:idx]))
+	} else {
+		owners = append(owners, fromClass)
+		if decl := pool.declMap[fromClass]; decl != nil {
+			for _, imp := range decl.StaticImports {
+				if strings.HasSuffix(imp, "."+name) {
+					owners = append(owners, strings.TrimSuffix(imp, "."+name))
+				} else if strings.HasSuffix(imp, ".*") {
+					owners = append(owners, strings.TrimSuffix(imp, ".*"))
+				}
+			}
+		}
+	}
+
+	for _, owner := range owners {
+		declaringClass, field := pool.findConstantField(owner, fieldName)
+		if field == nil {
+			continue
+		}
+		key := declaringClass + "." + fieldName
+		if visiting[key] {
+			return "", false // Circular definition
+		}
+		visiting[key] = true
+		value, ok := pool.evaluateString(declaringClass, field.Initializer, visiting)
+		delete(visiting, key)
+		return value, ok
+	}
+	return "", false
+}
+
+// findConstantField returns a constant field declared in a class or one of its
+// supertypes, with the class that declares it
+func (pool *ComponentPool) findConstantField(fullClassName, fieldName string) (string, *javaparser.Field) {
+	for _, className := range append([]string{fullClassName}, pool.AllSuperTypes(fullClassName)...) {
+		decl := pool.declMap[className]
+		if decl == nil {
+			continue
+		}
+		for i := range decl.Fields {
+			field := &decl.Fields[i]
+			if field.Name != fieldName || field.Initializer == "" {
+				continue
+			}
+			if decl.Kind == "interface" || (field.HasModifier("static") && field.HasModifier("final")) {
+				return className, field
+			}
+		}
+	}
+	return "", nil
+}
+
+// AnnotationString returns a String element of an annotation declared in a
+// class, with constants evaluated; an element that is not a compile-time
+// String falls back to its text as parsed
+func (pool *ComponentPool) AnnotationString(fromClass string, ann javaparser.Annotation, key string) string {
+	if value, ok := pool.EvaluateString(fromClass, ann.Expressions[key]); ok {
+		return value
+	}
+	return ann.Attributes[key]
+}
@@ -0,0 +1,120 @@
+package linker
+
+import (
+	"reflect"
+	"testing"
+
+	"spec-recon/internal/analyzer"
+	"spec-recon/internal/xmlparser"
+)
+
+// TestCRUDMatrix verifies SQL table usage on statement nodes and its roll-up to endpoints
+func TestCRUDMatrix(t *testing.T) {
+	sources := []string{
+		`package com.company.user;
+
+@RestController
+@RequestMapping("/users")
+public class UserController {
+    @Autowired
+    private UserService userService;
+
+    @GetMapping
+    public List<UserDto> list(UserSearch search) {
+        return userService.findUsers(search);
+    }
+
+    @PostMapping
+    public void save(@RequestBody UserDto user) {
+        userService.save(user);
+    }
+}
+`,
+		`package com.company.user;
+
+@Service
+public class UserService {
+    @Autowired
+    private UserMapper userMapper;
+
+    public List<UserDto> findUsers(UserSearch search) {
+        return userMapper.selectUsers(search);
+    }
+
+    public void save(UserDto user) {
+        userMapper.insertUser(user);
+        userMapper.insertHistory(user);
+        userMapper.updateLogin(user);
+    }
+}
+`,
+		`package com.company.user;
+
+@Mapper
+public interface UserMapper {
+    List<UserDto> selectUsers(UserSearch search);
+    int insertUser(UserDto user);
+    int insertHistory(UserDto user);
+    int updateLogin(UserDto user);
+}
+`,
+	}
+	mapperXML := `<?xml version="1.0" encoding="UTF-8"?>
+<mapper namespace="com.company.user.UserMapper">
+    <select id="selectUsers">
+        SELECT u.USER_ID, u.USER_NAME FROM TB_USER u
+        <if test="deptName != null">JOIN TB_DEPT d ON d.DEPT_ID = u.DEPT_ID</if>
+        <where>
+            <if test="deptName != null">AND d.DEPT_NAME = #{deptName}</if>
+        </where>
+    </select>
+    <insert id="insertUser">INSERT INTO TB_USER (USER_ID, USER_NAME) VALUES (#{userId}, #{userName})</insert>
+    <insert id="insertHistory">
+        INSERT INTO TB_USER_HIST (USER_ID, ACTION)
+        SELECT USER_ID, 'JOIN' FROM TB_USER WHERE USER_ID = #{userId}
+    </insert>
+    <update id="updateLogin">UPDATE TB_USER SET LAST_LOGIN_DT = SYSDATE WHERE USER_ID = #{userId}</update>
+</mapper>
+`
+
+	pool := NewTestPool(t, sources...)
+	mapper, err := xmlparser.ParseXMLFile(mapperXML)
+	if err != nil {
+		t.Fatalf("ParseXMLFile failed: %v", err)
+	}
+	pool.AddMapperXML(mapper)
+
+	// Tables inside <if> branches count: the statement can touch them
+	sel := pool.GetSQL("com.company.user.UserMapper", "selectUsers")
+	if len(sel.Tables) != 2 || sel.Tables[0].Table != "TB_DEPT" || sel.Tables[1].Table != "TB_USER" {
+		t.Fatalf("Unexpected tables for selectUsers: %+v", sel.Tables)
+	}
+	if got := sel.Tables[0].Columns; !reflect.DeepEqual(got, []string{"DEPT_ID", "DEPT_NAME"}) {
+		t.Errorf("Unexpected TB_DEPT columns: %v", got)
+	}
+
+	matrix := analyzer.BuildCRUDMatrix(NewLinker(pool).BuildCallGraph())
+
+	if want := []string{"TB_DEPT", "TB_USER", "TB_USER_HIST"}; !reflect.DeepEqual(matrix.Tables, want) {
+		t.Fatalf("Expected tables %v, got %v", want, matrix.Tables)
+	}
+	if len(matrix.Rows) != 2 {
+		t.Fatalf("Expected 2 endpoint rows, got %+v", matrix.Rows)
+	}
+	access := make(map[string]map[string]string)
+	for _, row := range matrix.Rows {
+		access[row.Method+" "+row.Handler] = row.Access
+	}
+	tests := map[string]map[string]string{
+		"GET list":  {"TB_DEPT": "R", "TB_USER": "R"},
+		"POST save": {"TB_USER": "CRU", "TB_USER_HIST": "C"},
+	}
+	for endpoint, want := range tests {
+		if !reflect.DeepEqual(access[endpoint], want) {
+			t.Errorf("%s: expected %v, got %v", endpoint, want, access[endpoint])
+		}
+	}
+	if want := []string{"DEPT_ID", "LAST_LOGIN_DT", "USER_ID", "USER_NAME"}; !reflect.DeepEqual(matrix.Columns["TB_USER"], want) {
+		t.Errorf("Expected TB_USER columns %v, got %v", want, matrix.Columns["TB_USER"])
+	}
+}
@@ -0,0 +1,338 @@
+package linker
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"spec-recon/internal/javaparser"
+)
+
+// derivedQueryRegex splits a Spring Data query method name into its action,
+// subject and predicate: findDistinctTop10ByEmailAndStatus
+var derivedQueryRegex = regexp.MustCompile(`^(find|read|get|query|search|stream|count|exists|delete|remove)(\w*?)By(\w*)$`)
+
+// derivedLimitRegex reads the result limit of a subject: Top10, First
+var derivedLimitRegex = regexp.MustCompile(`(?:Top|First)(\d*)`)
+
+// derivedOperator is a predicate keyword of a derived query, matched as the
+// suffix of a criterion; text and sql are format strings over the property
+// (or column) followed by the bound parameters
+type derivedOperator struct {
+	suffix string
+	text   string
+	sql    string
+	args   int
+}
+
+// derivedOperators lists the predicate keywords, longest first where one is
+// the suffix of another ("Is" prefixes are stripped from the property)
+var derivedOperators = []derivedOperator{
+	{"NotNull", "%s is not null", "%s IS NOT NULL", 0},
+	{"Null", "%s is null", "%s IS NULL", 0},
+	{"True", "%s is true", "%s = TRUE", 0},
+	{"False", "%s is false", "%s = FALSE", 0},
+	{"GreaterThanEqual", "%s >= %s", "%s >= %s", 1},
+	{"GreaterThan", "%s > %s", "%s > %s", 1},
+	{"LessThanEqual", "%s <= %s", "%s <= %s", 1},
+	{"LessThan", "%s < %s", "%s < %s", 1},
+	{"After", "%s after %s", "%s > %s", 1},
+	{"Before", "%s before %s", "%s < %s", 1},
+	{"Between", "%s between %s and %s", "%s BETWEEN %s AND %s", 2},
+	{"NotLike", "%s not like %s", "%s NOT LIKE %s", 1},
+	{"Like", "%s like %s", "%s LIKE %s", 1},
+	{"StartingWith", "%s starts with %s", "%s LIKE %s", 1},
+	{"StartsWith", "%s starts with %s", "%s LIKE %s", 1},
+	{"EndingWith", "%s ends with %s", "%s LIKE %s", 1},
+	{"EndsWith", "%s ends with %s", "%s LIKE %s", 1},
+	{"NotContaining", "%s does not contain %s", "%s NOT LIKE %s", 1},
+	{"Containing", "%s contains %s", "%s LIKE %s", 1},
+	{"Contains", "%s contains %s", "%s LIKE %s", 1},
+	{"NotIn", "%s not in %s", "%s NOT IN (%s)", 1},
+	{"In", "%s in %s", "%s IN (%s)", 1},
+	{"Not", "%s <> %s", "%s <> %s", 1},
+	{"Is", "%s = %s", "%s = %s", 1},
+	{"Equals", "%s = %s", "%s = %s", 1},
+	{"", "%s = %s", "%s = %s", 1},
+}
+
+// derivedActions describes each derived query action
+var derivedActions = map[string]string{
+	"find": "Find", "count": "Count", "exists": "Check existence of", "delete": "Delete",
+}
+
+// derivedQuery is a Spring Data query method name, parsed
+type derivedQuery struct {
+	Action   string        // find, count, exists or delete
+	Distinct bool          // findDistinctBy...
+	Limit    int           // findTop10By... (0 for no limit)
+	Criteria [][]criterion // Or-ed groups of And-ed criteria
+	Order    []sortOrder   // OrderBy... clause
+}
+
+// criterion is one property condition of a derived query
+type criterion struct {
+	Property   string // Property path as written, decapitalized: "email", "deptId"
+	Operator   derivedOperator
+	IgnoreCase bool
+}
+
+// sortOrder is one OrderBy property of a derived query
+type sortOrder struct {
+	Property string
+	Desc     bool
+}
+
+// parseDerivedQuery parses a query method name the way Spring Data's
+// PartTree does; false when the name is not a derived query
+// hasProperty settles names a keyword could end ("loggedIn" is a property,
+// not "logged" In); nil accepts any property
+func parseDerivedQuery(methodName string, hasProperty func(string) bool) (*derivedQuery, bool) {
+	match := derivedQueryRegex.FindStringSubmatch(methodName)
+	if match == nil {
+		return nil, false
+	}
+	query := &derivedQuery{Action: match[1]}
+	switch query.Action {
+	case "read", "get", "query", "search", "stream":
+		query.Action = "find"
+	case "remove":
+		query.Action = "delete"
+	}
+
+	subject := match[2]
+	query.Distinct = strings.Contains(subject, "Distinct")
+	if limit := derivedLimitRegex.FindStringSubmatch(subject); limit != nil {
+		query.Limit = 1
+		if n, err := strconv.Atoi(limit[1]); err == nil {
+			query.Limit = n
+		}
+	}
+
+	predicate := match[3]
+	if idx := strings.Index(predicate, "OrderBy"); idx >= 0 {
+		query.Order = parseSortOrders(predicate[idx+len("OrderBy"):])
+		predicate = predicate[:idx]
+	}
+	allIgnoreCase := false
+	for _, suffix := range []string{"AllIgnoreCase", "AllIgnoringCase"} {
+		if strings.HasSuffix(predicate, suffix) {
+			predicate = strings.TrimSuffix(predicate, suffix)
+			allIgnoreCase = true
+		}
+	}
+	if predicate == "" {
+		return query, true
+	}
+
+	for _, group := range splitKeyword(predicate, "Or") {
+		var criteria []criterion
+		for _, part := range splitKeyword(group, "And") {
+			c, ok := parseCriterion(part, hasProperty)
+			if !ok {
+				return nil, false
+			}
+			c.IgnoreCase = c.IgnoreCase || allIgnoreCase
+			criteria = append(criteria, c)
+		}
+		query.Criteria = append(query.Criteria, criteria)
+	}
+	return query, true
+}
+
+// parseCriterion reads a property and its operator: "EmailIsNotNull"
+// The longest operator leaving a known property wins, else the longest one
+func parseCriterion(part string, hasProperty func(string) bool) (criterion, bool) {
+	var c criterion
+	for _, suffix := range []string{"IgnoreCase", "IgnoringCase"} {
+		if strings.HasSuffix(part, suffix) {
+			part = strings.TrimSuffix(part, suffix)
+			c.IgnoreCase = true
+		}
+	}
+	found := false
+	for _, op := range derivedOperators {
+		if !strings.HasSuffix(part, op.suffix) {
+			continue
+		}
+		property := strings.TrimSuffix(part, op.suffix)
+		if op.suffix != "Is" {
+			property = strings.TrimSuffix(property, "Is")
+		}
+		if property == "" {
+			continue
+		}
+		if !found {
+			c.Property, c.Operator, found = javaparser.Decapitalize(property), op, true
+		}
+		if hasProperty == nil || hasProperty(javaparser.Decapitalize(property)) {
+			c.Property, c.Operator = javaparser.Decapitalize(property), op
+			return c, true
+		}
+	}
+	return c, found
+}
+
+// parseSortOrders reads an OrderBy clause: "CreatedAtDescNameAsc"
+func parseSortOrders(clause string) []sortOrder {
+	var orders []sortOrder
+	for clause != "" {
+		asc, desc := keywordIndex(clause, "Asc"), keywordIndex(clause, "Desc")
+		end, isDesc := len(clause), false
+		if asc >= 0 && (desc < 0 || asc < desc) {
+			end = asc
+		} else if desc >= 0 {
+			end, isDesc = desc, true
+		}
+		if end > 0 {
+			orders = append(orders, sortOrder{Property: javaparser.Decapitalize(clause[:end]), Desc: isDesc})
+		}
+		if end == len(clause) {
+			break
+		}
+		keyword := "Asc"
+		if isDesc {
+			keyword = "Desc"
+		}
+		clause = clause[end+len(keyword):]
+	}
+	return orders
+}
+
+// splitKeyword splits a predicate at a keyword standing as a whole camel-case
+// word: "EmailAndStatus" -> ["Email", "Status"], leaving "Brand" intact
+func splitKeyword(s, keyword string) []string {
+	var parts []string
+	for {
+		idx := keywordIndex(s, keyword)
+		if idx < 0 {
+			return append(parts, s)
+		}
+		parts = append(parts, s[:idx])
+		s = s[idx+len(keyword):]
+	}
+}
+
+// keywordIndex finds a keyword that starts a camel-case word and is followed
+// by another word (or ends s), or -1
+func keywordIndex(s, keyword string) int {
+	for from := 1; from < len(s); {
+		idx := strings.Index(s[from:], keyword)
+		if idx < 0 {
+			return -1
+		}
+		idx += from
+		next := idx + len(keyword)
+		if next == len(s) || (s[next] >= 'A' && s[next] <= 'Z') {
+			return idx
+		}
+		from = idx + 1
+	}
+	return -1
+}
+
+// describe renders a derived query as a readable sentence over property
+// names and method parameters: "Find UserAccount where email = :email"
+func (q *derivedQuery) describe(entityName string, params []string) string {
+	var sb strings.Builder
+	sb.WriteString(derivedActions[q.Action] + " ")
+	if q.Distinct {
+		sb.WriteString("distinct ")
+	}
+	if q.Limit == 1 {
+		sb.WriteString("first ")
+	} else if q.Limit > 1 {
+		sb.WriteString(fmt.Sprintf("first %d ", q.Limit))
+	}
+	sb.WriteString(entityName)
+	if where := q.conditions(params, "where", "and", "or", func(c criterion) (string, string) {
+		text := c.Property
+		if c.IgnoreCase {
+			return text, " (ignoring case)"
+		}
+		return text, ""
+	}, func(op derivedOperator) string { return op.text }); where != "" {
+		sb.WriteString(" " + where)
+	}
+	if len(q.Order) > 0 {
+		var orders []string
+		for _, order := range q.Order {
+			if order.Desc {
+				orders = append(orders, order.Property+" desc")
+			} else {
+				orders = append(orders, order.Property)
+			}
+		}
+		sb.WriteString(" order by " + strings.Join(orders, ", "))
+	}
+	return sb.String()
+}
+
+// toSQL renders a derived query as SQL over the entity's table and columns
+func (q *derivedQuery) toSQL(entity *jpaEntity, params []string) string {
+	var sb strings.Builder
+	switch q.Action {
+	case "count":
+		sb.WriteString("SELECT COUNT(*) FROM " + entity.Table)
+	case "exists":
+		sb.WriteString("SELECT 1 FROM " + entity.Table)
+	case "delete":
+		sb.WriteString("DELETE FROM " + entity.Table)
+	default:
+		sb.WriteString("SELECT ")
+		if q.Distinct {
+			sb.WriteString("DISTINCT ")
+		}
+		sb.WriteString("* FROM " + entity.Table)
+	}
+	if where := q.conditions(params, "WHERE", "AND", "OR", func(c criterion) (string, string) {
+		return entity.column(c.Property), ""
+	}, func(op derivedOperator) string { return op.sql }); where != "" {
+		sb.WriteString(" " + where)
+	}
+	if len(q.Order) > 0 {
+		var orders []string
+		for _, order := range q.Order {
+			column := entity.column(order.Property)
+			if order.Desc {
+				column += " DESC"
+			}
+			orders = append(orders, column)
+		}
+		sb.WriteString(" ORDER BY " + strings.Join(orders, ", "))
+	}
+	if q.Limit > 0 && q.Action == "find" {
+		sb.WriteString(" LIMIT " + strconv.Itoa(q.Limit))
+	}
+	return sb.String()
+}
+
+// conditions renders the criteria with the given keywords, binding the method
+// parameters in order (":name", or "?" once they run out)
+func (q *derivedQuery) conditions(params []string, where, and, or string,
+	subject func(criterion) (string, string), format func(derivedOperator) string) string {
+	if len(q.Criteria) == 0 {
+		return ""
+	}
+	next := 0
+	var groups []string
+	for _, group := range q.Criteria {
+		var parts []string
+		for _, c := range group {
+			name, note := subject(c)
+			args := []any{name}
+			for i := 0; i < c.Operator.args; i++ {
+				if next < len(params) {
+					args = append(args, ":"+params[next])
+				} else {
+					args = append(args, "?")
+				}
+				next++
+			}
+			parts = append(parts, fmt.Sprintf(format(c.Operator), args...)+note)
+		}
+		groups = append(groups, strings.Join(parts, " "+and+" "))
+	}
+	return where + " " + strings.Join(groups, " "+or+" ")
+}
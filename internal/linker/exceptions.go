@@ -0,0 +1,131 @@
+package linker
+
+import (
+	"strings"
+
+	"spec-recon/internal/javaparser"
+	"spec-recon/internal/model"
+)
+
+// ResolveExceptions records on every method the exceptions it declares
+// (throws clause) or throws (throw new X, orElseThrow(X::new)), and reads
+// @ExceptionHandler methods, their @ControllerAdvice scope, and the
+// @ResponseStatus codes of methods and exception classes
+func (pool *ComponentPool) ResolveExceptions() {
+	for fullClassName, decl := range pool.declMap {
+		if ann, ok := findAnnotation(decl.Annotations, "ResponseStatus"); ok {
+			pool.ClassMap[fullClassName].ResponseStatus = responseStatus(ann)
+		}
+
+		advice, isAdvice := findAnnotation(decl.Annotations, "ControllerAdvice")
+		if !isAdvice {
+			advice, isAdvice = findAnnotation(decl.Annotations, "RestControllerAdvice")
+		}
+		var basePackages []string
+		if isAdvice {
+			basePackages = pool.annotationValues(fullClassName, advice, "basePackages", "value")
+			for _, element := range javaparser.ArrayElements(advice.Attributes["basePackageClasses"]) {
+				className := pool.ResolveTypeName(fullClassName, strings.TrimSuffix(element, ".class"))
+				basePackages = append(basePackages, extractPackage(className))
+			}
+		}
+
+		for i := range decl.Methods {
+			method := &decl.Methods[i]
+			node := pool.MethodMap[fullClassName+"."+method.Signature()]
+			if node == nil {
+				continue
+			}
+
+			node.Throws = nil
+			for _, name := range append(append([]string(nil), method.Throws...), thrownExceptions(method.Body)...) {
+				if resolved := pool.ResolveTypeName(fullClassName, name); !containsString(node.Throws, resolved) {
+					node.Throws = append(node.Throws, resolved)
+				}
+			}
+			if ann, ok := findAnnotation(method.Annotations, "ResponseStatus"); ok {
+				node.ResponseStatus = responseStatus(ann)
+			}
+
+			ann, ok := findAnnotation(method.Annotations, "ExceptionHandler")
+			if !ok {
+				continue
+			}
+			handler := &model.ExceptionHandler{Advice: isAdvice, BasePackages: basePackages}
+			for _, element := range javaparser.ArrayElements(ann.Attributes["value"]) {
+				handler.Exceptions = append(handler.Exceptions, pool.ResolveTypeName(fullClassName, strings.TrimSuffix(element, ".class")))
+			}
+			if len(handler.Exceptions) == 0 {
+				// @ExceptionHandler without a value handles its exception parameters
+				for _, param := range method.Parameters {
+					if isExceptionName(param.Type) {
+						handler.Exceptions = append(handler.Exceptions, pool.ResolveTypeName(fullClassName, param.Type))
+					}
+				}
+			}
+			node.ExceptionHandler = handler
+		}
+	}
+}
+
+// responseStatus reads the code of a @ResponseStatus annotation
+func responseStatus(ann javaparser.Annotation) int {
+	for _, key := range []string{"value", "code"} {
+		if value, ok := ann.Attributes[key]; ok {
+			return model.HTTPStatusCode(value)
+		}
+	}
+	return 0
+}
+
+// isExceptionName reports whether a type name reads as an exception type
+func isExceptionName(typeName string) bool {
+	return strings.HasSuffix(typeName, "Exception") || strings.HasSuffix(typeName, "Error") || typeName == "Throwable"
+}
+
+// thrownExceptions finds the exception types a method body throws:
+// throw new X(...), and the supplier of Optional.orElseThrow
+// (() -> new X(...) or X::new; NoSuchElementException without one)
+func thrownExceptions(body string) []string {
+	var names []string
+	tokens := javaparser.Tokenize(body)
+	for i := 0; i+1 < len(tokens); i++ {
+		switch {
+		case tokens[i].Is("throw") && tokens[i+1].Is("new"):
+			if name := qualifiedName(tokens, i+2); name != "" {
+				names = append(names, name)
+			}
+		case tokens[i].Kind == javaparser.TokenIdent && tokens[i].Text == "orElseThrow" && tokens[i+1].Is("("):
+			end := closingParen(tokens, i+1)
+			if end == i+2 {
+				names = append(names, "NoSuchElementException")
+				continue
+			}
+			for j := i + 2; j < end; j++ {
+				name := ""
+				if tokens[j].Is("new") {
+					name = qualifiedName(tokens, j+1)
+				} else if tokens[j].Is("::") && tokens[j+1].Is("new") {
+					name = qualifiedName(tokens, i+2)
+				}
+				if name != "" {
+					names = append(names, name)
+					break
+				}
+			}
+		}
+	}
+	return names
+}
+
+// qualifiedName reads a (possibly qualified) type name starting at tokens[from]
+func qualifiedName(tokens []javaparser.Token, from int) string {
+	if from >= len(tokens) || tokens[from].Kind != javaparser.TokenIdent {
+		return ""
+	}
+	name := tokens[from].Text
+	for i := from + 1; i+1 < len(tokens) && tokens[i].Is(".") && tokens[i+1].Kind == javaparser.TokenIdent; i += 2 {
+		name += "." + tokens[i+1].Text
+	}
+	return name
+}
@@ -0,0 +1,193 @@
+package linker
+
+import (
+	"sort"
+	"strings"
+
+	"spec-recon/internal/model"
+)
+
+// BuildHierarchy resolves the extends/implements clauses of every class in the
+// pool to full class names and indexes the concrete implementations of each type
+// It must run after all classes are added, since supertypes may be declared later
+func (pool *ComponentPool) BuildHierarchy() {
+	pool.SuperTypeMap = make(map[string][]string)
+	pool.ImplementationMap = make(map[string][]string)
+
+	for fullClassName, decl := range pool.declMap {
+		classNode := pool.ClassMap[fullClassName]
+
+		var superTypes []string
+		if decl.SuperClass != "" {
+			classNode.SuperClass = pool.ResolveTypeName(fullClassName, decl.SuperClass)
+			superTypes = append(superTypes, classNode.SuperClass)
+		}
+		classNode.Interfaces = nil
+		for _, iface := range decl.Interfaces {
+			resolved := pool.ResolveTypeName(fullClassName, iface)
+			classNode.Interfaces = append(classNode.Interfaces, resolved)
+			superTypes = append(superTypes, resolved)
+		}
+		pool.SuperTypeMap[fullClassName] = superTypes
+	}
+
+	for fullClassName, decl := range pool.declMap {
+		if !decl.IsConcrete() {
+			continue
+		}
+		for _, superType := range pool.AllSuperTypes(fullClassName) {
+			pool.ImplementationMap[superType] = append(pool.ImplementationMap[superType], fullClassName)
+		}
+	}
+	for _, impls := range pool.ImplementationMap {
+		sort.Strings(impls)
+	}
+
+	pool.resolveMappings()
+}
+
+// AllSuperTypes returns every supertype of a class, nearest first
+// (superclass chain and interfaces, transitively); cycles are ignored
+func (pool *ComponentPool) AllSuperTypes(fullClassName string) []string {
+	var result []string
+	seen := map[string]bool{fullClassName: true}
+	queue := []string{fullClassName}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, superType := range pool.SuperTypeMap[current] {
+			if seen[superType] {
+				continue
+			}
+			seen[superType] = true
+			result = append(result, superType)
+			queue = append(queue, superType)
+		}
+	}
+	return result
+}
+
+// FindImplementations returns the concrete classes that implement or extend a type
+func (pool *ComponentPool) FindImplementations(fullTypeName string) []string {
+	return pool.ImplementationMap[fullTypeName]
+}
+
+// ResolveTypeName resolves a type name as written in a class to a full class name
+// Lookup order: qualified name, member types of the class and its enclosing
+// classes, single-type imports, same package, on-demand imports, then a
+// project-wide simple-name match. Types that cannot be found
+// in the project are returned as written: the import path when there is one,
+// otherwise the erased simple name
+func (pool *ComponentPool) ResolveTypeName(fromClass, typeName string) string {
+	name := typeName
+	if idx := strings.Index(name, "<"); idx >= 0 {
+		name = name[:idx]
+	}
+	name = strings.TrimSpace(name)
+
+	if strings.Contains(name, ".") {
+		if pool.ClassMap[name] != nil {
+			return name
+		}
+	}
+	simpleName := name[strings.LastIndex(name, ".")+1:]
+
+	var imports []string
+	pkg := extractPackage(fromClass)
+	if decl := pool.declMap[fromClass]; decl != nil {
+		imports, pkg = decl.Imports, decl.Package
+	}
+
+	for scope := fromClass; pool.declMap[scope] != nil; scope = extractPackage(scope) {
+		if candidate := scope + "." + name; pool.ClassMap[candidate] != nil {
+			return candidate
+		}
+	}
+
+	for _, imp := range imports {
+		if strings.HasSuffix(imp, "."+simpleName) {
+			return imp
+		}
+	}
+
+	if candidate := pkg + "." + name; pool.ClassMap[candidate] != nil {
+		return candidate
+	}
+	if candidate := pkg + "." + simpleName; pool.ClassMap[candidate] != nil {
+		return candidate
+	}
+
+	for _, imp := range imports {
+		if strings.HasSuffix(imp, ".*") {
+			if candidate := strings.TrimSuffix(imp, "*") + simpleName; pool.ClassMap[candidate] != nil {
+				return candidate
+			}
+		}
+	}
+
+	for fullName := range pool.ClassMap {
+		if extractSimpleTypeName(fullName) == simpleName {
+			return fullName
+		}
+	}
+
+	if name != simpleName {
+		return name
+	}
+	return simpleName
+}
+
+// isAbstractMethod reports whether a method node has no implementation of its own
+func (pool *ComponentPool) isAbstractMethod(methodKey string) bool {
+	return pool.abstractMethods[methodKey]
+}
+
+// FindOverride returns the method that implements method in class implClass:
+// the method with the same signature declared there or inherited from one of
+// its superclasses. When generic parameters make the signature differ, a
+// single candidate with the same name and arity is accepted
+func (pool *ComponentPool) FindOverride(implClass string, method *model.Node) *model.Node {
+	_, signature := splitSignature(method.ID)
+	types := append([]string{implClass}, pool.superClassChain(implClass)...)
+
+	for _, className := range types {
+		if node := pool.MethodMap[className+"."+signature]; node != nil && !pool.isAbstractMethod(node.ID) {
+			return node
+		}
+
+		var candidates []*model.Node
+		for _, overload := range pool.FindMethodByName(className, method.Method) {
+			if len(overload.ParamTypes) == len(method.ParamTypes) && !pool.isAbstractMethod(overload.ID) {
+				candidates = append(candidates, overload)
+			}
+		}
+		if len(candidates) == 1 {
+			return candidates[0]
+		}
+	}
+	return nil
+}
+
+// superClassChain returns the superclasses of a class, nearest first
+func (pool *ComponentPool) superClassChain(fullClassName string) []string {
+	var chain []string
+	seen := map[string]bool{fullClassName: true}
+	for current := pool.ClassMap[fullClassName]; current != nil && current.SuperClass != ""; current = pool.ClassMap[current.SuperClass] {
+		if seen[current.SuperClass] {
+			break
+		}
+		seen[current.SuperClass] = true
+		chain = append(chain, current.SuperClass)
+	}
+	return chain
+}
+
+// splitSignature splits "pkg.Class.save(UserDto)" into "pkg.Class" and "save(UserDto)"
+func splitSignature(methodKey string) (string, string) {
+	className, _ := splitMethodKey(methodKey)
+	if className == "" {
+		return "", methodKey
+	}
+	return className, methodKey[len(className)+1:]
+}
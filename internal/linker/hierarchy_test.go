@@ -0,0 +1,220 @@
+package linker
+
+import (
+	"testing"
+
+	"spec-recon/internal/javaparser"
+)
+
+// TestInterfaceImplementationLinking verifies that calls on an injected interface
+// continue into every concrete implementation, including inherited overrides
+func TestInterfaceImplementationLinking(t *testing.T) {
+	sources := []string{
+		`package com.company.modern.service;
+
+public interface UserService {
+    User find(Long id);
+}
+`,
+		`package com.company.modern.service.impl;
+
+import com.company.modern.service.UserService;
+
+@Service
+public class UserServiceImpl implements UserService {
+    private UserMapper userMapper;
+
+    public User find(Long id) {
+        return userMapper.selectById(id);
+    }
+}
+`,
+		`package com.company.modern.service.impl;
+
+import com.company.modern.service.UserService;
+
+public abstract class AbstractCachedUserService implements UserService {
+    public User find(Long id) {
+        return null;
+    }
+}
+`,
+		`package com.company.modern.service.impl;
+
+@Service
+public class CachedUserService extends AbstractCachedUserService {
+}
+`,
+		`package com.company.modern.controller;
+
+import com.company.modern.service.UserService;
+
+@RestController
+public class UserController {
+    private UserService userService;
+
+    @GetMapping("/users/{id}")
+    public User get(Long id) {
+        return userService.find(id);
+    }
+}
+`,
+	}
+
+	pool := NewTestPool(t, sources...)
+	if err := NewLinker(pool).Link(); err != nil {
+		t.Fatalf("Link failed: %v", err)
+	}
+
+	impl := pool.GetClass("com.company.modern.service.impl.UserServiceImpl")
+	if len(impl.Interfaces) != 1 || impl.Interfaces[0] != "com.company.modern.service.UserService" {
+		t.Errorf("Unexpected resolved interfaces %v", impl.Interfaces)
+	}
+	if got := pool.GetClass("com.company.modern.service.impl.CachedUserService").SuperClass; got != "com.company.modern.service.impl.AbstractCachedUserService" {
+		t.Errorf("Unexpected resolved superclass %q", got)
+	}
+
+	impls := pool.FindImplementations("com.company.modern.service.UserService")
+	if len(impls) != 2 {
+		t.Fatalf("Expected 2 concrete implementations, got %v", impls)
+	}
+
+	get := pool.GetMethod("com.company.modern.controller.UserController.get")
+	if len(get.Children) != 1 || get.Children[0].ID != "com.company.modern.service.UserService.find(Long)" {
+		t.Fatalf("Expected call to the interface method, got %v", get.Children)
+	}
+
+	ifaceMethod := get.Children[0]
+	want := map[string]bool{
+		"com.company.modern.service.impl.UserServiceImpl.find(Long)":           true,
+		"com.company.modern.service.impl.AbstractCachedUserService.find(Long)": true,
+	}
+	if len(ifaceMethod.Children) != len(want) {
+		t.Fatalf("Expected %d implementations, got %d", len(want), len(ifaceMethod.Children))
+	}
+	for _, child := range ifaceMethod.Children {
+		if !want[child.ID] {
+			t.Errorf("Unexpected implementation %s", child.ID)
+		}
+		if child.ImplementationOf != "com.company.modern.service.UserService" || child.ImplementationCount != 2 {
+			t.Errorf("Implementation %s not annotated: %q (%d)", child.ID, child.ImplementationOf, child.ImplementationCount)
+		}
+		if note := child.ImplementationNote(); note != "[Impl of 2] UserService" {
+			t.Errorf("Unexpected note %q", note)
+		}
+	}
+}
+
+// TestInheritedFieldsAndMappings verifies that injected fields and the class-level
+// @RequestMapping of an abstract base controller apply to its subclasses
+func TestInheritedFieldsAndMappings(t *testing.T) {
+	sources := []string{
+		`package com.company.common;
+
+import com.company.modern.service.AuditService;
+
+@RequestMapping("/api/v1")
+public abstract class BaseController {
+    @Autowired
+    protected AuditService auditService;
+}
+`,
+		`package com.company.modern.service;
+
+@Service
+public class AuditService {
+    public void record(String action) {}
+}
+`,
+		`package com.company.modern.controller;
+
+import com.company.common.BaseController;
+
+@RestController
+public class OrderController extends BaseController {
+    @PostMapping("/orders")
+    public void create(String orderNo) {
+        auditService.record(orderNo);
+    }
+}
+`,
+		`package com.company.modern.controller;
+
+import com.company.common.BaseController;
+
+@RestController
+@RequestMapping("/admin")
+public class AdminController extends BaseController {
+    @GetMapping("/stats")
+    public void stats() {}
+}
+`,
+	}
+
+	pool := NewComponentPool()
+	for _, src := range sources {
+		cls, err := javaparser.ParseJavaFile(src)
+		if err != nil {
+			t.Fatalf("ParseJavaFile failed: %v", err)
+		}
+		pool.AddJavaClass(cls, src)
+	}
+	if err := NewLinker(pool).Link(); err != nil {
+		t.Fatalf("Link failed: %v", err)
+	}
+
+	if got := pool.ResolveFieldType("com.company.modern.controller.OrderController", "auditService"); got != "com.company.modern.service.AuditService" {
+		t.Errorf("Inherited field resolved to %q", got)
+	}
+
+	create := pool.GetMethod("com.company.modern.controller.OrderController.create")
+	if create.URL != "/api/v1/orders" {
+		t.Errorf("Expected inherited prefix in URL, got %q", create.URL)
+	}
+	if len(create.Children) != 1 || create.Children[0].ID != "com.company.modern.service.AuditService.record(String)" {
+		t.Errorf("Expected call through inherited field, got %v", create.Children)
+	}
+
+	if stats := pool.GetMethod("com.company.modern.controller.AdminController.stats"); stats.URL != "/admin/stats" {
+		t.Errorf("Own class-level mapping should win, got %q", stats.URL)
+	}
+}
+
+// TestImplementationNoteOwner verifies that a method implementing the methods
+// of several interfaces is noted as the implementation of the same one on
+// every run
+func TestImplementationNoteOwner(t *testing.T) {
+	sources := []string{
+		`package com.company.order;
+
+public interface Saver {
+    void save(Order order);
+}
+`,
+		`package com.company.order;
+
+public interface Auditable {
+    void save(Order order);
+}
+`,
+		`package com.company.order;
+
+@Service
+public class OrderServiceImpl implements Saver, Auditable {
+    public void save(Order order) {
+    }
+}
+`,
+	}
+
+	for i := 0; i < 10; i++ {
+		pool := NewTestPool(t, sources...)
+		if err := NewLinker(pool).Link(); err != nil {
+			t.Fatalf("Link failed: %v", err)
+		}
+		impl := pool.GetMethod("com.company.order.OrderServiceImpl.save(Order)")
+		if note := impl.ImplementationNote(); note != "[Impl] Auditable" {
+			t.Fatalf("Run %d: unexpected note %q", i, note)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package linker
+
+import (
+	"spec-recon/internal/javaparser"
+	"spec-recon/internal/model"
+)
+
+// registerBean records a stereotype-annotated class under its bean name and
+// under its class-level @Qualifier / @Named value
+func (pool *ComponentPool) registerBean(javaClass *javaparser.JavaClass, classNode *model.Node) {
+	classNode.BeanName = javaClass.BeanName()
+	if classNode.BeanName == "" {
+		return
+	}
+	pool.RegisterBean(classNode.BeanName, classNode.ID)
+
+	for _, ann := range javaClass.Annotations {
+		switch ann.Name {
+		case "Qualifier", "Named":
+			if value := ann.Attributes["value"]; value != "" {
+				pool.RegisterBean(value, classNode.ID)
+			}
+		case "Primary":
+			pool.primaryBeans[classNode.ID] = true
+		}
+	}
+}
+
+// RegisterBean maps a bean name to the class that implements it
+func (pool *ComponentPool) RegisterBean(beanName, fullClassName string) {
+	pool.BeanMap[beanName] = fullClassName
+}
+
+// ResolveBeanType resolves the class a call on a variable is dispatched to
+// For an injection point whose declared type has several bean implementations,
+// the bean is picked by qualifier (@Qualifier, @Named, @Resource(name)), then by
+// @Primary, then by the injection point's name, as Spring does. Otherwise the
+// declared type is returned and calls on interfaces reach every implementation
+// through linkImplementations. Non-injected fields fall back to ResolveFieldType
+func (pool *ComponentPool) ResolveBeanType(fullClassName, name string) string {
+	declaringClass, point := pool.findInjection(fullClassName, name)
+	if point == nil {
+		return pool.ResolveFieldType(fullClassName, name)
+	}
+
+	declared := pool.ResolveTypeName(declaringClass, point.Type)
+	if pool.ClassMap[declared] == nil {
+		return pool.ResolveFieldType(fullClassName, name)
+	}
+
+	candidates := pool.beanCandidates(declared)
+	if point.Qualifier != "" {
+		if bean := pool.BeanMap[point.Qualifier]; bean != "" && (bean == declared || containsString(candidates, bean)) {
+			return bean
+		}
+	}
+	if len(candidates) < 2 {
+		return declared
+	}
+
+	var primary []string
+	for _, candidate := range candidates {
+		if pool.primaryBeans[candidate] {
+			primary = append(primary, candidate)
+		}
+	}
+	if len(primary) == 1 {
+		return primary[0]
+	}
+	if bean := pool.BeanMap[point.Name]; bean != "" && containsString(candidates, bean) {
+		return bean
+	}
+	return declared
+}
+
+// findInjection returns the injection point stored in a field of the class or
+// one of its superclasses, with the class that declares it
+func (pool *ComponentPool) findInjection(fullClassName, name string) (string, *model.InjectionInfo) {
+	for _, className := range append([]string{fullClassName}, pool.superClassChain(fullClassName)...) {
+		if classNode := pool.ClassMap[className]; classNode != nil {
+			if point := classNode.GetInjection(name); point != nil {
+				return className, point
+			}
+		}
+	}
+	return "", nil
+}
+
+// beanCandidates returns the concrete implementations of a type that are beans
+// When none of them is a registered bean, all implementations are returned
+func (pool *ComponentPool) beanCandidates(fullTypeName string) []string {
+	impls := pool.FindImplementations(fullTypeName)
+	var beans []string
+	for _, impl := range impls {
+		if classNode := pool.ClassMap[impl]; classNode != nil && classNode.BeanName != "" {
+			beans = append(beans, impl)
+		}
+	}
+	if len(beans) == 0 {
+		return impls
+	}
+	return beans
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package linker
+
+import "testing"
+
+// TestQualifiedInjection verifies that @Qualifier and @Primary pick one bean among several implementations
+func TestQualifiedInjection(t *testing.T) {
+	sources := []string{
+		`package com.company.pay;
+
+public interface PaymentService {
+    void pay(Long orderId);
+}
+`,
+		`package com.company.pay;
+
+@Service("cardPayment")
+@Primary
+public class CardPaymentService implements PaymentService {
+    public void pay(Long orderId) {}
+}
+`,
+		`package com.company.pay;
+
+@Service
+public class BankPaymentService implements PaymentService {
+    public void pay(Long orderId) {}
+}
+`,
+		`package com.company.order;
+
+import com.company.pay.PaymentService;
+
+@Service
+@RequiredArgsConstructor
+public class OrderService {
+    @Qualifier("bankPaymentService")
+    private final PaymentService bankPayment;
+    private final PaymentService payment;
+
+    public void checkout(Long orderId) {
+        bankPayment.pay(orderId);
+    }
+
+    public void quickCheckout(Long orderId) {
+        payment.pay(orderId);
+    }
+}
+`,
+	}
+
+	pool := NewTestPool(t, sources...)
+	if err := NewLinker(pool).Link(); err != nil {
+		t.Fatalf("Link failed: %v", err)
+	}
+
+	if bean := pool.BeanMap["cardPayment"]; bean != "com.company.pay.CardPaymentService" {
+		t.Errorf("Explicit bean name not registered, got %q", bean)
+	}
+
+	checkout := pool.GetMethod("com.company.order.OrderService.checkout")
+	if len(checkout.Children) != 1 || checkout.Children[0].ID != "com.company.pay.BankPaymentService.pay(Long)" {
+		t.Errorf("@Qualifier should select BankPaymentService, got %v", checkout.Children)
+	}
+
+	quick := pool.GetMethod("com.company.order.OrderService.quickCheckout")
+	if len(quick.Children) != 1 || quick.Children[0].ID != "com.company.pay.CardPaymentService.pay(Long)" {
+		t.Errorf("@Primary should select CardPaymentService, got %v", quick.Children)
+	}
+}
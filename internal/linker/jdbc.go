@@ -0,0 +1,275 @@
+package linker
+
+import (
+	"fmt"
+	"strings"
+
+	"spec-recon/internal/javaparser"
+	"spec-recon/internal/model"
+)
+
+// jdbcMethods are the JdbcTemplate, NamedParameterJdbcTemplate and JDBC
+// operations whose first argument is SQL text
+var jdbcMethods = map[string]bool{
+	"query": true, "queryForObject": true, "queryForList": true, "queryForMap": true,
+	"queryForRowSet": true, "queryForStream": true, "queryForLong": true, "queryForInt": true,
+	"update": true, "batchUpdate": true, "execute": true,
+	"prepareStatement": true, "prepareCall": true,
+	"executeQuery": true, "executeUpdate": true, "executeLargeUpdate": true, "addBatch": true,
+}
+
+// jdbcTypes are the receiver types of those operations
+var jdbcTypes = map[string]bool{
+	"JdbcTemplate": true, "NamedParameterJdbcTemplate": true, "SimpleJdbcTemplate": true,
+	"JdbcOperations": true, "NamedParameterJdbcOperations": true,
+	"Connection": true, "Statement": true, "PreparedStatement": true, "CallableStatement": true,
+}
+
+// jdbcGetters are the JdbcDaoSupport accessors returning a template
+var jdbcGetters = map[string]bool{
+	"getJdbcTemplate": true, "getNamedParameterJdbcTemplate": true, "getSimpleJdbcTemplate": true,
+}
+
+// JdbcCall is a call that runs SQL text through JDBC, e.g.,
+// jdbcTemplate.query(SELECT_USER, rowMapper) or conn.prepareStatement(sql)
+type JdbcCall struct {
+	Receiver   string // Variable name, or the getter for getJdbcTemplate().query(...)
+	Getter     bool   // Receiver is a JdbcDaoSupport getter call
+	MethodName string // query, update, prepareStatement, ...
+	SQLExpr    string // Source text of the SQL argument
+	Offset     int    // Byte offset of the call in the source
+	Line       int    // 1-based line of the call in the source
+}
+
+// FindJdbcCalls finds calls to JDBC operations with arguments in a method
+// body; receiver types are checked by the linker
+func FindJdbcCalls(source string) []JdbcCall {
+	var calls []JdbcCall
+
+	tokens := javaparser.Tokenize(source)
+	for i := 2; i+1 < len(tokens); i++ {
+		name, paren := tokens[i], tokens[i+1]
+		if name.Kind != javaparser.TokenIdent || !jdbcMethods[name.Text] || !paren.Is("(") || !tokens[i-1].Is(".") {
+			continue
+		}
+		call := JdbcCall{MethodName: name.Text, Offset: name.Pos, Line: name.Line}
+		switch receiver := tokens[i-2]; {
+		case receiver.Kind == javaparser.TokenIdent:
+			call.Receiver = receiver.Text
+		case receiver.Is(")") && i >= 4 && tokens[i-3].Is("(") && tokens[i-4].Kind == javaparser.TokenIdent:
+			call.Receiver, call.Getter = tokens[i-4].Text, true
+		default:
+			continue
+		}
+		args := splitArguments(source, tokens, i+1)
+		if len(args) == 0 {
+			continue // PreparedStatement.executeQuery() runs the SQL prepared earlier
+		}
+		call.SQLExpr = args[0]
+		calls = append(calls, call)
+	}
+
+	return calls
+}
+
+// linkJdbcCalls attaches a SQL node to each method that runs SQL text with
+// JdbcTemplate, NamedParameterJdbcTemplate or plain JDBC. The SQL is
+// resolved from literals, constants and the local variable (or StringBuilder)
+// it was built in; values only known at run time read as "?"
+func (l *Linker) linkJdbcCalls() error {
+	for methodKey, methodNode := range l.Pool.MethodMap {
+		body := l.Pool.MethodBodyMap[methodKey]
+		if body == "" {
+			continue
+		}
+		fullClassName, _ := splitMethodKey(methodKey)
+
+		var declared map[string]string
+		for _, call := range FindJdbcCalls(body) {
+			if call.Getter {
+				if !jdbcGetters[call.Receiver] {
+					continue
+				}
+			} else {
+				if declared == nil {
+					declared = collectDeclaredTypes(body)
+					for name, typeName := range collectDeclaredTypes(methodNode.Params) {
+						declared[name] = typeName
+					}
+				}
+				receiverType, ok := declared[call.Receiver]
+				if !ok {
+					receiverType = javaparser.EraseType(l.Pool.GetFieldType(fullClassName, call.Receiver))
+				}
+				if !jdbcTypes[receiverType] {
+					continue
+				}
+			}
+
+			// SQL passed in from elsewhere still gets a node, without text: the
+			// injection report reads its argument
+			sql := strings.TrimSpace(l.Pool.jdbcSQL(fullClassName, call.SQLExpr, body[:call.Offset]))
+			if !strings.ContainsFunc(sql, func(r rune) bool { return r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' }) {
+				sql = ""
+			}
+
+			receiver := call.Receiver
+			if call.Getter {
+				receiver += "()"
+			}
+			// Line 1 of the body is the line of its opening brace
+			line := l.Pool.bodyLines[methodKey] + call.Line - 1
+			sqlKey := fmt.Sprintf("%s#%s.%s:%d", methodKey, receiver, call.MethodName, line)
+			sqlNode := &model.Node{
+				ID:           sqlKey,
+				Type:         model.NodeTypeSQL,
+				Package:      methodNode.Package,
+				File:         methodNode.File,
+				Line:         line,
+				Method:       receiver + "." + call.MethodName,
+				Comment:      sql,
+				Annotation:   "JDBC",
+				Tables:       tableAccesses(sql),
+				Placeholders: placeholders(sql, nil),
+				SQLArgument:  call.SQLExpr,
+				Children:     []*model.Node{},
+			}
+			l.Pool.SQLMap[sqlKey] = sqlNode
+			methodNode.AddChild(sqlNode)
+		}
+	}
+	return nil
+}
+
+// jdbcSQL resolves an SQL argument: a constant expression, a concatenation
+// (unknown operands become "?"), or a local variable followed through the
+// assignments, += and StringBuilder appends that precede the call
+func (pool *ComponentPool) jdbcSQL(fromClass, expr, before string) string {
+	expr = strings.TrimSpace(expr)
+	name := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(strings.TrimSuffix(expr, "()")), ".toString"))
+	if !isValidJavaIdentifier(name) || strings.ToUpper(name) == name {
+		return pool.concatSQL(fromClass, expr) // Constants resolve as expressions
+	}
+
+	var parts []string
+	tokens := javaparser.Tokenize(before)
+	for i := 0; i+2 < len(tokens); i++ {
+		if tokens[i].Text != name || tokens[i].Kind != javaparser.TokenIdent || (i > 0 && tokens[i-1].Is(".")) {
+			continue
+		}
+		// The lexer reads "+=" and "==" as two operators
+		assign, compound := tokens[i+1].Is("=") && !tokens[i+2].Is("="), tokens[i+1].Is("+") && tokens[i+2].Is("=")
+		switch {
+		case assign || compound:
+			from := i + 2
+			if compound {
+				from = i + 3
+			}
+			end := statementEnd(tokens, from)
+			value := tokenText(before, tokens, from, end)
+			if strings.HasPrefix(value, "new String") { // new StringBuilder(...), new StringBuffer(...)
+				value = ""
+				if open := indexToken(tokens, from, end, "("); open >= 0 {
+					if args := splitArguments(before, tokens, open); len(args) > 0 {
+						value = args[0]
+					}
+				}
+			}
+			if assign {
+				parts = nil
+			}
+			if value != "" {
+				parts = append(parts, pool.concatSQL(fromClass, value))
+			}
+			i = end
+		case tokens[i+1].Is("."):
+			// sql.append(a).append(b)
+			for j := i + 1; j+2 < len(tokens) && tokens[j].Is(".") && tokens[j+1].Text == "append" && tokens[j+2].Is("("); {
+				args := splitArguments(before, tokens, j+2)
+				if len(args) > 0 {
+					parts = append(parts, pool.concatSQL(fromClass, args[0]))
+				}
+				j = closingParen(tokens, j+2) + 1
+				i = j - 1
+			}
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// concatSQL evaluates a String concatenation operand by operand; an operand
+// that is not a compile-time constant becomes "?"
+func (pool *ComponentPool) concatSQL(fromClass, expr string) string {
+	tokens := javaparser.Tokenize(expr)
+	var sb strings.Builder
+	depth, start := 0, 0
+	flush := func(end int) {
+		operand := strings.TrimSpace(expr[start:end])
+		if operand == "" {
+			return
+		}
+		if value, ok := pool.EvaluateString(fromClass, operand); ok {
+			sb.WriteString(value)
+		} else {
+			sb.WriteString("?")
+		}
+	}
+	for _, tok := range tokens {
+		switch {
+		case tok.Kind == javaparser.TokenEOF:
+			flush(len(expr))
+		case tok.Is("(") || tok.Is("[") || tok.Is("{"):
+			depth++
+		case tok.Is(")") || tok.Is("]") || tok.Is("}"):
+			depth--
+		case tok.Is("+") && depth == 0:
+			flush(tok.Pos)
+			start = tok.End
+		}
+	}
+	return sb.String()
+}
+
+// statementEnd returns the index of the ';' ending the statement that
+// starts at tokens[from] (or the last token)
+func statementEnd(tokens []javaparser.Token, from int) int {
+	depth := 0
+	for i := from; i < len(tokens); i++ {
+		switch {
+		case tokens[i].Is("(") || tokens[i].Is("[") || tokens[i].Is("{"):
+			depth++
+		case tokens[i].Is(")") || tokens[i].Is("]") || tokens[i].Is("}"):
+			depth--
+		case tokens[i].Is(";") && depth <= 0, tokens[i].Kind == javaparser.TokenEOF:
+			return i
+		}
+	}
+	return len(tokens) - 1
+}
+
+// closingParen returns the index of the ')' closing the '(' at tokens[open]
+func closingParen(tokens []javaparser.Token, open int) int {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		if tokens[i].Is("(") {
+			depth++
+		} else if tokens[i].Is(")") {
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens) - 1
+}
+
+// indexToken returns the index of the first token with the given text in
+// tokens[from:to], or -1
+func indexToken(tokens []javaparser.Token, from, to int, text string) int {
+	for i := from; i < to && i < len(tokens); i++ {
+		if tokens[i].Is(text) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,129 @@
+package linker
+
+import (
+	"reflect"
+	"testing"
+
+	"spec-recon/internal/model"
+)
+
+// TestJdbcCalls verifies that JdbcTemplate, NamedParameterJdbcTemplate and
+// JDBC calls get a SQL node with the SQL resolved from constants and locals
+func TestJdbcCalls(t *testing.T) {
+	sources := []string{
+		`package com.company.order;
+
+public interface OrderSql {
+    String TABLE = "TB_ORDER";
+}
+`,
+		`package com.company.order;
+
+@Repository
+public class OrderDao extends JdbcDaoSupport implements OrderSql {
+    private static final String SELECT_ORDER = "SELECT ORDER_ID, AMOUNT FROM " + TABLE + " WHERE ORDER_ID = ?";
+
+    @Autowired
+    private JdbcTemplate jdbcTemplate;
+    @Autowired
+    private NamedParameterJdbcTemplate namedTemplate;
+    @Autowired
+    private DataSource dataSource;
+
+    public Order find(Long id) {
+        return jdbcTemplate.queryForObject(SELECT_ORDER, rowMapper, id);
+    }
+
+    public List<Order> search(String status, String sort) {
+        StringBuilder sql = new StringBuilder("SELECT * FROM TB_ORDER");
+        sql.append(" WHERE STATUS = :status");
+        if (sort != null) {
+            sql.append(" ORDER BY ").append(sort);
+        }
+        return namedTemplate.query(sql.toString(), Map.of("status", status), rowMapper);
+    }
+
+    public int archive(Long id) throws SQLException {
+        try (Connection conn = dataSource.getConnection()) {
+            String sql = "UPDATE " + TABLE + " SET STATUS = 'ARCHIVED'";
+            sql += " WHERE ORDER_ID = ?";
+            PreparedStatement ps = conn.prepareStatement(sql);
+            ps.setLong(1, id);
+            return ps.executeUpdate();
+        }
+    }
+
+    public void purge() {
+        getJdbcTemplate().update("DELETE FROM TB_ORDER WHERE STATUS = 'X'");
+    }
+
+    public List<Order> cached(Cache cache) {
+        return cache.query("not sql");
+    }
+
+    public int count(
+            String status)
+    {
+        return jdbcTemplate.queryForObject(
+                "SELECT COUNT(*) FROM TB_ORDER WHERE STATUS = ?", Integer.class, status);
+    }
+}
+`,
+	}
+
+	pool := NewTestPool(t, sources...)
+	NewLinker(pool).BuildCallGraph()
+
+	type statement struct {
+		Method, SQL string
+		Tables      []model.TableAccess
+	}
+	want := map[string][]statement{
+		"find(Long)": {{
+			Method: "jdbcTemplate.queryForObject",
+			SQL:    "SELECT ORDER_ID, AMOUNT FROM TB_ORDER WHERE ORDER_ID = ?",
+			Tables: []model.TableAccess{{Table: "TB_ORDER", Operations: "R", Columns: []string{"AMOUNT", "ORDER_ID"}}},
+		}},
+		"search(String,String)": {{
+			Method: "namedTemplate.query",
+			SQL:    "SELECT * FROM TB_ORDER WHERE STATUS = :status ORDER BY ?",
+			Tables: []model.TableAccess{{Table: "TB_ORDER", Operations: "R", Columns: []string{"STATUS"}}},
+		}},
+		"archive(Long)": {{
+			Method: "conn.prepareStatement",
+			SQL:    "UPDATE TB_ORDER SET STATUS = 'ARCHIVED' WHERE ORDER_ID = ?",
+			Tables: []model.TableAccess{{Table: "TB_ORDER", Operations: "U", Columns: []string{"ORDER_ID", "STATUS"}}},
+		}},
+		"purge()": {{
+			Method: "getJdbcTemplate().update",
+			SQL:    "DELETE FROM TB_ORDER WHERE STATUS = 'X'",
+			Tables: []model.TableAccess{{Table: "TB_ORDER", Operations: "D", Columns: []string{"STATUS"}}},
+		}},
+		"cached(Cache)": nil,
+		"count(String)": {{
+			Method: "jdbcTemplate.queryForObject",
+			SQL:    "SELECT COUNT(*) FROM TB_ORDER WHERE STATUS = ?",
+			Tables: []model.TableAccess{{Table: "TB_ORDER", Operations: "R", Columns: []string{"STATUS"}}},
+		}},
+	}
+	for method, w := range want {
+		methodNode := pool.GetMethod("com.company.order.OrderDao." + method)
+		var got []statement
+		for _, child := range methodNode.Children {
+			if child.Type == model.NodeTypeSQL {
+				got = append(got, statement{child.Method, child.Comment, child.Tables})
+			}
+		}
+		if !reflect.DeepEqual(got, w) {
+			t.Errorf("%s: got\n%+v\nwant\n%+v", method, got, w)
+		}
+	}
+
+	if sqlNode := pool.GetMethod("com.company.order.OrderDao.find(Long)").Children[0]; sqlNode.Line != 15 || pool.SQLMap[sqlNode.ID] != sqlNode {
+		t.Errorf("find: SQL node line %d, registered %v", sqlNode.Line, pool.SQLMap[sqlNode.ID] != nil)
+	}
+	// The signature and the opening brace wrap: the call is on line 48
+	if sqlNode := pool.GetMethod("com.company.order.OrderDao.count(String)").Children[0]; sqlNode.Line != 48 {
+		t.Errorf("count: SQL node line %d, want 48", sqlNode.Line)
+	}
+}
@@ -0,0 +1,594 @@
+package linker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"spec-recon/internal/javaparser"
+	"spec-recon/internal/model"
+)
+
+// springDataRepositories are the Spring Data interfaces whose first type
+// argument is the entity type of the repositories extending them
+var springDataRepositories = map[string]bool{
+	"Repository": true, "CrudRepository": true, "ListCrudRepository": true,
+	"PagingAndSortingRepository": true, "ListPagingAndSortingRepository": true,
+	"JpaRepository": true, "JpaSpecificationExecutor": true, "QuerydslPredicateExecutor": true,
+	"RevisionRepository": true,
+}
+
+// pagingParameters are repository method parameters that bind no query value
+var pagingParameters = map[string]bool{
+	"Pageable": true, "Sort": true, "Limit": true, "ScrollPosition": true, "Class": true,
+}
+
+// inheritedRepositoryMethods are the CrudRepository methods services call
+// without the repository declaring them. Params use T for the entity type
+// and ID for the id type
+var inheritedRepositoryMethods = []struct {
+	name       string
+	params     []string
+	returnType string
+	text       string
+	sql        func(e *jpaEntity) string
+}{
+	{"save", []string{"T"}, "T", "Save %s (insert or update)", saveSQL},
+	{"saveAndFlush", []string{"T"}, "T", "Save %s (insert or update)", saveSQL},
+	{"saveAll", []string{"Iterable"}, "List<T>", "Save all %s (insert or update)", saveSQL},
+	{"findById", []string{"ID"}, "Optional<T>", "Find %s by id", func(e *jpaEntity) string {
+		return "SELECT * FROM " + e.Table + " WHERE " + e.IDColumn + " = :id"
+	}},
+	{"findAll", nil, "List<T>", "Find all %s", func(e *jpaEntity) string {
+		return "SELECT * FROM " + e.Table
+	}},
+	{"findAllById", []string{"Iterable"}, "List<T>", "Find %s by ids", func(e *jpaEntity) string {
+		return "SELECT * FROM " + e.Table + " WHERE " + e.IDColumn + " IN (:ids)"
+	}},
+	{"existsById", []string{"ID"}, "boolean", "Check existence of %s by id", func(e *jpaEntity) string {
+		return "SELECT 1 FROM " + e.Table + " WHERE " + e.IDColumn + " = :id"
+	}},
+	{"count", nil, "long", "Count %s", func(e *jpaEntity) string {
+		return "SELECT COUNT(*) FROM " + e.Table
+	}},
+	{"deleteById", []string{"ID"}, "void", "Delete %s by id", deleteByIDSQL},
+	{"delete", []string{"T"}, "void", "Delete %s", deleteByIDSQL},
+	{"deleteAll", nil, "void", "Delete all %s", func(e *jpaEntity) string {
+		return "DELETE FROM " + e.Table
+	}},
+}
+
+// saveSQL is what save() runs: an insert for a new entity, else an update
+func saveSQL(e *jpaEntity) string {
+	columns := e.columnList()
+	values := make([]string, len(columns))
+	sets := make([]string, len(columns))
+	for i, column := range columns {
+		values[i] = ":" + column
+		sets[i] = column + " = :" + column
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s); UPDATE %s SET %s WHERE %s = :id",
+		e.Table, strings.Join(columns, ", "), strings.Join(values, ", "),
+		e.Table, strings.Join(sets, ", "), e.IDColumn)
+}
+
+// deleteByIDSQL is what deleteById() and delete() run
+func deleteByIDSQL(e *jpaEntity) string {
+	return "DELETE FROM " + e.Table + " WHERE " + e.IDColumn + " = :id"
+}
+
+// jpaEntity is the table mapping of an @Entity class
+type jpaEntity struct {
+	ClassName string            // Full class name
+	Name      string            // Entity name used by JPQL: @Entity(name), or the simple name
+	Table     string            // @Table name, or the physical name Spring Boot derives
+	IDColumn  string            // Column of the @Id property
+	Columns   map[string]string // Property -> column, for properties stored in the table
+	Relations map[string]string // Association property -> target class (element type for collections)
+}
+
+// column returns the column a property path is stored in: a property of the
+// entity, or the join column of an association path ("deptId" -> "dept_id")
+func (e *jpaEntity) column(property string) string {
+	if column, ok := e.Columns[property]; ok {
+		return column
+	}
+	for name, column := range e.Columns {
+		if _, association := e.Relations[name]; association && strings.HasPrefix(property, name) {
+			return column
+		}
+	}
+	return physicalName(property)
+}
+
+// hasProperty reports whether a property (or association path) is mapped
+func (e *jpaEntity) hasProperty(property string) bool {
+	if _, ok := e.Columns[property]; ok {
+		return true
+	}
+	for name := range e.Relations {
+		if strings.HasPrefix(property, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// columnList returns the mapped columns, sorted
+func (e *jpaEntity) columnList() []string {
+	columns := make([]string, 0, len(e.Columns))
+	for _, column := range e.Columns {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// physicalName is the name Spring Boot's CamelCaseToUnderscoresNamingStrategy
+// gives an entity or property without an explicit one: userAccount ->
+// user_account, while URLPath stays urlpath
+func physicalName(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i-1]) && unicode.IsUpper(r) && unicode.IsLower(runes[i+1]) {
+			sb.WriteByte('_')
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+// AddJpaRepositories reads Spring Data JPA repositories into query nodes:
+// derived query methods become a readable description, @Query methods keep
+// their JPQL or native SQL, and the CrudRepository methods other classes call
+// are added to the repository. Table usage is read through the @Entity,
+// @Table and @Column mappings. Call it after BuildHierarchy
+func (pool *ComponentPool) AddJpaRepositories() {
+	pool.loadEntities()
+
+	calledNames := make(map[string]bool)
+	for _, body := range pool.MethodBodyMap {
+		for _, call := range FindMethodCalls(body) {
+			calledNames[call.MethodName] = true
+		}
+	}
+
+	for className, decl := range pool.declMap {
+		if decl.Kind != javaparser.KindInterface {
+			continue
+		}
+		entityType, idType, base := pool.repositoryTypes(className, make(map[string]bool))
+		if base == "" {
+			continue
+		}
+		entity := pool.entities[entityType]
+		if entity == nil {
+			simpleName := extractSimpleTypeName(entityType)
+			entity = &jpaEntity{ClassName: entityType, Name: simpleName, Table: physicalName(simpleName), IDColumn: "id"}
+		}
+
+		classNode := pool.ClassMap[className]
+		classNode.Type = model.NodeTypeMapper
+		for _, child := range classNode.Children {
+			child.Type = model.NodeTypeMapper
+		}
+
+		for i := range decl.Methods {
+			method := &decl.Methods[i]
+			if text, sql, kind, ok := pool.repositoryQuery(className, entity, method); ok {
+				sqlNode := pool.addQueryNode(className, decl.File, method.Name, method.Line, kind, text, sql)
+				if resultType := pool.ResolveTypeName(className, statementResultType(method.ReturnType)); pool.ClassMap[resultType] != nil {
+					sqlNode.ResultType = resultType // Entity, DTO or projection
+				}
+			}
+		}
+
+		for _, inherited := range inheritedRepositoryMethods {
+			if !calledNames[inherited.name] || len(pool.OverloadMap[className+"."+inherited.name]) > 0 {
+				continue
+			}
+			pool.addInheritedMethod(classNode, decl, base, entity, idType, inherited.name, inherited.params, inherited.returnType)
+			pool.addQueryNode(className, decl.File, inherited.name, decl.Line, "Inherited",
+				fmt.Sprintf(inherited.text, entity.Name), inherited.sql(entity))
+		}
+	}
+}
+
+// repositoryQuery reads the query of a repository method: @Query JPQL or
+// native SQL, or a derived query name. Returns the text to show, the SQL
+// to read table usage from and the query kind
+func (pool *ComponentPool) repositoryQuery(className string, entity *jpaEntity, method *javaparser.Method) (string, string, string, bool) {
+	for _, ann := range method.Annotations {
+		if ann.Name != "Query" {
+			continue
+		}
+		query := strings.TrimSpace(pool.AnnotationString(className, ann, "value"))
+		if query == "" {
+			return "", "", "", false // Querydsl or another @Query
+		}
+		if ann.Attributes["nativeQuery"] == "true" {
+			return query, query, "@Query(native)", true
+		}
+		return query, pool.jpqlToSQL(query), "@Query", true
+	}
+
+	derived, ok := parseDerivedQuery(method.Name, entity.hasProperty)
+	if !ok {
+		return "", "", "", false
+	}
+	var params []string
+	for _, param := range method.Parameters {
+		if !pagingParameters[javaparser.EraseType(param.Type)] {
+			params = append(params, param.Name)
+		}
+	}
+	return derived.describe(entity.Name, params), derived.toSQL(entity, params), "Derived query", true
+}
+
+// addQueryNode adds the SQL node of a repository method
+func (pool *ComponentPool) addQueryNode(className, file, method string, line int, kind, text, sql string) *model.Node {
+	key := className + "." + method
+	sqlNode := &model.Node{
+		ID:         key,
+		Type:       model.NodeTypeSQL,
+		Package:    className,
+		File:       file,
+		Line:       line,
+		Method:     method,
+		Comment:    text,
+		Annotation: kind,
+		Tables:     tableAccesses(sql),
+		Children:   []*model.Node{},
+	}
+	pool.SQLMap[key] = sqlNode
+	return sqlNode
+}
+
+// addInheritedMethod adds a CrudRepository method to a repository that
+// inherits it, so calls to it resolve like calls to declared methods
+func (pool *ComponentPool) addInheritedMethod(classNode *model.Node, decl *javaparser.JavaClass, base string, entity *jpaEntity, idType, name string, params []string, returnType string) {
+	entityName := extractSimpleTypeName(entity.ClassName)
+	substitute := func(typeName string) string {
+		switch typeName {
+		case "T":
+			return entityName
+		case "ID":
+			return idType
+		}
+		return strings.ReplaceAll(typeName, "<T>", "<"+entityName+">")
+	}
+
+	var paramTypes, paramDecls []string
+	for _, param := range params {
+		paramTypes = append(paramTypes, javaparser.EraseType(substitute(param)))
+		paramDecls = append(paramDecls, substitute(param)+" "+javaparser.Decapitalize(javaparser.EraseType(substitute(param))))
+	}
+	methodKey := classNode.ID + "." + name + "(" + strings.Join(paramTypes, ",") + ")"
+	methodNode := &model.Node{
+		ID:           methodKey,
+		Type:         model.NodeTypeMapper,
+		Package:      decl.Package,
+		File:         decl.File,
+		Line:         decl.Line,
+		Method:       name,
+		Params:       strings.Join(paramDecls, ", "),
+		ParamTypes:   paramTypes,
+		ReturnDetail: substitute(returnType),
+		Comment:      "Inherited from " + base,
+		Parent:       classNode,
+		Children:     []*model.Node{},
+	}
+	pool.MethodMap[methodKey] = methodNode
+	pool.abstractMethods[methodKey] = true
+	pool.OverloadMap[classNode.ID+"."+name] = append(pool.OverloadMap[classNode.ID+"."+name], methodNode)
+	classNode.Children = append(classNode.Children, methodNode)
+}
+
+// repositoryTypes finds the entity and id types of a Spring Data repository,
+// through intermediate base repositories, with the Spring Data interface it
+// extends; base is "" for any other interface
+func (pool *ComponentPool) repositoryTypes(className string, visiting map[string]bool) (string, string, string) {
+	decl := pool.declMap[className]
+	if decl == nil || visiting[className] {
+		return "", "", ""
+	}
+	visiting[className] = true
+
+	for _, iface := range decl.Interfaces {
+		name := javaparser.EraseType(iface)
+		args := typeArguments(iface)
+		if springDataRepositories[name] {
+			if len(args) == 0 {
+				continue // Raw type
+			}
+			idType := "Object"
+			if len(args) > 1 {
+				idType = javaparser.EraseType(args[1])
+			}
+			return pool.ResolveTypeName(className, args[0]), idType, name
+		}
+		entityType, idType, base := pool.repositoryTypes(pool.ResolveTypeName(className, iface), visiting)
+		if base == "" {
+			continue
+		}
+		// A generic base repository (BaseRepository<T, ID>) takes its types from this clause
+		if pool.ClassMap[entityType] == nil && len(args) > 0 {
+			entityType = pool.ResolveTypeName(className, args[0])
+			if len(args) > 1 {
+				idType = javaparser.EraseType(args[1])
+			}
+		}
+		return entityType, idType, base
+	}
+	return "", "", ""
+}
+
+// typeArguments splits the type arguments of a generic type:
+// "JpaRepository<User, Map<String, Long>>" -> ["User", "Map<String, Long>"]
+func typeArguments(typeName string) []string {
+	open, close := strings.Index(typeName, "<"), strings.LastIndex(typeName, ">")
+	if open < 0 || close < open {
+		return nil
+	}
+	var args []string
+	depth, start := 0, open+1
+	for i := open + 1; i < close; i++ {
+		switch typeName[i] {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(typeName[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(args, strings.TrimSpace(typeName[start:close]))
+}
+
+// loadEntities reads the table mapping of every @Entity class, including the
+// properties it inherits from @MappedSuperclass classes
+func (pool *ComponentPool) loadEntities() {
+	pool.entities = make(map[string]*jpaEntity)
+	for className, decl := range pool.declMap {
+		var entityAnn *javaparser.Annotation
+		for i := range decl.Annotations {
+			if decl.Annotations[i].Name == "Entity" {
+				entityAnn = &decl.Annotations[i]
+			}
+		}
+		if entityAnn == nil {
+			continue
+		}
+
+		entity := &jpaEntity{
+			ClassName: className,
+			Name:      decl.Name,
+			IDColumn:  "id",
+			Columns:   make(map[string]string),
+			Relations: make(map[string]string),
+		}
+		if name := pool.AnnotationString(className, *entityAnn, "name"); name != "" {
+			entity.Name = name
+		}
+		entity.Table = physicalName(entity.Name)
+		for _, ann := range decl.Annotations {
+			if ann.Name == "Table" {
+				if table := pool.AnnotationString(className, ann, "name"); table != "" {
+					entity.Table = table
+				}
+			}
+		}
+
+		chain := []string{className}
+		for _, ancestor := range pool.superClassChain(className) {
+			if ancestorDecl := pool.declMap[ancestor]; ancestorDecl != nil && (hasAnnotation(ancestorDecl.Annotations, "MappedSuperclass") || hasAnnotation(ancestorDecl.Annotations, "Entity")) {
+				chain = append(chain, ancestor)
+			}
+		}
+		for _, owner := range chain {
+			for _, field := range pool.declMap[owner].Fields {
+				pool.mapEntityField(owner, entity, field)
+			}
+		}
+		pool.entities[className] = entity
+	}
+}
+
+// mapEntityField records the column (or association) of an entity field
+func (pool *ComponentPool) mapEntityField(owner string, entity *jpaEntity, field javaparser.Field) {
+	if field.HasModifier("static") || field.HasModifier("transient") || hasAnnotation(field.Annotations, "Transient") {
+		return
+	}
+	if _, mapped := entity.Columns[field.Name]; mapped {
+		return // Redeclared in a subclass
+	}
+
+	column := physicalName(field.Name)
+	for _, ann := range field.Annotations {
+		switch ann.Name {
+		case "OneToMany", "ManyToMany":
+			target := field.Type
+			if args := typeArguments(field.Type); len(args) > 0 {
+				target = args[len(args)-1]
+			}
+			entity.Relations[field.Name] = pool.ResolveTypeName(owner, target)
+			return // Stored in the other table or a join table
+		case "ManyToOne", "OneToOne":
+			entity.Relations[field.Name] = pool.ResolveTypeName(owner, field.Type)
+			if ann.Attributes["mappedBy"] != "" {
+				return // The other side holds the join column
+			}
+			column = physicalName(field.Name) + "_id"
+		}
+	}
+	for _, ann := range field.Annotations {
+		switch ann.Name {
+		case "Column", "JoinColumn":
+			if name := pool.AnnotationString(owner, ann, "name"); name != "" {
+				column = name
+			}
+		}
+	}
+
+	entity.Columns[field.Name] = column
+	if hasAnnotation(field.Annotations, "Id") || hasAnnotation(field.Annotations, "EmbeddedId") {
+		entity.IDColumn = column
+	}
+}
+
+// jpqlKeywords are JPQL words that cannot be an identification variable
+var jpqlKeywords = map[string]bool{
+	"WHERE": true, "JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "OUTER": true,
+	"FETCH": true, "ON": true, "SET": true, "ORDER": true, "GROUP": true, "HAVING": true,
+	"WITH": true, "UNION": true, "AS": true,
+}
+
+// jpqlToken is a word, quoted literal or other character of a JPQL query
+type jpqlToken struct {
+	text  string
+	ident bool
+}
+
+// jpqlToSQL rewrites JPQL into SQL over the mapped tables so its table usage
+// can be read: entity names become tables, alias.property paths columns, and
+// a join through an association path ("JOIN u.dept d") the target's table
+func (pool *ComponentPool) jpqlToSQL(jpql string) string {
+	byName := make(map[string]*jpaEntity)
+	for _, entity := range pool.entities {
+		byName[entity.Name] = entity
+	}
+
+	tokens := tokenizeJPQL(jpql)
+	// next returns the index of the next non-space token after i, or len(tokens)
+	next := func(i int) int {
+		for i++; i < len(tokens) && strings.TrimSpace(tokens[i].text) == ""; i++ {
+		}
+		return i
+	}
+	prev := func(i int) int {
+		for i--; i >= 0 && strings.TrimSpace(tokens[i].text) == ""; i-- {
+		}
+		return i
+	}
+	word := func(i int) string {
+		if i < 0 || i >= len(tokens) || !tokens[i].ident {
+			return ""
+		}
+		return strings.ToUpper(tokens[i].text)
+	}
+	bindAlias := func(i int, aliases map[string]*jpaEntity, entity *jpaEntity) {
+		j := next(i)
+		if word(j) == "AS" {
+			j = next(j)
+		}
+		if w := word(j); w != "" && !jpqlKeywords[w] {
+			aliases[tokens[j].text] = entity
+		}
+	}
+
+	// Identification variables: "User u", "JOIN u.dept d"
+	aliases := make(map[string]*jpaEntity)
+	joins := make(map[int]*jpaEntity) // Index of a joined association path -> target
+	for i := range tokens {
+		if !tokens[i].ident || (prev(i) >= 0 && tokens[prev(i)].text == ".") {
+			continue
+		}
+		if entity := byName[tokens[i].text]; entity != nil {
+			bindAlias(i, aliases, entity)
+		}
+	}
+	for i := range tokens {
+		if word(prev(i)) != "JOIN" && word(prev(i)) != "FETCH" {
+			continue
+		}
+		owner := aliases[tokens[i].text]
+		dot, prop := next(i), next(next(i))
+		if owner == nil || dot >= len(tokens) || tokens[dot].text != "." || word(prop) == "" {
+			continue
+		}
+		if target := pool.entities[owner.Relations[tokens[prop].text]]; target != nil {
+			joins[i] = target
+			bindAlias(prop, aliases, target)
+		}
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if !tok.ident {
+			sb.WriteString(tok.text)
+			continue
+		}
+		before := prev(i)
+		switch {
+		case joins[i] != nil:
+			sb.WriteString(joins[i].Table)
+			i = next(next(i)) // Skip ".property"
+		case word(i) == "NEW" && next(i) < len(tokens) && tokens[next(i)].ident:
+			// Constructor expression: keep the argument list only
+			for i = next(i); i+1 < len(tokens) && (tokens[i+1].ident || tokens[i+1].text == "."); i++ {
+			}
+		case before >= 0 && tokens[before].text == ".":
+			sb.WriteString(tok.text)
+		case aliases[tok.text] != nil && next(i) < len(tokens) && tokens[next(i)].text == "." && word(next(next(i))) != "":
+			prop := next(next(i))
+			sb.WriteString(tok.text + "." + aliases[tok.text].column(tokens[prop].text))
+			i = prop
+		case aliases[tok.text] != nil && (word(before) == "SELECT" || word(before) == "DISTINCT" ||
+			(before >= 0 && (tokens[before].text == "," || tokens[before].text == "("))):
+			sb.WriteString(tok.text + ".*") // SELECT u, COUNT(u)
+		case byName[tok.text] != nil:
+			sb.WriteString(byName[tok.text].Table)
+		default:
+			sb.WriteString(tok.text)
+		}
+	}
+	return sb.String()
+}
+
+// tokenizeJPQL splits JPQL into words, quoted literals, runs of spaces and
+// single characters, keeping the text intact
+func tokenizeJPQL(jpql string) []jpqlToken {
+	var tokens []jpqlToken
+	runes := []rune(jpql)
+	for i := 0; i < len(runes); {
+		start := i
+		r := runes[i]
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, jpqlToken{text: string(runes[start:i]), ident: true})
+			continue
+		case r == '\'':
+			for i++; i < len(runes); i++ {
+				if runes[i] == '\'' {
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						i++ // Escaped quote
+						continue
+					}
+					break
+				}
+			}
+			i++
+		case unicode.IsSpace(r):
+			for i < len(runes) && unicode.IsSpace(runes[i]) {
+				i++
+			}
+		case r == ':' || r == '?':
+			// Parameters stay whole: ":email", "?1"
+			for i++; i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_'); i++ {
+			}
+		default:
+			i++
+		}
+		tokens = append(tokens, jpqlToken{text: string(runes[start:min(i, len(runes))])})
+	}
+	return tokens
+}
@@ -0,0 +1,170 @@
+package linker
+
+import (
+	"reflect"
+	"testing"
+
+	"spec-recon/internal/model"
+)
+
+// TestJpaRepositories verifies query nodes for derived queries, @Query JPQL
+// and native SQL, and the inherited CrudRepository methods a service calls
+func TestJpaRepositories(t *testing.T) {
+	sources := []string{
+		`package com.company.account;
+
+@MappedSuperclass
+public abstract class BaseEntity {
+    @Column(name = "CREATED_AT")
+    private LocalDateTime createdAt;
+    private LocalDateTime updatedAt;
+}
+`,
+		`package com.company.account;
+
+@Entity
+@Table(name = "TB_USER_ACCOUNT")
+public class UserAccount extends BaseEntity {
+    public static final String PREFIX = "U";
+
+    @Id
+    @Column(name = "USER_ID")
+    private Long id;
+    private String email;
+    private String status;
+    private boolean loggedIn;
+    @ManyToOne
+    @JoinColumn(name = "DEPT_CD")
+    private Department department;
+    @OneToMany(mappedBy = "user")
+    private List<LoginHistory> histories;
+    @Transient
+    private String displayName;
+}
+`,
+		`package com.company.account;
+
+@Entity
+public class Department {
+    @Id
+    private String deptCode;
+    private String deptName;
+}
+`,
+		`package com.company.account;
+
+public interface UserAccountRepository extends JpaRepository<UserAccount, Long> {
+    List<UserAccount> findByEmailAndStatusOrderByCreatedAtDesc(String email, String status);
+
+    long countByLoggedIn(boolean loggedIn);
+
+    Optional<UserAccount> findFirstByDepartmentDeptCodeOrEmailIgnoreCase(String deptCode, String email);
+
+    @Query("SELECT u FROM UserAccount u JOIN u.department d WHERE d.deptName = :name AND u.status <> 'DELETED'")
+    List<UserAccount> searchByDeptName(@Param("name") String name);
+
+    @Modifying
+    @Query(value = "UPDATE TB_USER_ACCOUNT SET STATUS = 'LOCKED' WHERE USER_ID = :id", nativeQuery = true)
+    int lock(@Param("id") Long id);
+}
+`,
+		`package com.company.account;
+
+@Service
+public class UserAccountService {
+    @Autowired
+    private UserAccountRepository userAccountRepository;
+
+    public UserAccount register(UserAccount account) {
+        return userAccountRepository.save(account);
+    }
+
+    public UserAccount get(Long id) {
+        return userAccountRepository.findById(id).orElseThrow();
+    }
+}
+`,
+	}
+
+	pool := NewTestPool(t, sources...)
+	NewLinker(pool).BuildCallGraph()
+
+	repository := "com.company.account.UserAccountRepository"
+	type query struct {
+		Kind, Text, ResultType string
+		Tables                 []model.TableAccess
+	}
+	want := map[string]query{
+		"findByEmailAndStatusOrderByCreatedAtDesc": {
+			Kind:       "Derived query",
+			Text:       "Find UserAccount where email = :email and status = :status order by createdAt desc",
+			ResultType: "com.company.account.UserAccount",
+			Tables:     []model.TableAccess{{Table: "TB_USER_ACCOUNT", Operations: "R", Columns: []string{"CREATED_AT", "EMAIL", "STATUS"}}},
+		},
+		"countByLoggedIn": {
+			Kind:   "Derived query",
+			Text:   "Count UserAccount where loggedIn = :loggedIn",
+			Tables: []model.TableAccess{{Table: "TB_USER_ACCOUNT", Operations: "R", Columns: []string{"LOGGED_IN"}}},
+		},
+		"findFirstByDepartmentDeptCodeOrEmailIgnoreCase": {
+			Kind:       "Derived query",
+			Text:       "Find first UserAccount where departmentDeptCode = :deptCode or email = :email (ignoring case)",
+			ResultType: "com.company.account.UserAccount",
+			Tables:     []model.TableAccess{{Table: "TB_USER_ACCOUNT", Operations: "R", Columns: []string{"DEPT_CD", "EMAIL"}}},
+		},
+		"searchByDeptName": {
+			Kind:       "@Query",
+			Text:       "SELECT u FROM UserAccount u JOIN u.department d WHERE d.deptName = :name AND u.status <> 'DELETED'",
+			ResultType: "com.company.account.UserAccount",
+			Tables: []model.TableAccess{
+				{Table: "DEPARTMENT", Operations: "R", Columns: []string{"DEPT_NAME"}},
+				{Table: "TB_USER_ACCOUNT", Operations: "R", Columns: []string{"STATUS"}},
+			},
+		},
+		"lock": {
+			Kind:   "@Query(native)",
+			Text:   "UPDATE TB_USER_ACCOUNT SET STATUS = 'LOCKED' WHERE USER_ID = :id",
+			Tables: []model.TableAccess{{Table: "TB_USER_ACCOUNT", Operations: "U", Columns: []string{"STATUS", "USER_ID"}}},
+		},
+		"save": {
+			Kind: "Inherited",
+			Text: "Save UserAccount (insert or update)",
+			Tables: []model.TableAccess{{Table: "TB_USER_ACCOUNT", Operations: "CU",
+				Columns: []string{"CREATED_AT", "DEPT_CD", "EMAIL", "LOGGED_IN", "STATUS", "UPDATED_AT", "USER_ID"}}},
+		},
+		"findById": {
+			Kind:   "Inherited",
+			Text:   "Find UserAccount by id",
+			Tables: []model.TableAccess{{Table: "TB_USER_ACCOUNT", Operations: "R", Columns: []string{"USER_ID"}}},
+		},
+	}
+	for id, w := range want {
+		sqlNode := pool.GetSQL(repository, id)
+		if sqlNode == nil {
+			t.Errorf("%s: no query node", id)
+			continue
+		}
+		got := query{Kind: sqlNode.Annotation, Text: sqlNode.Comment, ResultType: sqlNode.ResultType, Tables: sqlNode.Tables}
+		if !reflect.DeepEqual(got, w) {
+			t.Errorf("%s: got\n%+v\nwant\n%+v", id, got, w)
+		}
+	}
+	if sqlNode := pool.GetSQL(repository, "findAll"); sqlNode != nil {
+		t.Errorf("findAll is never called, but got a query node: %+v", sqlNode)
+	}
+
+	// The service reaches the inherited methods and, through them, their queries
+	for method, id := range map[string]string{"register(UserAccount)": "save", "get(Long)": "findById"} {
+		serviceMethod := pool.GetMethod("com.company.account.UserAccountService." + method)
+		if len(serviceMethod.Children) != 1 || serviceMethod.Children[0].Method != id {
+			t.Fatalf("%s: expected a call to %s, got %+v", method, id, serviceMethod.Children)
+		}
+		if !containsNode(serviceMethod.Children[0].Children, pool.GetSQL(repository, id)) {
+			t.Errorf("%s: query node not linked to the repository method", id)
+		}
+	}
+
+	if physical := physicalName("URLPath"); physical != "urlpath" {
+		t.Errorf("physicalName(URLPath) = %q", physical)
+	}
+}
@@ -2,70 +2,16 @@ package linker
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"spec-recon/internal/javaparser"
+	"spec-recon/internal/logger"
 	"spec-recon/internal/model"
 	"spec-recon/internal/xmlparser"
 )
 
-// IgnoredTokens is a blocklist of Java keywords and common constructs that should not be traced as method calls
-var IgnoredTokens = map[string]bool{
-	// Java keywords
-	"if":           true,
-	"else":         true,
-	"for":          true,
-	"while":        true,
-	"switch":       true,
-	"case":         true,
-	"try":          true,
-	"catch":        true,
-	"finally":      true,
-	"synchronized": true,
-	"return":       true,
-	"throw":        true,
-	"throws":       true,
-	"assert":       true,
-	"break":        true,
-	"continue":     true,
-	"do":           true,
-
-	// Constructors and object creation
-	"new":   true,
-	"this":  true,
-	"super": true,
-
-	// Common library methods (logging, I/O)
-	"println": true,
-	"print":   true,
-	"printf":  true,
-	"info":    true,
-	"debug":   true,
-	"warn":    true,
-	"error":   true,
-	"trace":   true,
-	"log":     true,
-	"out":     true,
-	"err":     true,
-
-	// Common Java classes that are usually noise
-	"System":    true,
-	"String":    true,
-	"Integer":   true,
-	"Long":      true,
-	"Double":    true,
-	"Boolean":   true,
-	"Object":    true,
-	"Class":     true,
-	"Exception": true,
-
-	// Common Spring/View classes (usually not business logic)
-	"ModelAndView": true,
-	"Model":        true,
-	"View":         true,
-	"RedirectView": true,
-}
-
 // Linker orchestrates the creation of the call graph
 type Linker struct {
 	Pool *ComponentPool
@@ -87,8 +33,7 @@ func (l *Linker) BuildCallGraph() []*model.Node {
 // LoadJavaClasses loads parsed Java classes into the pool
 func (l *Linker) LoadJavaClasses(classes []*javaparser.JavaClass, sourceContents map[string]string) error {
 	for _, cls := range classes {
-		fullClassName := cls.Package + "." + cls.Name
-		content := sourceContents[fullClassName]
+		content := sourceContents[cls.QualifiedName()]
 		if err := l.Pool.AddJavaClass(cls, content); err != nil {
 			return err
 		}
@@ -97,8 +42,16 @@ func (l *Linker) LoadJavaClasses(classes []*javaparser.JavaClass, sourceContents
 }
 
 // LoadMapperXMLs loads parsed XML mappers into the pool
+// <include> elements are expanded across the given mappers first
 func (l *Linker) LoadMapperXMLs(mappers []*xmlparser.MapperXML) error {
+	fragments := xmlparser.NewFragmentRegistry()
+	for _, mapper := range mappers {
+		fragments.Register(mapper)
+	}
 	for _, mapper := range mappers {
+		for _, diagnostic := range fragments.Expand(mapper) {
+			logger.Warn("Unresolved SQL include: %s", diagnostic)
+		}
 		if err := l.Pool.AddMapperXML(mapper); err != nil {
 			return err
 		}
@@ -108,16 +61,46 @@ func (l *Linker) LoadMapperXMLs(mappers []*xmlparser.MapperXML) error {
 
 // Link performs the linking process to build the call graph
 func (l *Linker) Link() error {
+	// 0. Resolve extends/implements clauses (needs every class loaded),
+	// then apply XML bean definitions and handler mappings on top of them,
+	// read annotation statements (needs constants and mapper files loaded)
+	// and JPA repository queries (needs the hierarchy for entities and base
+	// repositories), resolve statement resultMaps (needs every mapper loaded)
+	// the exceptions methods throw and handle (needs every class loaded), and
+	// evaluate the annotations of fields and parameters (needs constants loaded)
+	l.Pool.BuildHierarchy()
+	l.Pool.ApplySpringContexts()
+	l.Pool.AddAnnotatedStatements()
+	l.Pool.AddJpaRepositories()
+	l.Pool.ResolveResultMaps()
+	l.Pool.ResolveExceptions()
+	l.Pool.ResolveMemberAnnotations()
+
 	// 1. Link Java Methods (heuristic call tracing)
 	if err := l.linkJavaMethods(); err != nil {
 		return err
 	}
 
-	// 2. Link Mappers to XML
+	// 2. Link interface/abstract methods to their implementations
+	if err := l.linkImplementations(); err != nil {
+		return err
+	}
+
+	// 3. Link Mappers to XML
 	if err := l.linkMappersToXML(); err != nil {
 		return err
 	}
 
+	// 4. Link DAO calls that execute statements by ID (iBatis SqlMapClient)
+	if err := l.linkStatementCalls(); err != nil {
+		return err
+	}
+
+	// 5. Link methods that run SQL text through JdbcTemplate or plain JDBC
+	if err := l.linkJdbcCalls(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -145,63 +128,28 @@ func (l *Linker) linkJavaMethods() error {
 		// Parse calls in the body
 		calls := FindMethodCalls(body)
 
-		// Reconstruct FullClassName from methodKey (package.Class.method)
-		// Assuming methodKey is created as FullClassName + "." + MethodName
-		lastDot := strings.LastIndex(methodKey, ".")
-		if lastDot == -1 {
+		// Reconstruct FullClassName from methodKey (package.Class.method(Types))
+		fullClassName, _ := splitMethodKey(methodKey)
+		if fullClassName == "" {
 			continue
 		}
-		fullClassName := methodKey[:lastDot]
-
-		for _, call := range calls {
-			// JAVA IDENTIFIER VALIDATION: Reject invalid identifiers
-			// This catches "if (...)", "switch (...)", "return ...", etc.
-			if !isValidJavaIdentifier(call.Variable) || !isValidJavaIdentifier(call.MethodName) {
-				continue
-			}
 
-			// INVALID TOKEN CHECK: Prefix-based keyword detection
-			// This catches "if", "throw", "new", etc. even in context
-			if IsInvalidToken(call.Variable) || IsInvalidToken(call.MethodName) {
-				continue
-			}
-
-			// NOISE FILTER: Skip Java keywords and common constructs
-			if IgnoredTokens[call.Variable] || IgnoredTokens[call.MethodName] {
-				continue
-			}
-
-			// STRICT METHOD CALL VALIDATION: Block exceptions, keywords, invalid constructors
-			if !IsValidMethodCall(call.Variable) || !IsValidMethodCall(call.MethodName) {
-				continue
-			}
-
-			// CONSTRUCTOR FILTER: Skip "new ClassName()" patterns
-			// If this is a static call and the variable starts with uppercase,
-			// it might be a constructor. We skip these unless they're known business components.
-			if call.IsStatic && isConstructorCall(call.Variable) {
-				continue
-			}
-
-			// RETURN TYPE FILTER: Skip constructor calls matching the method's return type
-			// E.g., if method returns ModelAndView, skip "ModelAndView" as a child call
-			if call.IsStatic && methodNode.ReturnDetail != "" {
-				// Extract simple type name from return type (remove generics, packages)
-				returnType := methodNode.ReturnDetail
-				if idx := strings.Index(returnType, "<"); idx > 0 {
-					returnType = returnType[:idx]
-				}
-				if idx := strings.LastIndex(returnType, "."); idx >= 0 {
-					returnType = returnType[idx+1:]
-				}
-
-				// If the call variable matches the return type, it's likely a constructor
-				if call.Variable == returnType {
-					fmt.Printf("[LINKER DROP] Ignored constructor matching return type: %s\n", call.Variable)
-					continue
-				}
+		// Local variables and parameters shadow fields when inferring argument types
+		declared := collectDeclaredTypes(body)
+		for name, typeName := range collectDeclaredTypes(methodNode.Params) {
+			declared[name] = typeName
+		}
+		lookup := func(name string) string {
+			if typeName, ok := declared[name]; ok {
+				return typeName
 			}
+			return javaparser.EraseType(l.Pool.GetFieldType(fullClassName, name))
+		}
 
+		for _, call := range calls {
+			// Calls come from the tokenizer: receivers and names are real
+			// identifiers and constructors (new X(...)) are not calls, so only
+			// resolving the receiver decides what gets linked
 			var targetNodes []*model.Node
 
 			if call.IsStatic {
@@ -213,13 +161,13 @@ func (l *Linker) linkJavaMethods() error {
 						fmt.Printf("[LINKER SKIP] Data Class ignored: %s\n", targetClass)
 						continue
 					}
-					targetNodes = l.Pool.FindMethodByName(targetClass, call.MethodName)
+					targetNodes = l.Pool.ResolveMethod(targetClass, call.MethodName, argumentTypes(call, lookup))
 				}
 
 			} else {
 				// Instance call: Variable is variable name
-				// 1. Resolve variable type
-				variableType := l.Pool.ResolveFieldType(fullClassName, call.Variable)
+				// 1. Resolve variable type (injected beans honour qualifiers)
+				variableType := l.Pool.ResolveBeanType(fullClassName, call.Variable)
 
 				if variableType != "" {
 					// DATA CLASS FILTER: Skip data structures (DTO, VO, Model, Entity, etc.)
@@ -228,7 +176,7 @@ func (l *Linker) linkJavaMethods() error {
 						continue
 					}
 					// 2. Find method in target type
-					targetNodes = l.Pool.FindMethodByName(variableType, call.MethodName)
+					targetNodes = l.Pool.ResolveMethod(variableType, call.MethodName, argumentTypes(call, lookup))
 				}
 			}
 
@@ -241,17 +189,57 @@ func (l *Linker) linkJavaMethods() error {
 	return nil
 }
 
+// linkImplementations continues call chains that end on an interface or abstract
+// method into the overriding method of every concrete implementation
+// Calls are resolved against the declared (field) type, so without this step a
+// chain through an injected interface stops at a method with no body
+// A method overriding several abstract methods is noted as the implementation
+// of the first of their types in name order, so the note is the same every run
+func (l *Linker) linkImplementations() error {
+	for _, methodKey := range slices.Sorted(maps.Keys(l.Pool.MethodMap)) {
+		methodNode := l.Pool.MethodMap[methodKey]
+		if !l.Pool.isAbstractMethod(methodKey) {
+			continue
+		}
+		fullClassName, _ := splitMethodKey(methodKey)
+		if IsDataClass(fullClassName) {
+			continue
+		}
+
+		var targets []*model.Node
+		for _, implClass := range l.Pool.FindImplementations(fullClassName) {
+			target := l.Pool.FindOverride(implClass, methodNode)
+			if target != nil && !containsNode(targets, target) {
+				targets = append(targets, target)
+			}
+		}
+
+		for _, target := range targets {
+			if target.ImplementationOf == "" {
+				target.ImplementationOf = fullClassName
+				target.ImplementationCount = len(targets)
+			}
+			methodNode.AddChild(target)
+		}
+	}
+	return nil
+}
+
+func containsNode(nodes []*model.Node, node *model.Node) bool {
+	for _, n := range nodes {
+		if n == node {
+			return true
+		}
+	}
+	return false
+}
+
 // linkMappersToXML links Mapper interface methods to XML SQL nodes
 func (l *Linker) linkMappersToXML() error {
 	for methodKey, methodNode := range l.Pool.MethodMap {
 		// Check if this method belongs to a Mapper interface
 
-		lastDot := strings.LastIndex(methodKey, ".")
-		if lastDot == -1 {
-			continue
-		}
-		fullClassName := methodKey[:lastDot]
-
+		fullClassName, _ := splitMethodKey(methodKey)
 		classNode := l.Pool.ClassMap[fullClassName]
 
 		if classNode != nil && classNode.Type == model.NodeTypeMapper {
@@ -261,6 +249,10 @@ func (l *Linker) linkMappersToXML() error {
 			if sqlNode != nil {
 				methodNode.AddChild(sqlNode)
 			}
+			// @SelectProvider and friends: the provider method builds the SQL
+			for _, providerNode := range l.Pool.OverloadMap[l.Pool.sqlProviders[fullClassName+"."+methodNode.Method]] {
+				methodNode.AddChild(providerNode)
+			}
 		}
 	}
 	return nil
@@ -283,40 +275,6 @@ func (l *Linker) findClassBySimpleName(simpleName string) string {
 	return ""
 }
 
-// isConstructorCall checks if a variable name looks like a constructor call
-// (e.g., ModelAndView, ResponseEntity, etc.)
-func isConstructorCall(varName string) bool {
-	// If it's in the ignored tokens list, it's likely a constructor we want to skip
-	if IgnoredTokens[varName] {
-		return true
-	}
-
-	// Additional heuristic: Common Spring/Java classes that are typically constructors
-	// These are classes we create but don't want to trace as business logic
-	constructorPatterns := []string{
-		"ResponseEntity",
-		"HttpEntity",
-		"ArrayList",
-		"HashMap",
-		"HashSet",
-		"LinkedList",
-		"TreeMap",
-		"TreeSet",
-		"Date",
-		"SimpleDateFormat",
-		"StringBuilder",
-		"StringBuffer",
-	}
-
-	for _, pattern := range constructorPatterns {
-		if varName == pattern {
-			return true
-		}
-	}
-
-	return false
-}
-
 // isValidJavaIdentifier checks if a string is a valid Java identifier
 // Rejects strings with spaces, parentheses, operators, etc.
 func isValidJavaIdentifier(name string) bool {
@@ -343,135 +301,6 @@ func isValidJavaIdentifier(name string) bool {
 	return true
 }
 
-// IsInvalidToken performs prefix-based validation to catch keywords in context
-// This catches cases like "if (...)", "throw new", "switch (...)" that might slip through regex
-func IsInvalidToken(name string) bool {
-	if name == "" {
-		return true // Invalid: empty
-	}
-
-	// Normalize to lowercase for checking
-	lower := strings.ToLower(name)
-
-	// STRICT BLOCKLIST: Java keywords and control flow statements
-	// These should NEVER appear as method calls or variables in the call graph
-	strictBlocklist := map[string]bool{
-		"if":           true,
-		"else":         true,
-		"for":          true,
-		"while":        true,
-		"do":           true,
-		"switch":       true,
-		"case":         true,
-		"default":      true,
-		"return":       true,
-		"throw":        true,
-		"throws":       true,
-		"new":          true,
-		"try":          true,
-		"catch":        true,
-		"finally":      true,
-		"synchronized": true,
-		"assert":       true,
-		"break":        true,
-		"continue":     true,
-		"goto":         true,
-		"instanceof":   true,
-		"this":         true,
-		"super":        true,
-		"null":         true,
-		"true":         true,
-		"false":        true,
-	}
-
-	// Exact match check
-	if strictBlocklist[lower] {
-		fmt.Printf("[LINKER DROP] Ignored keyword: %s\n", name)
-		return true
-	}
-
-	// PREFIX CHECK: Block if token STARTS with these keywords
-	// This catches "if", "ifSomething", etc.
-	keywordPrefixes := []string{
-		"if", "for", "while", "switch", "catch",
-		"synchronized", "return", "throw", "assert",
-		"new", "instanceof",
-	}
-
-	for _, prefix := range keywordPrefixes {
-		if strings.HasPrefix(lower, prefix) {
-			// Additional check: if it's exactly the keyword or followed by non-letter
-			if lower == prefix || (len(lower) > len(prefix) && !isLetter(rune(lower[len(prefix)]))) {
-				fmt.Printf("[LINKER DROP] Ignored keyword/prefix: %s (prefix: %s)\n", name, prefix)
-				return true // Invalid
-			}
-		}
-	}
-
-	// Check for Exception/Error constructors
-	if strings.HasSuffix(name, "Exception") || strings.HasSuffix(name, "Error") {
-		fmt.Printf("[LINKER DROP] Ignored Exception/Error: %s\n", name)
-		return true // Invalid
-	}
-
-	return false // Valid
-}
-
-// Helper to check if a rune is a letter
-func isLetter(r rune) bool {
-	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
-}
-
-// IsValidMethodCall performs strict validation on method calls to filter out noise
-// Blocks: Java keywords, Exception constructors, and invalid class instantiations
-func IsValidMethodCall(name string) bool {
-	if name == "" {
-		return false
-	}
-
-	// Block Java keywords that might slip through
-	javaKeywords := map[string]bool{
-		"if":           true,
-		"else":         true,
-		"for":          true,
-		"while":        true,
-		"switch":       true,
-		"case":         true,
-		"default":      true,
-		"try":          true,
-		"catch":        true,
-		"finally":      true,
-		"synchronized": true,
-		"return":       true,
-		"throw":        true,
-		"throws":       true,
-		"new":          true,
-		"super":        true,
-		"this":         true,
-		"break":        true,
-		"continue":     true,
-		"do":           true,
-		"instanceof":   true,
-		"assert":       true,
-	}
-
-	if javaKeywords[name] {
-		return false
-	}
-
-	// Block Exception constructors (anything ending with "Exception")
-	if strings.HasSuffix(name, "Exception") {
-		return false
-	}
-
-	// Block Error constructors (anything ending with "Error")
-	if strings.HasSuffix(name, "Error") {
-		return false
-	}
-
-	return true
-}
-
 // IsDataClass checks if a package name indicates a data structure class
 // Data classes (DTO, VO, Model, Entity, etc.) should be excluded from call graphs
 // as they represent data structures, not business logic flows
@@ -119,7 +119,7 @@ func verifyLegacyFlow(t *testing.T, l *Linker) {
 	if !foundServiceCall {
 		t.Error("❌ Link MISSING: UserController.login -> UserService.authenticateUser")
 		// Debug
-		body := pool.MethodBodyMap["com.company.legacy.UserController.login"]
+		body := pool.MethodBodyMap[userControllerLogin.ID]
 		t.Logf("Method Body:\n%s", body)
 		fieldType := pool.ResolveFieldType("com.company.legacy.UserController", "userService")
 		t.Logf("Resolved userService type: %s", fieldType)
@@ -191,7 +191,7 @@ func verifyModernFlow(t *testing.T, l *Linker) {
 	if !foundService {
 		t.Error("❌ Link MISSING: ProductApiController.getProductList -> ProductService.getProductList")
 		// Debug
-		body := pool.MethodBodyMap["com.company.modern.ProductApiController.getProductList"]
+		body := pool.MethodBodyMap[methodNode.ID]
 		t.Logf("Controller Body:\n%s", body)
 	}
 }
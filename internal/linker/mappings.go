@@ -0,0 +1,180 @@
+package linker
+
+import (
+	"fmt"
+	"strings"
+
+	"spec-recon/internal/javaparser"
+	"spec-recon/internal/model"
+)
+
+// mediaTypeConstants are the Spring MediaType String constants used in
+// consumes and produces, by field name
+var mediaTypeConstants = map[string]string{
+	"ALL_VALUE":                         "*/*",
+	"APPLICATION_JSON_VALUE":            "application/json",
+	"APPLICATION_JSON_UTF8_VALUE":       "application/json;charset=UTF-8",
+	"APPLICATION_PROBLEM_JSON_VALUE":    "application/problem+json",
+	"APPLICATION_XML_VALUE":             "application/xml",
+	"APPLICATION_FORM_URLENCODED_VALUE": "application/x-www-form-urlencoded",
+	"APPLICATION_OCTET_STREAM_VALUE":    "application/octet-stream",
+	"APPLICATION_PDF_VALUE":             "application/pdf",
+	"MULTIPART_FORM_DATA_VALUE":         "multipart/form-data",
+	"TEXT_PLAIN_VALUE":                  "text/plain",
+	"TEXT_HTML_VALUE":                   "text/html",
+	"TEXT_XML_VALUE":                    "text/xml",
+	"TEXT_EVENT_STREAM_VALUE":           "text/event-stream",
+	"IMAGE_PNG_VALUE":                   "image/png",
+	"IMAGE_JPEG_VALUE":                  "image/jpeg",
+	"IMAGE_GIF_VALUE":                   "image/gif",
+}
+
+// resolveMappings sets the request URLs and conditions of every method from
+// its class's and its own mapping annotations, evaluating constants and
+// concatenations (UrlConstants.USER_BASE + "/list") the parser keeps as source text
+// A class without its own @RequestMapping inherits the nearest superclass's
+// (typically an abstract base controller), the way Spring merges the
+// annotation from the hierarchy. Array values map a method to several paths
+func (pool *ComponentPool) resolveMappings() {
+	for fullClassName, decl := range pool.declMap {
+		classPaths := []string{""}
+		var classConditions model.RequestMapping
+		for _, className := range append([]string{fullClassName}, pool.superClassChain(fullClassName)...) {
+			classDecl := pool.declMap[className]
+			if classDecl == nil {
+				continue
+			}
+			if ann, ok := findAnnotation(classDecl.Annotations, "RequestMapping"); ok {
+				classPaths = pool.mappingPaths(className, ann)
+				classConditions = pool.mappingConditions(className, ann)
+				break
+			}
+		}
+
+		for i := range decl.Methods {
+			method := &decl.Methods[i]
+			node := pool.MethodMap[fullClassName+"."+method.Signature()]
+			if node == nil {
+				continue
+			}
+			// The first mapping annotation declaring a path, else the first one
+			methodPaths := []string{""}
+			var handler *javaparser.Annotation
+			for j := range method.Annotations {
+				ann := &method.Annotations[j]
+				if !strings.HasSuffix(ann.Name, "Mapping") {
+					continue
+				}
+				if handler == nil {
+					handler = ann
+				}
+				if paths := pool.mappingPaths(fullClassName, *ann); len(paths) > 1 || paths[0] != "" {
+					handler, methodPaths = ann, paths
+					break
+				}
+			}
+			node.Mapping = nil
+			if handler != nil {
+				conditions := combineConditions(classConditions, pool.mappingConditions(fullClassName, *handler))
+				node.Mapping = &conditions
+			}
+
+			var urls []string
+			seen := make(map[string]bool)
+			for _, classPath := range classPaths {
+				for _, methodPath := range methodPaths {
+					url := javaparser.CombineURLPaths(classPath, methodPath)
+					if !seen[url] {
+						seen[url] = true
+						urls = append(urls, url)
+					}
+				}
+			}
+			node.URL, node.URLs = urls[0], nil
+			if len(urls) > 1 {
+				node.URLs = urls
+			}
+		}
+	}
+}
+
+// mappingPaths returns the paths declared by a mapping annotation (value or
+// path element), or [""] when it declares none
+func (pool *ComponentPool) mappingPaths(fromClass string, ann javaparser.Annotation) []string {
+	if paths := pool.annotationValues(fromClass, ann, "value", "path"); len(paths) > 0 {
+		return paths
+	}
+	return []string{""}
+}
+
+// mappingConditions reads the method, params, headers, consumes and produces
+// conditions of a mapping annotation
+func (pool *ComponentPool) mappingConditions(fromClass string, ann javaparser.Annotation) model.RequestMapping {
+	return model.RequestMapping{
+		Methods:  ann.HTTPMethods(),
+		Params:   pool.annotationValues(fromClass, ann, "params"),
+		Headers:  pool.annotationValues(fromClass, ann, "headers"),
+		Consumes: pool.annotationValues(fromClass, ann, "consumes"),
+		Produces: pool.annotationValues(fromClass, ann, "produces"),
+	}
+}
+
+// combineConditions merges class-level and method-level conditions as Spring
+// does: methods, params and headers add up, while the method's consumes and
+// produces replace the class's
+func combineConditions(class, method model.RequestMapping) model.RequestMapping {
+	combined := model.RequestMapping{
+		Methods:  appendMissing(class.Methods, method.Methods),
+		Params:   appendMissing(class.Params, method.Params),
+		Headers:  appendMissing(class.Headers, method.Headers),
+		Consumes: class.Consumes,
+		Produces: class.Produces,
+	}
+	if len(method.Consumes) > 0 {
+		combined.Consumes = method.Consumes
+	}
+	if len(method.Produces) > 0 {
+		combined.Produces = method.Produces
+	}
+	return combined
+}
+
+// appendMissing returns the values of a followed by those of b not already in a
+func appendMissing(a, b []string) []string {
+	result := append([]string(nil), a...)
+	for _, value := range b {
+		if !containsString(result, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// annotationValues returns the String elements of the first of the given
+// annotation elements present (a single value or an array), evaluated as
+// written in a class; MediaType constants read as their media type, and an
+// element that is not a compile-time String is kept as source text
+func (pool *ComponentPool) annotationValues(fromClass string, ann javaparser.Annotation, keys ...string) []string {
+	for _, key := range keys {
+		expr, ok := ann.Expressions[key]
+		if !ok {
+			continue
+		}
+		var values []string
+		for _, element := range javaparser.ArrayElements(expr) {
+			value, ok := pool.EvaluateString(fromClass, element)
+			if !ok {
+				value, ok = mediaTypeConstants[strings.TrimPrefix(element, "MediaType.")]
+			}
+			if !ok {
+				fmt.Printf("[LINKER SKIP] Unresolved %s '%s' of @%s in %s\n", key, element, ann.Name, fromClass)
+				value = element
+			}
+			values = append(values, value)
+		}
+		if len(values) > 0 {
+			return values
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package linker
+
+import (
+	"strconv"
+	"strings"
+
+	"spec-recon/internal/javaparser"
+	"spec-recon/internal/model"
+)
+
+// ResolveMemberAnnotations records the annotations of every class, field and
+// method parameter with their element values evaluated (see annotationModel),
+// so the analyzer can read constraints such as @Size(max = UserConstants.NAME_MAX),
+// and the Jackson naming strategy of every class
+func (pool *ComponentPool) ResolveMemberAnnotations() {
+	for fullClassName, decl := range pool.declMap {
+		classNode := pool.ClassMap[fullClassName]
+		classNode.TypeAnnotations = pool.annotationModels(fullClassName, decl.Annotations)
+		classNode.PropertyNaming = pool.propertyNaming(fullClassName)
+		for _, field := range decl.Fields {
+			if info := classNode.GetField(field.Name); info != nil {
+				info.Annotations = pool.annotationModels(fullClassName, field.Annotations)
+			}
+		}
+
+		for i := range decl.Methods {
+			method := &decl.Methods[i]
+			node := pool.MethodMap[fullClassName+"."+method.Signature()]
+			if node == nil {
+				continue
+			}
+			node.ParamAnnotations = nil
+			for _, param := range method.Parameters {
+				if len(param.Annotations) == 0 {
+					continue
+				}
+				if node.ParamAnnotations == nil {
+					node.ParamAnnotations = make(map[string][]model.Annotation)
+				}
+				node.ParamAnnotations[param.Name] = pool.annotationModels(fullClassName, param.Annotations)
+			}
+		}
+	}
+}
+
+// propertyNaming returns the naming strategy of a class's JSON properties:
+// the @JsonNaming of the class or its nearest annotated superclass, else the
+// project-wide strategy
+func (pool *ComponentPool) propertyNaming(fullClassName string) string {
+	for _, className := range append([]string{fullClassName}, pool.superClassChain(fullClassName)...) {
+		decl := pool.declMap[className]
+		if decl == nil {
+			continue
+		}
+		if ann, ok := findAnnotation(decl.Annotations, "JsonNaming"); ok {
+			return pool.elementValue(className, ann.Expressions["value"])
+		}
+	}
+	return pool.PropertyNaming
+}
+
+// annotationModels evaluates the annotations of a member declared in a class
+func (pool *ComponentPool) annotationModels(fromClass string, annotations []javaparser.Annotation) []model.Annotation {
+	var models []model.Annotation
+	for _, ann := range annotations {
+		models = append(models, pool.annotationModel(fromClass, ann))
+	}
+	return models
+}
+
+// annotationModel evaluates the elements of an annotation: String constants
+// and concatenations read as their value, numeric literals and constants as
+// written, class literals as the simple class name; anything else (enum
+// constants such as JsonInclude.Include.NON_NULL) is kept as source text
+func (pool *ComponentPool) annotationModel(fromClass string, ann javaparser.Annotation) model.Annotation {
+	result := model.Annotation{Name: extractSimpleTypeName(ann.Name), Values: make(map[string][]string)}
+	for key, expr := range ann.Expressions {
+		for _, element := range javaparser.ArrayElements(expr) {
+			result.Values[key] = append(result.Values[key], pool.elementValue(fromClass, element))
+		}
+	}
+	return result
+}
+
+// elementValue evaluates one annotation element value
+func (pool *ComponentPool) elementValue(fromClass, element string) string {
+	if value, ok := pool.EvaluateString(fromClass, element); ok {
+		return value
+	}
+	if className, ok := strings.CutSuffix(element, ".class"); ok {
+		return extractSimpleTypeName(className)
+	}
+	if number, ok := numericLiteral(element); ok {
+		return number
+	}
+	// A numeric constant: MAX_LENGTH, UserConstants.NAME_MAX
+	owner, name := fromClass, element
+	if idx := strings.LastIndex(element, "."); idx >= 0 {
+		owner, name = pool.ResolveTypeName(fromClass, element[:idx]), element[idx+1:]
+	}
+	if _, field := pool.findConstantField(owner, name); field != nil {
+		if number, ok := numericLiteral(field.Initializer); ok {
+			return number
+		}
+	}
+	return element
+}
+
+// numericLiteral reads an integer or decimal literal ("20", "-1", "100L", "0.5")
+func numericLiteral(text string) (string, bool) {
+	text = strings.ReplaceAll(strings.Join(strings.Fields(text), ""), "_", "")
+	text = strings.TrimRight(text, "LlFfDd")
+	if _, err := strconv.ParseFloat(text, 64); err != nil {
+		return "", false
+	}
+	return text, true
+}
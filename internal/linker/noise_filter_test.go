@@ -122,45 +122,3 @@ func TestNoiseFilter(t *testing.T) {
 
 	t.Log("✅ Noise filter test completed")
 }
-
-// TestIgnoredTokensMap verifies the IgnoredTokens map contains expected keywords
-func TestIgnoredTokensMap(t *testing.T) {
-	expectedKeywords := []string{
-		"if", "else", "for", "while", "return", "new", "this", "super",
-		"System", "ModelAndView", "println", "log",
-	}
-
-	for _, keyword := range expectedKeywords {
-		if !IgnoredTokens[keyword] {
-			t.Errorf("Expected keyword '%s' to be in IgnoredTokens map", keyword)
-		}
-	}
-
-	t.Logf("✅ IgnoredTokens map contains %d entries", len(IgnoredTokens))
-}
-
-// TestConstructorFilter verifies that constructor calls are properly filtered
-func TestConstructorFilter(t *testing.T) {
-	testCases := []struct {
-		name     string
-		varName  string
-		expected bool
-	}{
-		{"ModelAndView constructor", "ModelAndView", true},
-		{"ResponseEntity constructor", "ResponseEntity", true},
-		{"ArrayList constructor", "ArrayList", true},
-		{"HashMap constructor", "HashMap", true},
-		{"System class", "System", true},
-		{"Valid UserService", "UserService", false},
-		{"Valid ProductMapper", "ProductMapper", false},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			result := isConstructorCall(tc.varName)
-			if result != tc.expected {
-				t.Errorf("isConstructorCall(%s) = %v, expected %v", tc.varName, result, tc.expected)
-			}
-		})
-	}
-}
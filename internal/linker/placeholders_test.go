@@ -0,0 +1,142 @@
+package linker
+
+import (
+	"strings"
+	"testing"
+
+	"spec-recon/internal/analyzer"
+	"spec-recon/internal/model"
+	"spec-recon/internal/xmlparser"
+)
+
+// TestPlaceholderParams verifies that SQL placeholders reached from a handler
+// document the keys of its Map and servlet request inputs
+func TestPlaceholderParams(t *testing.T) {
+	sources := []string{
+		`package com.company.board;
+
+@Controller
+@RequestMapping("/board")
+public class BoardController {
+    @Autowired
+    private BoardService boardService;
+
+    @ResponseBody
+    @RequestMapping("/list.do")
+    public List<Map<String, Object>> list(HttpServletRequest request, @RequestParam String boardId) {
+        Map<String, Object> params = new HashMap<>();
+        params.put("boardId", boardId);
+        params.put("keyword", request.getParameter("keyword"));
+        return boardService.list(params);
+    }
+
+    @ResponseBody
+    @RequestMapping("/delete.do")
+    public int delete(@RequestBody Map<String, Object> params) {
+        return boardService.delete(params);
+    }
+}
+`,
+		`package com.company.board;
+
+@Service
+public class BoardService {
+    @Autowired
+    private BoardMapper boardMapper;
+
+    public List<Map<String, Object>> list(Map<String, Object> params) {
+        return boardMapper.selectList(params);
+    }
+
+    public int delete(Map<String, Object> params) {
+        boardMapper.deleteComments(params);
+        return boardMapper.deletePosts(params);
+    }
+}
+`,
+		`package com.company.board;
+
+@Mapper
+public interface BoardMapper {
+    List<Map<String, Object>> selectList(Map<String, Object> params);
+    int deleteComments(Map<String, Object> params);
+    int deletePosts(Map<String, Object> params);
+}
+`,
+	}
+	mapperXML := `<?xml version="1.0" encoding="UTF-8"?>
+<mapper namespace="com.company.board.BoardMapper">
+    <select id="selectList" parameterType="map" resultType="map">
+        <bind name="pattern" value="'%' + keyword + '%'"/>
+        SELECT POST_ID, TITLE FROM TB_POST
+        WHERE BOARD_ID = #{boardId}
+        <if test="keyword != null">AND TITLE LIKE #{pattern}</if>
+        ORDER BY ${sortColumn}
+    </select>
+    <delete id="deleteComments" parameterType="map">
+        DELETE FROM TB_COMMENT WHERE BOARD_ID = #{boardId} AND DEL_TYPE = #{deleteType} AND POST_ID IN
+        <foreach collection="postIds" item="id" open="(" separator="," close=")">#{id}</foreach>
+    </delete>
+    <delete id="deletePosts" parameterType="map">
+        DELETE FROM TB_POST WHERE BOARD_ID = #{boardId} AND POST_ID IN
+        <foreach collection="postIds" item="id" open="(" separator="," close=")">#{id}</foreach>
+    </delete>
+</mapper>
+`
+
+	pool := NewTestPool(t, sources...)
+	mapper, err := xmlparser.ParseXMLFile(mapperXML)
+	if err != nil {
+		t.Fatalf("ParseXMLFile failed: %v", err)
+	}
+	pool.AddMapperXML(mapper)
+	tree := NewLinker(pool).BuildCallGraph()
+
+	selectList := pool.GetSQL("com.company.board.BoardMapper", "selectList")
+	if got := describePlaceholders(selectList.Placeholders); got != "#{boardId} ${sortColumn}" {
+		t.Errorf("Bind and foreach variables should not be inputs, got %s", got)
+	}
+
+	endpoints := analyzer.ExtractEndpoints(tree, pool.ClassMap, pool.FieldTypeMap)
+	byPath := make(map[string]model.EndpointDef)
+	for _, ep := range endpoints {
+		byPath[ep.Path] = ep
+	}
+
+	// Servlet request: inferred query parameters, minus the declared boardId
+	list := byPath["/board/list.do"]
+	var inferred []model.ParamDef
+	for _, param := range list.Params {
+		if param.Inferred {
+			inferred = append(inferred, param)
+		}
+	}
+	if len(inferred) != 1 || inferred[0].Name != "sortColumn" || inferred[0].In != "Query" || inferred[0].Required {
+		t.Fatalf("Expected one optional inferred sortColumn parameter, got %+v", inferred)
+	}
+	if want := "Inferred from ${sortColumn} in BoardMapper.selectList (low confidence)"; inferred[0].Description != want {
+		t.Errorf("Description = %q, want %q", inferred[0].Description, want)
+	}
+
+	// Request body Map: inferred keys become its fields
+	remove := byPath["/board/delete.do"]
+	if len(remove.Params) != 1 {
+		t.Fatalf("Expected the body parameter only, got %+v", remove.Params)
+	}
+	fields := remove.Params[0].Fields
+	if len(fields) != 2 || fields[0].Name != "boardId" || fields[1].Name != "deleteType" || !fields[0].Inferred {
+		t.Fatalf("Unexpected inferred body fields: %+v", fields)
+	}
+	if want := "Inferred from #{boardId} in BoardMapper.deleteComments and 1 more (low confidence)"; fields[0].Description != want {
+		t.Errorf("Description = %q, want %q", fields[0].Description, want)
+	}
+}
+
+// describePlaceholders renders placeholders as written in the mapper
+func describePlaceholders(placeholders []model.Placeholder) string {
+	parts := make([]string, len(placeholders))
+	for i, placeholder := range placeholders {
+		parts[i] = placeholder.Syntax()
+	}
+	return strings.Join(parts, " ")
+}
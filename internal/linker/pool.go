@@ -2,11 +2,11 @@ package linker
 
 import (
 	"fmt"
-	"regexp"
 	"strings"
 
 	"spec-recon/internal/javaparser"
 	"spec-recon/internal/model"
+	"spec-recon/internal/sqlparser"
 	"spec-recon/internal/xmlparser"
 )
 
@@ -15,12 +15,15 @@ type ComponentPool struct {
 	// ClassMap: FullClassName -> Node
 	ClassMap map[string]*model.Node
 
-	// MethodMap: FullClassName.MethodName -> Node
+	// MethodMap: FullClassName.MethodName(ParamTypes) -> Node
 	MethodMap map[string]*model.Node
 
-	// MethodBodyMap: FullClassName.MethodName -> Body content
+	// MethodBodyMap: FullClassName.MethodName(ParamTypes) -> Body content
 	MethodBodyMap map[string]string
 
+	// OverloadMap: FullClassName.MethodName -> all overloads, in declaration order
+	OverloadMap map[string][]*model.Node
+
 	// SQLMap: Namespace.ID -> Node
 	SQLMap map[string]*model.Node
 
@@ -29,6 +32,28 @@ type ComponentPool struct {
 
 	// Source content for call tracing
 	SourceMap map[string]string // FullClassName -> file content
+
+	// SuperTypeMap: FullClassName -> resolved direct supertypes, superclass first (see BuildHierarchy)
+	SuperTypeMap map[string][]string
+
+	// ImplementationMap: FullTypeName -> concrete classes implementing or extending it
+	ImplementationMap map[string][]string
+
+	// BeanMap: bean name (or class-level @Qualifier) -> FullClassName
+	BeanMap map[string]string
+
+	// PropertyNaming: project-wide Jackson naming strategy ("SNAKE_CASE"), for classes without @JsonNaming
+	PropertyNaming string
+
+	declMap         map[string]*javaparser.JavaClass // FullClassName -> parsed declaration
+	abstractMethods map[string]bool                  // Method keys declared without a body
+	bodyLines       map[string]int                   // Method key -> line of the body's opening brace (see linkJdbcCalls)
+	primaryBeans    map[string]bool                  // FullClassNames annotated with @Primary
+	springContexts  []*xmlparser.SpringContext       // XML contexts, applied by ApplySpringContexts
+	resultMaps      map[string]*xmlparser.ResultMap  // Namespace.ID -> <resultMap>, applied by ResolveResultMaps
+	selectColumns   map[string][]string              // Namespace.ID -> select list of a resultType statement (see ResolveResultMaps)
+	sqlProviders    map[string]string                // Namespace.ID -> SQL provider Class.method (see AddAnnotatedStatements)
+	entities        map[string]*jpaEntity            // FullClassName -> @Entity table mapping (see AddJpaRepositories)
 }
 
 // NewComponentPool creates a new empty component pool
@@ -37,26 +62,48 @@ func NewComponentPool() *ComponentPool {
 		ClassMap:      make(map[string]*model.Node),
 		MethodMap:     make(map[string]*model.Node),
 		MethodBodyMap: make(map[string]string),
+		OverloadMap:   make(map[string][]*model.Node),
 		SQLMap:        make(map[string]*model.Node),
 		FieldTypeMap:  make(map[string]map[string]string),
 		SourceMap:     make(map[string]string),
+
+		SuperTypeMap:      make(map[string][]string),
+		ImplementationMap: make(map[string][]string),
+		declMap:           make(map[string]*javaparser.JavaClass),
+		abstractMethods:   make(map[string]bool),
+		bodyLines:         make(map[string]int),
+		BeanMap:           make(map[string]string),
+		primaryBeans:      make(map[string]bool),
+		resultMaps:        make(map[string]*xmlparser.ResultMap),
+		selectColumns:     make(map[string][]string),
+		sqlProviders:      make(map[string]string),
+		entities:          make(map[string]*jpaEntity),
 	}
 }
 
-// AddJavaClass adds a parsed Java class to the pool
+// AddJavaClass adds a parsed Java class and its nested types to the pool
+// Nested types are registered under their qualified name (Outer.Inner)
 func (pool *ComponentPool) AddJavaClass(javaClass *javaparser.JavaClass, sourceContent string) error {
-	fullClassName := javaClass.Package + "." + javaClass.Name
+	fullClassName := javaClass.QualifiedName()
 
 	// Create class node
+	classDoc := newDocComment(javaClass.JavaDoc)
 	classNode := &model.Node{
 		ID:       fullClassName,
 		Type:     determineNodeType(javaClass),
 		Package:  javaClass.Package,
+		File:     javaClass.File,
+		Line:     javaClass.Line,
 		Method:   "", // Class node usually has empty method name or class name
+		Comment:  docSummary(classDoc),
+		Doc:      classDoc,
 		Children: []*model.Node{},
+		Fields:   []model.FieldInfo{},
+		Kind:     javaClass.Kind,
 	}
 
 	pool.ClassMap[fullClassName] = classNode
+	pool.declMap[fullClassName] = javaClass
 	pool.SourceMap[fullClassName] = sourceContent
 
 	// Build field type map
@@ -65,21 +112,51 @@ func (pool *ComponentPool) AddJavaClass(javaClass *javaparser.JavaClass, sourceC
 		// Extract simple type name from full type
 		simpleType := extractSimpleTypeName(field.Type)
 		fieldTypes[field.Name] = simpleType
+
+		classNode.Fields = append(classNode.Fields, model.FieldInfo{
+			Name:    field.Name,
+			Type:    simpleType,
+			File:    javaClass.File,
+			Line:    field.Line,
+			Comment: docSummary(newDocComment(field.JavaDoc)),
+		})
 	}
 	pool.FieldTypeMap[fullClassName] = fieldTypes
 
+	pool.registerBean(javaClass, classNode)
+	for _, point := range javaClass.InjectionPoints() {
+		classNode.Injections = append(classNode.Injections, model.InjectionInfo{
+			Name:      point.Name,
+			Type:      extractSimpleTypeName(point.Type),
+			Qualifier: point.Qualifier,
+			Kind:      point.Kind,
+			Line:      point.Line,
+		})
+		// Setter and constructor injection may store into a field declared elsewhere
+		if _, ok := fieldTypes[point.Name]; !ok {
+			fieldTypes[point.Name] = extractSimpleTypeName(point.Type)
+		}
+	}
+
 	// Add methods
 	for _, method := range javaClass.Methods {
-		methodKey := fullClassName + "." + method.Name
+		methodKey := fullClassName + "." + method.Signature()
+		methodDoc := newDocComment(method.JavaDoc)
 
 		methodNode := &model.Node{
 			ID:           methodKey,
 			Type:         classNode.Type, // Inherit from class
 			Package:      javaClass.Package,
+			File:         javaClass.File,
+			Line:         method.Line,
 			Method:       method.Name,
 			Params:       method.Params,
+			ParamTypes:   method.ParamTypes(),
+			Varargs:      method.IsVarargs(),
 			ReturnDetail: method.ReturnType,
 			Body:         method.Body, // Store body for return type inference
+			Comment:      docSummary(methodDoc),
+			Doc:          methodDoc,
 			URL:          extractURL(javaClass, &method),
 			Annotation:   method.GetHTTPMethod(), // Store HTTP Method (GET, POST) here
 			Children:     []*model.Node{},
@@ -87,45 +164,129 @@ func (pool *ComponentPool) AddJavaClass(javaClass *javaparser.JavaClass, sourceC
 
 		pool.MethodMap[methodKey] = methodNode
 		pool.MethodBodyMap[methodKey] = method.Body
+		pool.bodyLines[methodKey] = method.BodyLine
+		if isAbstractDeclaration(javaClass, &method) {
+			pool.abstractMethods[methodKey] = true
+		}
+
+		nameKey := fullClassName + "." + method.Name
+		pool.OverloadMap[nameKey] = append(pool.OverloadMap[nameKey], methodNode)
 
 		// Add method as child of class
 		methodNode.Parent = classNode
 		classNode.Children = append(classNode.Children, methodNode)
 	}
 
+	for _, inner := range javaClass.InnerClasses {
+		if err := pool.AddJavaClass(inner, sourceContent); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// AddMapperXML adds a parsed MyBatis XML to the pool
+// AddMapperXML adds a parsed MyBatis XML (or iBatis sqlMap) to the pool
 func (pool *ComponentPool) AddMapperXML(mapperXML *xmlparser.MapperXML) error {
 	for _, sql := range mapperXML.SQLs {
-		sqlKey := mapperXML.Namespace + "." + sql.ID
+		sqlKey := sql.ID
+		if mapperXML.Namespace != "" {
+			sqlKey = mapperXML.Namespace + "." + sql.ID
+		}
 
 		sqlNode := &model.Node{
 			ID:      sqlKey,
 			Type:    model.NodeTypeSQL,
 			Package: mapperXML.Namespace,
+			File:    mapperXML.File,
+			Line:    sql.Line,
 			Method:  sql.ID,
 			// Note: Node struct doesn't have SQLQuery field, putting it in Comment or similar if needed.
 			// Ideally Node definition should have query info, or we use Comment field for now.
-			Comment:  sql.Content,
-			Children: []*model.Node{},
+			Comment:       sql.Content,
+			Conditions:    sql.Root.Conditions(),
+			ParameterType: sql.ParameterType,
+			ResultType:    sql.ResultType,
+			Children:      []*model.Node{},
+		}
+		sqlNode.Params = formatConditions(sqlNode.Conditions)
+		plainSQL := sql.Content
+		if sql.Root != nil {
+			plainSQL = sql.Root.PlainSQL()
+		}
+		sqlNode.Tables = tableAccesses(plainSQL)
+		sqlNode.Placeholders = placeholders(plainSQL, sql.Root.LocalNames())
+		if sql.ResultMap != "" {
+			sqlNode.ResultMap = qualifyID(sql.ResultMap, mapperXML.Namespace)
+		} else if sql.ResultType != "" {
+			pool.selectColumns[sqlKey] = sqlparser.SelectColumns(plainSQL)
 		}
 
 		pool.SQLMap[sqlKey] = sqlNode
 	}
+	for _, resultMap := range mapperXML.ResultMaps {
+		pool.resultMaps[qualifyID(resultMap.ID, mapperXML.Namespace)] = resultMap
+	}
 
 	return nil
 }
 
+// formatConditions lists dynamic SQL conditions as optional inputs,
+// e.g. "userName != null (optional), type == 'A' (optional)"
+func formatConditions(conditions []string) string {
+	parts := make([]string, len(conditions))
+	for i, condition := range conditions {
+		parts[i] = condition + " (optional)"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// placeholders reads the named inputs of a statement, leaving out those
+// rooted at a name the statement defines itself (foreach items, binds) and
+// the MyBatis built-ins
+func placeholders(sql string, localNames map[string]bool) []model.Placeholder {
+	var result []model.Placeholder
+	for _, param := range sqlparser.Parameters(sql) {
+		root := param.Name
+		if idx := strings.IndexAny(root, ".["); idx >= 0 {
+			root = root[:idx]
+		}
+		if localNames[root] || root == "_parameter" || root == "_databaseId" {
+			continue
+		}
+		result = append(result, model.Placeholder{Name: param.Name, Raw: param.Raw})
+	}
+	return result
+}
+
+// tableAccesses reads the tables and columns a SQL statement uses
+func tableAccesses(sql string) []model.TableAccess {
+	var tables []model.TableAccess
+	for _, access := range sqlparser.Analyze(sql) {
+		tables = append(tables, model.TableAccess{
+			Table:      access.Table,
+			Operations: access.Operations,
+			Columns:    access.Columns,
+		})
+	}
+	return tables
+}
+
 // GetClass retrieves a class node by full class name
 func (pool *ComponentPool) GetClass(fullClassName string) *model.Node {
 	return pool.ClassMap[fullClassName]
 }
 
 // GetMethod retrieves a method node by full method key
+// The key is either a signature ID ("pkg.Class.save(UserDto)") or a plain
+// name ("pkg.Class.save"); a plain name returns the first declared overload
 func (pool *ComponentPool) GetMethod(fullMethodKey string) *model.Node {
-	return pool.MethodMap[fullMethodKey]
+	if node := pool.MethodMap[fullMethodKey]; node != nil {
+		return node
+	}
+	if overloads := pool.OverloadMap[fullMethodKey]; len(overloads) > 0 {
+		return overloads[0]
+	}
+	return nil
 }
 
 // GetSQL retrieves a SQL node by namespace and ID
@@ -135,11 +296,21 @@ func (pool *ComponentPool) GetSQL(namespace, id string) *model.Node {
 }
 
 // GetFieldType returns the type of a field in a class
+// Fields inherited from superclasses are found once BuildHierarchy has run
 func (pool *ComponentPool) GetFieldType(fullClassName, fieldName string) string {
-	if fieldTypes, ok := pool.FieldTypeMap[fullClassName]; ok {
-		return fieldTypes[fieldName]
+	_, fieldType := pool.findField(fullClassName, fieldName)
+	return fieldType
+}
+
+// findField looks a field up in a class and then along its superclass chain
+// Returns the declaring class and the field type, or empty strings
+func (pool *ComponentPool) findField(fullClassName, fieldName string) (string, string) {
+	for _, className := range append([]string{fullClassName}, pool.superClassChain(fullClassName)...) {
+		if fieldType, ok := pool.FieldTypeMap[className][fieldName]; ok {
+			return className, fieldType
+		}
 	}
-	return ""
+	return "", ""
 }
 
 // GetSourceContent retrieves the source code for a class
@@ -161,6 +332,13 @@ func determineNodeType(javaClass *javaparser.JavaClass) model.NodeType {
 		}
 	}
 
+	// MyBatis annotation mappers need not be annotated with @Mapper
+	for i := range javaClass.Methods {
+		if javaClass.Methods[i].MapperStatement() != nil {
+			return model.NodeTypeMapper
+		}
+	}
+
 	// Check class name patterns
 	if strings.HasSuffix(javaClass.Name, "Controller") {
 		return model.NodeTypeController
@@ -176,6 +354,18 @@ func determineNodeType(javaClass *javaparser.JavaClass) model.NodeType {
 	return model.NodeTypeUtil
 }
 
+// isAbstractDeclaration reports whether a method has no implementation of its own
+// Methods built by hand (without a parsed body flag) count as abstract only in interfaces
+func isAbstractDeclaration(javaClass *javaparser.JavaClass, method *javaparser.Method) bool {
+	if method.HasModifier("abstract") {
+		return true
+	}
+	if method.HasBody || method.Body != "" || method.HasModifier("default") || method.HasModifier("static") {
+		return false
+	}
+	return javaClass.Kind == javaparser.KindInterface
+}
+
 func extractSimpleTypeName(fullType string) string {
 	// Get last part after dot: com.company.UserService -> UserService
 	// Note: We deliberately PRESERVE generics (e.g. List<String>) so they can be stored in FieldTypeMap
@@ -183,40 +373,186 @@ func extractSimpleTypeName(fullType string) string {
 	return strings.TrimSpace(parts[len(parts)-1])
 }
 
+// newDocComment parses a raw JavaDoc comment into the model representation
+// Returns nil when there is no documentation
+func newDocComment(raw string) *model.DocComment {
+	if raw == "" {
+		return nil
+	}
+	parsed := javaparser.ParseJavaDoc(raw)
+	if parsed.IsEmpty() {
+		return nil
+	}
+
+	doc := &model.DocComment{
+		Summary:     parsed.Summary,
+		Description: parsed.Description,
+		Params:      parsed.Params,
+		Return:      parsed.Return,
+		Throws:      make(map[string]string),
+	}
+	for _, t := range parsed.Throws {
+		doc.Throws[t.Type] = t.Description
+	}
+	return doc
+}
+
+func docSummary(doc *model.DocComment) string {
+	if doc == nil {
+		return ""
+	}
+	return doc.Summary
+}
+
 func extractURL(javaClass *javaparser.JavaClass, method *javaparser.Method) string {
 	classURL := javaClass.GetClassLevelURL()
 	methodURL := method.GetMethodURL(classURL)
 	return methodURL
 }
 
-// FindMethodByName finds methods with a specific name in a class
+// FindMethodByName returns every overload of methodName declared in a class
 func (pool *ComponentPool) FindMethodByName(fullClassName, methodName string) []*model.Node {
-	var results []*model.Node
+	return pool.OverloadMap[fullClassName+"."+methodName]
+}
 
-	// Look for exact match first
-	exactKey := fullClassName + "." + methodName
-	if node := pool.MethodMap[exactKey]; node != nil {
-		results = append(results, node)
-		return results
+// ResolveMethod picks the overloads of methodName that accept the given arguments
+// argTypes holds one entry per argument; an empty entry means the type is unknown
+// Candidates must match the argument count (varargs accept any count from
+// len(params)-1 up); among those, the ones with the most matching known argument
+// types win. Several methods are returned only when they are equally good
+// Methods inherited from a supertype are considered when the class declares none
+func (pool *ComponentPool) ResolveMethod(fullClassName, methodName string, argTypes []string) []*model.Node {
+	var best []*model.Node
+	bestScore := -1
+
+	for _, candidate := range pool.findInheritedMethods(fullClassName, methodName) {
+		score, ok := matchArguments(candidate, argTypes)
+		if !ok {
+			continue
+		}
+		if score > bestScore {
+			best = []*model.Node{candidate}
+			bestScore = score
+		} else if score == bestScore {
+			best = append(best, candidate)
+		}
 	}
 
-	// Look for partial matches (in case of overloading)
-	prefix := fullClassName + "."
-	for key, node := range pool.MethodMap {
-		if strings.HasPrefix(key, prefix) && strings.Contains(key, methodName) {
-			results = append(results, node)
+	return best
+}
+
+// findInheritedMethods returns the overloads of methodName declared in a class or,
+// when it declares none, in the nearest supertype that does
+func (pool *ComponentPool) findInheritedMethods(fullClassName, methodName string) []*model.Node {
+	if methods := pool.FindMethodByName(fullClassName, methodName); len(methods) > 0 {
+		return methods
+	}
+	for _, superType := range pool.AllSuperTypes(fullClassName) {
+		if methods := pool.FindMethodByName(superType, methodName); len(methods) > 0 {
+			return methods
 		}
 	}
+	return nil
+}
 
-	return results
+// matchArguments reports whether a method accepts the arguments and how many
+// known argument types match its parameter types exactly
+func matchArguments(method *model.Node, argTypes []string) (int, bool) {
+	params := method.ParamTypes
+	if method.Varargs {
+		if len(argTypes) < len(params)-1 {
+			return 0, false
+		}
+	} else if len(argTypes) != len(params) {
+		return 0, false
+	}
+
+	score := 0
+	for i, argType := range argTypes {
+		paramType := params[min(i, len(params)-1)]
+		if method.Varargs && i >= len(params)-1 {
+			// Either the element type or an array passed straight through
+			element := strings.TrimSuffix(paramType, "...")
+			if argType == element || argType == element+"[]" {
+				score++
+			}
+			continue
+		}
+		if argType == "" {
+			continue
+		}
+		if typesCompatible(argType, paramType) {
+			score++
+		} else if isKnownMismatch(argType, paramType) {
+			return 0, false
+		}
+	}
+	return score, true
+}
+
+// boxedTypes maps primitives to their wrapper classes
+var boxedTypes = map[string]string{
+	"int": "Integer", "long": "Long", "double": "Double", "float": "Float",
+	"boolean": "Boolean", "char": "Character", "short": "Short", "byte": "Byte",
+}
+
+// typesCompatible reports whether an argument of argType fits a parameter of
+// paramType without considering the class hierarchy (boxing and widening only)
+func typesCompatible(argType, paramType string) bool {
+	if argType == paramType || paramType == "Object" {
+		return true
+	}
+	if boxedTypes[argType] == paramType || boxedTypes[paramType] == argType {
+		return true
+	}
+	switch argType {
+	case "int":
+		return paramType == "long" || paramType == "double" || paramType == "float"
+	case "long", "float":
+		return paramType == "double"
+	}
+	return false
+}
+
+// isKnownMismatch reports whether argType can never be passed as paramType
+// Only literal-like types (String, primitives) are certain; anything else may be
+// a subtype we cannot see
+func isKnownMismatch(argType, paramType string) bool {
+	certain := func(t string) bool {
+		_, primitive := boxedTypes[t]
+		return primitive || t == "String"
+	}
+	if !certain(argType) {
+		return false
+	}
+	if _, isPrimitive := boxedTypes[paramType]; isPrimitive || paramType == "String" {
+		return !typesCompatible(argType, paramType)
+	}
+	// A primitive or String can still be passed as Number, CharSequence, Serializable, ...
+	return false
+}
+
+// splitMethodKey splits "pkg.Class.method(Types)" into the class name and method name
+func splitMethodKey(methodKey string) (string, string) {
+	nameKey := methodKey
+	if idx := strings.Index(nameKey, "("); idx >= 0 {
+		nameKey = nameKey[:idx]
+	}
+	lastDot := strings.LastIndex(nameKey, ".")
+	if lastDot == -1 {
+		return "", nameKey
+	}
+	return nameKey[:lastDot], nameKey[lastDot+1:]
 }
 
 // ResolveFieldType resolves a field name to its full class name
 func (pool *ComponentPool) ResolveFieldType(fullClassName, fieldName string) string {
-	rawType := pool.GetFieldType(fullClassName, fieldName)
+	declaringClass, rawType := pool.findField(fullClassName, fieldName)
 	if rawType == "" {
 		return ""
 	}
+	// Resolve relative to the class that declares the field (it may be a superclass in another package)
+	fullClassName = declaringClass
 
 	// Strip generics for linking purposes (e.g. List<User> -> List)
 	searchType := rawType
@@ -253,40 +589,83 @@ func extractPackage(fullClassName string) string {
 	return fullClassName[:lastDot]
 }
 
-// FindMethodCalls finds method calls in a source string
+// FindMethodCalls finds qualified method calls (receiver.method(...)) in a source string
+// The source is tokenized, so keywords, literals and comments never produce calls
 func FindMethodCalls(source string) []MethodCall {
 	var calls []MethodCall
 
-	// Pattern 1: instance.methodName(
-	instancePattern := regexp.MustCompile(`(\w+)\.(\w+)\s*\(`)
-	matches := instancePattern.FindAllStringSubmatch(source, -1)
-	for _, match := range matches {
-		calls = append(calls, MethodCall{
-			Variable:   match[1],
-			MethodName: match[2],
-			IsStatic:   false,
-		})
-	}
+	tokens := javaparser.Tokenize(source)
+	for i := 0; i+3 < len(tokens); i++ {
+		receiver, dot, name, paren := tokens[i], tokens[i+1], tokens[i+2], tokens[i+3]
+		if receiver.Kind != javaparser.TokenIdent || !dot.Is(".") || name.Kind != javaparser.TokenIdent || !paren.Is("(") {
+			continue
+		}
+
+		// Skip qualified constructor calls: new com.company.Foo(...)
+		if i > 0 && tokens[i-1].Is("new") {
+			continue
+		}
 
-	// Pattern 2: ClassName.methodName( (static calls)
-	staticPattern := regexp.MustCompile(`([A-Z]\w+)\.(\w+)\s*\(`)
-	matches = staticPattern.FindAllStringSubmatch(source, -1)
-	for _, match := range matches {
+		// ClassName.methodName( is treated as a static call
+		first := receiver.Text[0]
 		calls = append(calls, MethodCall{
-			Variable:   match[1], // This is the class name
-			MethodName: match[2],
-			IsStatic:   true,
+			Variable:   receiver.Text,
+			MethodName: name.Text,
+			IsStatic:   first >= 'A' && first <= 'Z',
+			Args:       splitArguments(source, tokens, i+3),
 		})
 	}
 
 	return calls
 }
 
+// splitArguments returns the source text of each argument of the call whose
+// opening parenthesis is tokens[open]
+func splitArguments(source string, tokens []javaparser.Token, open int) []string {
+	args := []string{}
+	depth := 0
+	start := open + 1
+
+	for i := open; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.Kind != javaparser.TokenOperator {
+			continue
+		}
+		switch tok.Text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+			if depth == 0 {
+				if i > start || len(args) > 0 {
+					args = append(args, tokenText(source, tokens, start, i))
+				}
+				return args
+			}
+		case ",":
+			if depth == 1 {
+				args = append(args, tokenText(source, tokens, start, i))
+				start = i + 1
+			}
+		}
+	}
+	return args
+}
+
+// tokenText returns the normalized source text of tokens[from:to]
+func tokenText(source string, tokens []javaparser.Token, from, to int) string {
+	if to <= from {
+		return ""
+	}
+	return strings.Join(strings.Fields(source[tokens[from].Pos:tokens[to-1].End]), " ")
+}
+
 // MethodCall represents a method invocation found in source code
 type MethodCall struct {
 	Variable   string // Variable name or class name
 	MethodName string
 	IsStatic   bool
+	Args       []string // Source text of each argument
 }
 
 func (mc MethodCall) String() string {
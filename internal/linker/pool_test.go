@@ -0,0 +1,137 @@
+package linker
+
+import (
+	"testing"
+
+	"spec-recon/internal/javaparser"
+	"spec-recon/internal/xmlparser"
+)
+
+// TestSourceLocations verifies that class, method, field and SQL nodes carry file and line
+func TestSourceLocations(t *testing.T) {
+	javaSource := `package com.company.legacy;
+
+public interface UserMapper {
+
+    /** 사용자 수 */
+    int COUNT = 0;
+
+    int selectUserCount();
+}
+`
+	xmlSource := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE mapper PUBLIC "-//mybatis.org//DTD Mapper 3.0//EN" "http://mybatis.org/dtd/mybatis-3-mapper.dtd">
+<mapper namespace="com.company.legacy.UserMapper">
+
+    <select id="selectUserCount" resultType="int">
+        SELECT COUNT(*) FROM TB_USER
+    </select>
+</mapper>
+`
+	cls, err := javaparser.ParseJavaFileWithPath("src/main/java/com/company/legacy/UserMapper.java", javaSource)
+	if err != nil {
+		t.Fatalf("ParseJavaFileWithPath failed: %v", err)
+	}
+	mapper, err := xmlparser.ParseXMLFileWithPath("src/main/resources/sqlmap/UserMapper.xml", xmlSource)
+	if err != nil {
+		t.Fatalf("ParseXMLFileWithPath failed: %v", err)
+	}
+
+	pool := NewComponentPool()
+	pool.AddJavaClass(cls, javaSource)
+	pool.AddMapperXML(mapper)
+
+	classNode := pool.GetClass("com.company.legacy.UserMapper")
+	if got := classNode.Source(); got != "src/main/java/com/company/legacy/UserMapper.java:3" {
+		t.Errorf("Unexpected class source %q", got)
+	}
+	if field := classNode.GetField("COUNT"); field == nil || field.Line != 6 || field.Comment != "사용자 수" {
+		t.Errorf("Unexpected field info %+v", field)
+	}
+
+	methodNode := pool.GetMethod("com.company.legacy.UserMapper.selectUserCount")
+	if got := methodNode.Source(); got != "src/main/java/com/company/legacy/UserMapper.java:8" {
+		t.Errorf("Unexpected method source %q", got)
+	}
+
+	sqlNode := pool.GetSQL("com.company.legacy.UserMapper", "selectUserCount")
+	if got := sqlNode.Source(); got != "src/main/resources/sqlmap/UserMapper.xml:5" {
+		t.Errorf("Unexpected SQL source %q", got)
+	}
+}
+
+// TestOverloadResolution verifies that overloads get distinct nodes and that calls
+// are resolved by argument count and type instead of by name prefix
+func TestOverloadResolution(t *testing.T) {
+	serviceSource := `package com.company.modern;
+
+@Service
+public class OrderService {
+    public void save(OrderDto order) {}
+    public void save(OrderDto order, boolean draft) {}
+    public void save(String orderNo) {}
+    public void saveAll(List<OrderDto> orders) {}
+    public void saveDraft(OrderDto order) {}
+    public void notify(String... targets) {}
+}
+`
+	controllerSource := `package com.company.modern;
+
+@RestController
+public class OrderController {
+    private OrderService orderService;
+
+    @PostMapping("/orders")
+    public void create(OrderDto order) {
+        orderService.save(order, true);
+        orderService.save("ORD-1");
+        orderService.notify("a", "b");
+    }
+}
+`
+	pool := NewComponentPool()
+	for _, src := range []string{serviceSource, controllerSource} {
+		cls, err := javaparser.ParseJavaFile(src)
+		if err != nil {
+			t.Fatalf("ParseJavaFile failed: %v", err)
+		}
+		pool.AddJavaClass(cls, src)
+	}
+
+	overloads := pool.FindMethodByName("com.company.modern.OrderService", "save")
+	if len(overloads) != 3 {
+		t.Fatalf("Expected 3 overloads of save, got %d", len(overloads))
+	}
+	for _, id := range []string{
+		"com.company.modern.OrderService.save(OrderDto)",
+		"com.company.modern.OrderService.save(OrderDto,boolean)",
+		"com.company.modern.OrderService.save(String)",
+	} {
+		if pool.MethodMap[id] == nil {
+			t.Errorf("Missing method node %s", id)
+		}
+	}
+
+	if err := NewLinker(pool).Link(); err != nil {
+		t.Fatalf("Link failed: %v", err)
+	}
+
+	create := pool.GetMethod("com.company.modern.OrderController.create")
+	var got []string
+	for _, child := range create.Children {
+		got = append(got, child.ID)
+	}
+	want := []string{
+		"com.company.modern.OrderService.save(OrderDto,boolean)",
+		"com.company.modern.OrderService.save(String)",
+		"com.company.modern.OrderService.notify(String...)",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected children %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Child %d: expected %s, got %s", i, want[i], got[i])
+		}
+	}
+}
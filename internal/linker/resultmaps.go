@@ -0,0 +1,241 @@
+package linker
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"spec-recon/internal/javaparser"
+	"spec-recon/internal/model"
+	"spec-recon/internal/xmlparser"
+)
+
+// ResolveResultMaps fills the result fields of every statement that declares a
+// resultMap, following extends and nested association/collection resultMaps
+// across namespaces, or a Map resultType. Call it once every mapper is loaded
+// and the hierarchy is built (a project class may extend a Map)
+func (pool *ComponentPool) ResolveResultMaps() {
+	for key, sqlNode := range pool.SQLMap {
+		if sqlNode.ResultMap == "" {
+			if pool.isMapType(sqlNode.ResultType) {
+				sqlNode.ResultFields = mapResultFields(sqlNode.ResultType, pool.selectColumns[key])
+			}
+			continue
+		}
+		resultMap := pool.lookupResultMap(sqlNode.ResultMap, sqlNode.Package)
+		if resultMap == nil {
+			fmt.Printf("[LINKER SKIP] Unknown resultMap '%s' in %s\n", sqlNode.ResultMap, key)
+			continue
+		}
+		sqlNode.ResultType = resultMap.Type
+		sqlNode.ResultFields = pool.resultMapFields(resultMap, map[*xmlparser.ResultMap]bool{})
+	}
+}
+
+// lookupResultMap resolves a resultMap reference made from a namespace
+// An unqualified ID that is not in the namespace (iBatis without
+// useStatementNamespaces) matches when exactly one resultMap has it
+func (pool *ComponentPool) lookupResultMap(ref, namespace string) *xmlparser.ResultMap {
+	if resultMap := pool.resultMaps[qualifyID(ref, namespace)]; resultMap != nil {
+		return resultMap
+	}
+	if resultMap := pool.resultMaps[ref]; resultMap != nil {
+		return resultMap
+	}
+
+	var match *xmlparser.ResultMap
+	for _, resultMap := range pool.resultMaps {
+		if resultMap.ID != ref {
+			continue
+		}
+		if match != nil {
+			return nil // Ambiguous across namespaces
+		}
+		match = resultMap
+	}
+	return match
+}
+
+// resultMapFields flattens a resultMap, its parents first; a property mapped
+// again in the child replaces the inherited mapping
+// active holds the resultMaps being expanded: recursive structures (a category
+// with child categories) stop at the second level
+func (pool *ComponentPool) resultMapFields(resultMap *xmlparser.ResultMap, active map[*xmlparser.ResultMap]bool) []model.ResultField {
+	if active[resultMap] {
+		return nil
+	}
+	active[resultMap] = true
+	defer delete(active, resultMap)
+
+	var fields []model.ResultField
+	if resultMap.Extends != "" {
+		if parent := pool.lookupResultMap(resultMap.Extends, resultMap.Namespace); parent != nil {
+			fields = pool.resultMapFields(parent, active)
+		}
+	}
+	for _, field := range pool.mappingFields(resultMap.Mappings, resultMap.Namespace, "", active) {
+		fields = mergeResultField(fields, field)
+	}
+	return fields
+}
+
+// mappingFields converts the mappings of a resultMap (or of a nested result)
+func (pool *ComponentPool) mappingFields(mappings []xmlparser.ResultMapping, namespace, columnPrefix string,
+	active map[*xmlparser.ResultMap]bool) []model.ResultField {
+	var fields []model.ResultField
+	for _, mapping := range mappings {
+		field := model.ResultField{
+			Property:   mapping.Property,
+			Type:       mapping.JavaType,
+			Collection: mapping.Kind == "collection",
+		}
+
+		nested := mapping.ResultMap != "" || mapping.Select != "" || len(mapping.Mappings) > 0
+		if !nested {
+			field.Column = columnPrefix + mapping.Column
+			if field.Property == "" {
+				field.Property = mapping.Column // Unnamed constructor argument
+			}
+			fields = mergeResultField(fields, field)
+			continue
+		}
+
+		prefix := columnPrefix + mapping.ColumnPrefix
+		field.Fields = pool.mappingFields(mapping.Mappings, namespace, prefix, active)
+		if mapping.ResultMap != "" {
+			if resultMap := pool.lookupResultMap(mapping.ResultMap, namespace); resultMap != nil {
+				if field.Type == "" {
+					field.Type = resultMap.Type
+				}
+				for _, child := range prefixColumns(pool.resultMapFields(resultMap, active), prefix) {
+					field.Fields = mergeResultField(field.Fields, child)
+				}
+			}
+		}
+		if mapping.Kind == "case" {
+			// A discriminator case adds its properties to the enclosing result
+			for _, child := range field.Fields {
+				fields = mergeResultField(fields, child)
+			}
+			continue
+		}
+		fields = mergeResultField(fields, field)
+	}
+	return fields
+}
+
+// prefixColumns applies an association's columnPrefix to nested column names
+func prefixColumns(fields []model.ResultField, prefix string) []model.ResultField {
+	if prefix == "" {
+		return fields
+	}
+	prefixed := make([]model.ResultField, len(fields))
+	for i, field := range fields {
+		prefixed[i] = field
+		if field.Column != "" {
+			prefixed[i].Column = prefix + field.Column
+		}
+		prefixed[i].Fields = prefixColumns(field.Fields, prefix)
+	}
+	return prefixed
+}
+
+// mergeResultField appends field, replacing an earlier mapping of the same property
+func mergeResultField(fields []model.ResultField, field model.ResultField) []model.ResultField {
+	for i := range fields {
+		if field.Property != "" && fields[i].Property == field.Property {
+			fields[i] = field
+			return fields
+		}
+	}
+	return append(fields, field)
+}
+
+// mapResultFields lists the keys of a map result: one per column of the
+// select list. EgovMap converts column names to camelCase
+func mapResultFields(resultType string, columns []string) []model.ResultField {
+	camel := strings.EqualFold(simpleTypeName(resultType), "EgovMap")
+	var fields []model.ResultField
+	for _, column := range columns {
+		property := column
+		if camel {
+			property = camelCase(column)
+		}
+		fields = append(fields, model.ResultField{Property: property, Column: column})
+	}
+	return fields
+}
+
+// mapTypes are the Map classes a statement may return, by lower-case simple
+// name or MyBatis alias
+var mapTypes = map[string]bool{
+	"map":                      true,
+	"hashmap":                  true,
+	"linkedhashmap":            true,
+	"treemap":                  true,
+	"sortedmap":                true,
+	"concurrenthashmap":        true,
+	"egovmap":                  true,
+	"listorderedmap":           true,
+	"caseinsensitivemap":       true,
+	"linkedcaseinsensitivemap": true,
+}
+
+// isMapType reports whether a resultType is a Map, whose keys come from the
+// select list: a known map type (map, hashmap, java.util.*Map, EgovMap, ...)
+// or a project class extending one. Other project classes ("UserRoleMap")
+// are mapped by their properties
+func (pool *ComponentPool) isMapType(typeName string) bool {
+	if pool.ClassMap[typeName] == nil {
+		return isKnownMapType(typeName)
+	}
+	for _, superType := range pool.AllSuperTypes(typeName) {
+		if pool.ClassMap[superType] == nil && isKnownMapType(superType) {
+			return true
+		}
+	}
+	return false
+}
+
+// isKnownMapType reports whether a type outside the project is a Map
+func isKnownMapType(typeName string) bool {
+	name := javaparser.EraseType(typeName)
+	if strings.HasPrefix(name, "java.util.") && strings.HasSuffix(name, "Map") {
+		return true
+	}
+	return mapTypes[strings.ToLower(simpleTypeName(name))]
+}
+
+// camelCase converts a column name to a property name: USER_ID -> userId
+func camelCase(column string) string {
+	if !strings.Contains(column, "_") && strings.ToUpper(column) != column {
+		return column // Already mixed case
+	}
+	var sb strings.Builder
+	upperNext := false
+	for _, r := range strings.ToLower(column) {
+		if r == '_' {
+			upperNext = sb.Len() > 0
+			continue
+		}
+		if upperNext {
+			r = unicode.ToUpper(r)
+			upperNext = false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// simpleTypeName strips the package from a class name
+func simpleTypeName(typeName string) string {
+	return typeName[strings.LastIndex(typeName, ".")+1:]
+}
+
+// qualifyID applies a namespace to an unqualified statement or resultMap ID
+func qualifyID(id, namespace string) string {
+	if namespace == "" || strings.Contains(id, ".") {
+		return id
+	}
+	return namespace + "." + id
+}
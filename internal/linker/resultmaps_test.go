@@ -0,0 +1,217 @@
+package linker
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"spec-recon/internal/analyzer"
+	"spec-recon/internal/model"
+	"spec-recon/internal/xmlparser"
+)
+
+// TestResultMapSchemas verifies resultMap resolution and its use as the
+// response schema of endpoints that return mapper results as maps, and that
+// only Map result types take their keys from the select list
+func TestResultMapSchemas(t *testing.T) {
+	sources := []string{
+		`package com.company.order;
+
+@RestController
+@RequestMapping("/orders")
+public class OrderController {
+    @Autowired
+    private OrderService orderService;
+
+    @GetMapping("/list")
+    public List<Map<String, Object>> list(@RequestParam Map<String, Object> params) {
+        return orderService.findOrders(params);
+    }
+
+    @GetMapping("/stats")
+    public Map<String, Object> stats() {
+        return orderService.stats();
+    }
+}
+`,
+		`package com.company.order;
+
+@Service
+public class OrderService {
+    @Autowired
+    private OrderMapper orderMapper;
+
+    public List<Map<String, Object>> findOrders(Map<String, Object> params) {
+        List<Map<String, Object>> orders = orderMapper.selectOrders(params);
+        return orders;
+    }
+
+    public Map<String, Object> stats() {
+        return orderMapper.selectStats();
+    }
+}
+`,
+		`package com.company.order;
+
+@Mapper
+public interface OrderMapper {
+    List<Map<String, Object>> selectOrders(Map<String, Object> params);
+    Map<String, Object> selectStats();
+}
+`,
+		`package com.company.order;
+
+public class OrderVO extends BaseVO {
+    private Long orderId;
+    private String status;
+    private UserVO customer;
+    private List<OrderItemVO> items;
+}
+`,
+		`package com.company.order;
+
+public class BaseVO {
+    private Date regDate;
+}
+`,
+		`package com.company.order;
+
+public class OrderSearch {
+    private String status;
+    private String keyword;
+}
+`,
+		`package com.company.order;
+
+public class UserRoleMap {
+    private Long userId;
+    private String roleCode;
+}
+`,
+		`package com.company.order;
+
+public class CamelMap extends HashMap<String, Object> {
+}
+`,
+	}
+	orderXML := `<?xml version="1.0" encoding="UTF-8"?>
+<mapper namespace="com.company.order.OrderMapper">
+    <resultMap id="baseMap" type="com.company.order.BaseVO">
+        <result property="regDate" column="REG_DT"/>
+    </resultMap>
+    <resultMap id="orderMap" type="com.company.order.OrderVO" extends="baseMap">
+        <id property="orderId" column="ORDER_ID"/>
+        <result property="status" column="STATUS"/>
+        <association property="customer" resultMap="com.company.user.UserMapper.userMap" columnPrefix="C_"/>
+        <collection property="items" ofType="com.company.order.OrderItemVO">
+            <id property="itemId" column="ITEM_ID"/>
+        </collection>
+    </resultMap>
+    <select id="selectOrders" parameterType="com.company.order.OrderSearch" resultMap="orderMap">
+        SELECT * FROM TB_ORDER
+    </select>
+    <select id="selectStats" resultType="egovMap">
+        SELECT STATUS, COUNT(*) ORDER_CNT FROM TB_ORDER GROUP BY STATUS
+    </select>
+    <select id="selectUserRoles" resultType="com.company.order.UserRoleMap">
+        SELECT USER_ID, ROLE_CODE FROM TB_USER_ROLE
+    </select>
+    <select id="selectCounts" resultType="com.company.order.CamelMap">
+        SELECT STATUS, COUNT(*) ORDER_CNT FROM TB_ORDER GROUP BY STATUS
+    </select>
+    <select id="selectImages" resultType="com.company.image.Bitmap">
+        SELECT IMAGE_ID FROM TB_IMAGE
+    </select>
+</mapper>
+`
+	userXML := `<?xml version="1.0" encoding="UTF-8"?>
+<mapper namespace="com.company.user.UserMapper">
+    <resultMap id="userMap" type="com.company.order.UserVO">
+        <id property="userId" column="USER_ID"/>
+        <result property="userName" column="USER_NAME"/>
+    </resultMap>
+</mapper>
+`
+
+	pool := NewTestPool(t, sources...)
+	for _, content := range []string{orderXML, userXML} {
+		mapper, err := xmlparser.ParseXMLFile(content)
+		if err != nil {
+			t.Fatalf("ParseXMLFile failed: %v", err)
+		}
+		pool.AddMapperXML(mapper)
+	}
+	tree := NewLinker(pool).BuildCallGraph()
+
+	orders := pool.GetSQL("com.company.order.OrderMapper", "selectOrders")
+	if orders.ResultType != "com.company.order.OrderVO" || orders.ParameterType != "com.company.order.OrderSearch" {
+		t.Errorf("Unexpected statement types: %q / %q", orders.ResultType, orders.ParameterType)
+	}
+	if got := describeResultFields(orders.ResultFields); got != "regDate=REG_DT orderId=ORDER_ID status=STATUS "+
+		"customer(userId=C_USER_ID userName=C_USER_NAME) items*(itemId=ITEM_ID)" {
+		t.Errorf("Unexpected resolved resultMap: %s", got)
+	}
+
+	for id, want := range map[string]string{"selectUserRoles": "", "selectCounts": "STATUS=STATUS ORDER_CNT=ORDER_CNT", "selectImages": ""} {
+		if got := describeResultFields(pool.GetSQL("com.company.order.OrderMapper", id).ResultFields); got != want {
+			t.Errorf("%s: map result fields %q, want %q", id, got, want)
+		}
+	}
+
+	endpoints := analyzer.ExtractEndpoints(tree, pool.ClassMap, pool.FieldTypeMap)
+	byPath := make(map[string]model.EndpointDef)
+	for _, ep := range endpoints {
+		byPath[ep.Path] = ep
+	}
+
+	list := byPath["/orders/list"]
+	if got := describeParams(list.SuccessResponse().Fields); got != "1:regDate:Date 1:orderId:Long 1:status:String "+
+		"1:customer:UserVO 2:userId:Object 2:userName:Object 1:items:List<OrderItemVO> 2:itemId:Object" {
+		t.Errorf("Unexpected /orders/list response schema: %s", got)
+	}
+	// Class fields come from a map: compare them in sorted order
+	if len(list.Params) != 1 || describeSortedParams(list.Params[0].Fields) != "1:keyword:String 1:status:String" {
+		t.Errorf("Map parameter should take the statement parameterType schema, got %+v", list.Params)
+	}
+
+	stats := byPath["/orders/stats"]
+	if got := describeParams(stats.SuccessResponse().Fields); got != "1:status:Object 1:orderCnt:Object" {
+		t.Errorf("Unexpected /orders/stats response schema: %s", got)
+	}
+}
+
+// describeResultFields renders result fields as "prop=COLUMN nested(...) list*(...)"
+func describeResultFields(fields []model.ResultField) string {
+	var parts []string
+	for _, field := range fields {
+		part := field.Property
+		if field.Collection {
+			part += "*"
+		}
+		if field.Column != "" {
+			part += "=" + field.Column
+		}
+		if len(field.Fields) > 0 {
+			part += "(" + describeResultFields(field.Fields) + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
+
+// describeParams renders schema fields as "depth:name:type" in order
+func describeParams(params []model.ParamDef) string {
+	var parts []string
+	for _, param := range params {
+		parts = append(parts, strings.Join([]string{strconv.Itoa(param.Depth), param.Name, param.Type}, ":"))
+	}
+	return strings.Join(parts, " ")
+}
+
+// describeSortedParams renders schema fields like describeParams, sorted
+func describeSortedParams(params []model.ParamDef) string {
+	parts := strings.Fields(describeParams(params))
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
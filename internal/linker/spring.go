@@ -0,0 +1,387 @@
+package linker
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"spec-recon/internal/javaparser"
+	"spec-recon/internal/model"
+	"spec-recon/internal/xmlparser"
+)
+
+// ViewControllerClass is the synthetic controller that holds <mvc:view-controller> mappings
+const ViewControllerClass = "mvc.ViewController"
+
+// handlerMethodNames are the entry points of Controller / AbstractController implementations
+var handlerMethodNames = []string{"handleRequest", "handleRequestInternal", "handle"}
+
+// AddSpringContext adds a parsed Spring XML context to the pool
+// Bean definitions refer to classes that may not be loaded yet, so the context
+// is applied by ApplySpringContexts once every file has been added
+func (pool *ComponentPool) AddSpringContext(ctx *xmlparser.SpringContext) error {
+	pool.springContexts = append(pool.springContexts, ctx)
+	return nil
+}
+
+// ApplySpringContexts registers XML-defined beans, turns <property ref> and
+// <constructor-arg ref> into qualified injection points, and maps handler URLs
+// and view controllers onto controller nodes
+// It must run after BuildHierarchy (handlers may be inherited)
+func (pool *ComponentPool) ApplySpringContexts() {
+	definitions := make(map[string]*xmlparser.SpringBean)
+	for _, ctx := range pool.springContexts {
+		for i := range ctx.Beans {
+			bean := &ctx.Beans[i]
+			for _, name := range beanNames(ctx, bean) {
+				definitions[name] = bean
+			}
+		}
+	}
+
+	// 1. Bean names -> classes
+	for _, ctx := range pool.springContexts {
+		for i := range ctx.Beans {
+			bean := &ctx.Beans[i]
+			className := beanClass(bean, definitions)
+			if className == "" || bean.Abstract {
+				continue
+			}
+			names := beanNames(ctx, bean)
+			for _, name := range names {
+				pool.RegisterBean(name, className)
+			}
+			if classNode := pool.ClassMap[className]; classNode != nil && classNode.BeanName == "" && len(names) > 0 {
+				classNode.BeanName = names[0]
+			}
+		}
+	}
+
+	// 2. Property and constructor wiring
+	for _, ctx := range pool.springContexts {
+		for i := range ctx.Beans {
+			bean := &ctx.Beans[i]
+			className := beanClass(bean, definitions)
+			if pool.ClassMap[className] == nil || bean.Abstract {
+				continue
+			}
+			for _, prop := range inheritedProperties(bean, definitions) {
+				pool.wireProperty(className, prop, javaparser.InjectSetter)
+			}
+			pool.wireConstructorArgs(className, bean.ConstructorArgs)
+		}
+	}
+
+	// 3. Handler mappings and view controllers
+	handlerBeans := make(map[string]bool)
+	for _, ctx := range pool.springContexts {
+		for _, mapping := range ctx.URLMappings {
+			if className := pool.mapHandler(ctx, mapping); className != "" {
+				handlerBeans[className] = true
+			}
+		}
+		for _, vc := range ctx.ViewControllers {
+			pool.addViewController(ctx, vc)
+		}
+	}
+
+	// Only the mapped methods of a handler bean serve requests; its setters and
+	// helpers do not, even when the class name made them controller methods
+	for className := range handlerBeans {
+		for _, method := range pool.ClassMap[className].Children {
+			if method.Type == model.NodeTypeController && method.URL == "" {
+				method.Type = model.NodeTypeUtil
+			}
+		}
+	}
+}
+
+// beanNames returns the id, name aliases and <alias> names of a bean
+func beanNames(ctx *xmlparser.SpringContext, bean *xmlparser.SpringBean) []string {
+	var names []string
+	if bean.ID != "" {
+		names = append(names, bean.ID)
+	}
+	names = append(names, bean.Names...)
+	for alias, target := range ctx.Aliases {
+		for _, name := range names {
+			if target == name {
+				names = append(names, alias)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// beanClass returns the class of a bean, following parent definitions
+func beanClass(bean *xmlparser.SpringBean, definitions map[string]*xmlparser.SpringBean) string {
+	seen := make(map[*xmlparser.SpringBean]bool)
+	for current := bean; current != nil && !seen[current]; current = definitions[current.Parent] {
+		seen[current] = true
+		if current.Class != "" {
+			return current.Class
+		}
+	}
+	return ""
+}
+
+// inheritedProperties returns the properties of a bean and of its parent
+// definitions; a property set on the child overrides the parent's
+func inheritedProperties(bean *xmlparser.SpringBean, definitions map[string]*xmlparser.SpringBean) []xmlparser.BeanProperty {
+	var props []xmlparser.BeanProperty
+	seenNames := make(map[string]bool)
+	seen := make(map[*xmlparser.SpringBean]bool)
+	for current := bean; current != nil && !seen[current]; current = definitions[current.Parent] {
+		seen[current] = true
+		for _, prop := range current.Properties {
+			if !seenNames[prop.Name] {
+				seenNames[prop.Name] = true
+				props = append(props, prop)
+			}
+		}
+	}
+	return props
+}
+
+// wireProperty records a <property ref> (or inner bean) as an injection point
+// The setter's assignment decides which field receives the bean
+func (pool *ComponentPool) wireProperty(className string, prop xmlparser.BeanProperty, kind string) {
+	qualifier := prop.Ref
+	if qualifier == "" && prop.Class != "" {
+		// Inner beans are anonymous; register them under their class name
+		qualifier = prop.Class
+		pool.RegisterBean(prop.Class, prop.Class)
+	}
+	if qualifier == "" || prop.Name == "" {
+		return
+	}
+
+	fieldName, paramType := prop.Name, ""
+	if decl := pool.declMap[className]; decl != nil && kind == javaparser.InjectSetter {
+		setter := "set" + strings.ToUpper(prop.Name[:1]) + prop.Name[1:]
+		for i := range decl.Methods {
+			if m := &decl.Methods[i]; m.Name == setter && len(m.Parameters) == 1 {
+				fieldName = m.AssignedField(m.Parameters[0].Name)
+				if fieldName == m.Parameters[0].Name {
+					fieldName = prop.Name
+				}
+				paramType = m.Parameters[0].Type
+				break
+			}
+		}
+	}
+	pool.addInjection(className, fieldName, paramType, qualifier, kind, prop.Line)
+}
+
+// wireConstructorArgs matches <constructor-arg ref> elements to the parameters
+// of the constructor with the same arity (by name, index or position)
+func (pool *ComponentPool) wireConstructorArgs(className string, args []xmlparser.BeanProperty) {
+	if len(args) == 0 {
+		return
+	}
+	decl := pool.declMap[className]
+
+	var ctor *javaparser.Method
+	if decl != nil {
+		for i := range decl.Constructors {
+			if len(decl.Constructors[i].Parameters) == len(args) {
+				ctor = &decl.Constructors[i]
+				break
+			}
+		}
+	}
+
+	for position, arg := range args {
+		if arg.Ref == "" {
+			continue
+		}
+		fieldName, paramType := arg.Name, ""
+		if ctor != nil {
+			index := position
+			if arg.Index >= 0 && arg.Index < len(ctor.Parameters) {
+				index = arg.Index
+			}
+			for i, param := range ctor.Parameters {
+				if arg.Name != "" && param.Name == arg.Name {
+					index = i
+				}
+			}
+			param := ctor.Parameters[index]
+			fieldName, paramType = ctor.AssignedField(param.Name), param.Type
+		}
+		if fieldName != "" {
+			pool.addInjection(className, fieldName, paramType, arg.Ref, javaparser.InjectConstructor, arg.Line)
+		}
+	}
+}
+
+// addInjection adds (or replaces) an injection point on a class node
+// The type comes from the field declaration, then the setter/constructor
+// parameter, then the referenced bean's class
+func (pool *ComponentPool) addInjection(className, fieldName, paramType, qualifier, kind string, line int) {
+	classNode := pool.ClassMap[className]
+	fieldTypes := pool.FieldTypeMap[className]
+	if fieldTypes == nil {
+		fieldTypes = make(map[string]string)
+		pool.FieldTypeMap[className] = fieldTypes
+	}
+
+	fieldType := pool.GetFieldType(className, fieldName)
+	if fieldType == "" {
+		fieldType = extractSimpleTypeName(paramType)
+	}
+	if fieldType == "" {
+		fieldType = extractSimpleTypeName(pool.BeanMap[qualifier])
+	}
+	if _, ok := fieldTypes[fieldName]; !ok && fieldType != "" {
+		fieldTypes[fieldName] = fieldType
+	}
+
+	info := model.InjectionInfo{Name: fieldName, Type: fieldType, Qualifier: qualifier, Kind: kind, Line: line}
+	if existing := classNode.GetInjection(fieldName); existing != nil {
+		*existing = info
+		return
+	}
+	classNode.Injections = append(classNode.Injections, info)
+}
+
+// mapHandler assigns a handler-mapping URL to the controller bean's handler method
+// (adding to its URLs when another mapping already reached it), and returns
+// the bean's class ("" when the bean or its handler is not found)
+// Controller implementations handle every request in handleRequest(Internal);
+// multi-action controllers dispatch on the last path segment (InternalPathMethodNameResolver)
+func (pool *ComponentPool) mapHandler(ctx *xmlparser.SpringContext, mapping xmlparser.URLMapping) string {
+	beanName := mapping.Bean
+	if target, ok := ctx.Aliases[beanName]; ok {
+		beanName = target
+	}
+	className := pool.BeanMap[beanName]
+	classNode := pool.ClassMap[className]
+	if classNode == nil {
+		fmt.Printf("[SPRING XML] Unresolved handler bean '%s' for %s\n", mapping.Bean, mapping.Path)
+		return ""
+	}
+
+	handler := pool.findHandlerMethod(className, mapping.Path)
+	if handler == nil {
+		fmt.Printf("[SPRING XML] No handler method in %s for %s\n", className, mapping.Path)
+		return ""
+	}
+	if owner, _ := splitMethodKey(handler.ID); owner != className {
+		handler = pool.inheritedHandler(classNode, handler)
+	}
+
+	classNode.Type = model.NodeTypeController
+	handler.Type = model.NodeTypeController
+	handler.XMLMapped = true
+	switch {
+	case handler.URL == "":
+		handler.URL = mapping.Path
+	case handler.URL != mapping.Path && !slices.Contains(handler.URLs, mapping.Path):
+		// Several URLs mapped to the same handler: keep each one
+		if len(handler.URLs) == 0 {
+			handler.URLs = []string{handler.URL}
+		}
+		handler.URLs = append(handler.URLs, mapping.Path)
+	}
+	return className
+}
+
+// inheritedHandler returns the handler node of a controller class for a
+// handler method it inherits, created on first use: a base class shared by
+// several controllers keeps its own node, so each controller has its own URLs
+// The node calls the inherited method, which holds the call chain
+func (pool *ComponentPool) inheritedHandler(classNode *model.Node, inherited *model.Node) *model.Node {
+	owner, _ := splitMethodKey(inherited.ID)
+	methodKey := classNode.ID + strings.TrimPrefix(inherited.ID, owner)
+	if handler := pool.MethodMap[methodKey]; handler != nil {
+		return handler
+	}
+	handler := &model.Node{
+		ID:           methodKey,
+		Package:      classNode.Package,
+		File:         inherited.File,
+		Line:         inherited.Line,
+		Method:       inherited.Method,
+		Params:       inherited.Params,
+		ParamTypes:   inherited.ParamTypes,
+		Varargs:      inherited.Varargs,
+		ReturnDetail: inherited.ReturnDetail,
+		Comment:      inherited.Comment,
+		Doc:          inherited.Doc,
+		Children:     []*model.Node{},
+	}
+	handler.AddChild(inherited)
+	pool.MethodMap[methodKey] = handler
+	nameKey := classNode.ID + "." + inherited.Method
+	pool.OverloadMap[nameKey] = append(pool.OverloadMap[nameKey], handler)
+	handler.Parent = classNode
+	classNode.Children = append(classNode.Children, handler)
+	return handler
+}
+
+// findHandlerMethod returns the method that handles path in a controller class
+func (pool *ComponentPool) findHandlerMethod(className, urlPath string) *model.Node {
+	classes := append([]string{className}, pool.superClassChain(className)...)
+	for _, name := range handlerMethodNames {
+		for _, c := range classes {
+			if methods := pool.FindMethodByName(c, name); len(methods) > 0 {
+				return methods[0]
+			}
+		}
+	}
+
+	methodName := path.Base(urlPath)
+	if idx := strings.Index(methodName, "."); idx >= 0 {
+		methodName = methodName[:idx]
+	}
+	for _, c := range classes {
+		if methods := pool.FindMethodByName(c, methodName); len(methods) > 0 {
+			return methods[0]
+		}
+	}
+	return nil
+}
+
+// addViewController adds an <mvc:view-controller> as a GET handler of the
+// synthetic ViewControllerClass controller
+func (pool *ComponentPool) addViewController(ctx *xmlparser.SpringContext, vc xmlparser.ViewController) {
+	classNode := pool.ClassMap[ViewControllerClass]
+	if classNode == nil {
+		classNode = &model.Node{
+			ID:       ViewControllerClass,
+			Type:     model.NodeTypeController,
+			Package:  extractPackage(ViewControllerClass),
+			File:     ctx.File,
+			Line:     vc.Line,
+			Comment:  "<mvc:view-controller> mappings",
+			Children: []*model.Node{},
+		}
+		pool.ClassMap[ViewControllerClass] = classNode
+	}
+
+	methodKey := ViewControllerClass + "." + vc.ViewName + "(" + vc.Path + ")"
+	if pool.MethodMap[methodKey] != nil {
+		return
+	}
+	methodNode := &model.Node{
+		ID:           methodKey,
+		Type:         model.NodeTypeController,
+		Package:      classNode.Package,
+		File:         ctx.File,
+		Line:         vc.Line,
+		Method:       vc.ViewName,
+		ReturnDetail: "ModelAndView",
+		Comment:      "View controller: " + vc.ViewName,
+		URL:          vc.Path,
+		Annotation:   "GET",
+		Children:     []*model.Node{},
+	}
+	pool.MethodMap[methodKey] = methodNode
+	nameKey := ViewControllerClass + "." + vc.ViewName
+	pool.OverloadMap[nameKey] = append(pool.OverloadMap[nameKey], methodNode)
+	methodNode.Parent = classNode
+	classNode.Children = append(classNode.Children, methodNode)
+}
@@ -0,0 +1,179 @@
+package linker
+
+import (
+	"slices"
+	"testing"
+
+	"spec-recon/internal/analyzer"
+
+	"spec-recon/internal/xmlparser"
+)
+
+// TestSpringXMLContext verifies XML bean wiring, handler mappings and view
+// controllers, and that only the mapped methods of handler beans are endpoints
+func TestSpringXMLContext(t *testing.T) {
+	contextXML := `<?xml version="1.0" encoding="UTF-8"?>
+<beans xmlns="http://www.springframework.org/schema/beans"
+       xmlns:mvc="http://www.springframework.org/schema/mvc"
+       xmlns:p="http://www.springframework.org/schema/p">
+
+    <bean id="userDao" class="com.company.legacy.dao.UserDaoImpl"/>
+    <bean id="auditDao" class="com.company.legacy.dao.AuditDaoImpl"/>
+
+    <bean id="userService" class="com.company.legacy.service.UserServiceImpl">
+        <property name="userDao" ref="userDao"/>
+    </bean>
+
+    <bean id="userController" class="com.company.legacy.web.UserController"
+          p:userService-ref="userService"/>
+
+    <bean id="boardController" class="com.company.legacy.web.BoardController">
+        <constructor-arg ref="auditDao"/>
+    </bean>
+
+    <bean id="noticeController" class="com.company.legacy.web.NoticeController"/>
+    <bean id="faqController" class="com.company.legacy.web.FaqController"/>
+
+    <bean class="org.springframework.web.servlet.handler.SimpleUrlHandlerMapping">
+        <property name="mappings">
+            <props>
+                <prop key="/user/list.do">userController</prop>
+            </props>
+        </property>
+    </bean>
+    <bean id="urlMapping" class="org.springframework.web.servlet.handler.SimpleUrlHandlerMapping">
+        <property name="mappings">
+            <value>
+                /board/list.do=boardController
+                /board/view.do=boardController
+                /notice/list.do=noticeController
+                /faq/list.do=faqController
+            </value>
+        </property>
+    </bean>
+
+    <mvc:view-controller path="/" view-name="home"/>
+</beans>
+`
+	sources := []string{
+		`package com.company.legacy.dao;
+public interface UserDao { List findAll(); }
+`,
+		`package com.company.legacy.dao;
+public class UserDaoImpl implements UserDao { public List findAll() { return null; } }
+`,
+		`package com.company.legacy.dao;
+public class AuditDaoImpl implements UserDao { public List findAll() { return null; } }
+`,
+		`package com.company.legacy.service;
+import com.company.legacy.dao.UserDao;
+public class UserServiceImpl {
+    private UserDao dao;
+    public void setUserDao(UserDao userDao) { this.dao = userDao; }
+    public List getUsers() { return dao.findAll(); }
+}
+`,
+		`package com.company.legacy.web;
+import com.company.legacy.service.UserServiceImpl;
+public class UserController extends AbstractController {
+    private UserServiceImpl userService;
+    public void setUserService(UserServiceImpl userService) { this.userService = userService; }
+    protected ModelAndView handleRequestInternal(HttpServletRequest request, HttpServletResponse response) {
+        return new ModelAndView("user/list", "users", userService.getUsers());
+    }
+}
+`,
+		`package com.company.legacy.web;
+import com.company.legacy.dao.UserDao;
+public class BoardController extends MultiActionController {
+    private final UserDao auditDao;
+    public BoardController(UserDao dao) { this.auditDao = dao; }
+    public ModelAndView list(HttpServletRequest request, HttpServletResponse response) { return null; }
+    public ModelAndView view(HttpServletRequest request, HttpServletResponse response) { auditDao.findAll(); return null; }
+}
+`,
+		`package com.company.legacy.web;
+public abstract class BaseListController extends AbstractController {
+    protected ModelAndView handleRequestInternal(HttpServletRequest request, HttpServletResponse response) { return null; }
+}
+`,
+		`package com.company.legacy.web;
+public class NoticeController extends BaseListController {
+}
+`,
+		`package com.company.legacy.web;
+public class FaqController extends BaseListController {
+}
+`,
+	}
+
+	pool := NewTestPool(t, sources...)
+	ctx, err := xmlparser.ParseSpringXMLWithPath("src/main/webapp/WEB-INF/dispatcher-servlet.xml", contextXML)
+	if err != nil {
+		t.Fatalf("ParseSpringXMLWithPath failed: %v", err)
+	}
+	if len(ctx.URLMappings) != 5 || len(ctx.ViewControllers) != 1 {
+		t.Fatalf("Expected 5 URL mappings and 1 view controller, got %+v / %+v", ctx.URLMappings, ctx.ViewControllers)
+	}
+	pool.AddSpringContext(ctx)
+
+	if err := NewLinker(pool).Link(); err != nil {
+		t.Fatalf("Link failed: %v", err)
+	}
+
+	if bean := pool.BeanMap["userService"]; bean != "com.company.legacy.service.UserServiceImpl" {
+		t.Errorf("Unexpected userService bean %q", bean)
+	}
+	if point := pool.GetClass("com.company.legacy.service.UserServiceImpl").GetInjection("dao"); point == nil || point.Qualifier != "userDao" {
+		t.Errorf("<property ref> should inject into the setter's field, got %+v", point)
+	}
+
+	handler := pool.GetMethod("com.company.legacy.web.UserController.handleRequestInternal")
+	if handler.URL != "/user/list.do" || !handler.IsController() {
+		t.Errorf("Unexpected handler mapping: url=%q type=%s", handler.URL, handler.Type)
+	}
+	if len(handler.Children) != 1 || handler.Children[0].ID != "com.company.legacy.service.UserServiceImpl.getUsers()" {
+		t.Fatalf("Expected call through the p: injected service, got %v", handler.Children)
+	}
+	if children := handler.Children[0].Children; len(children) != 1 || children[0].ID != "com.company.legacy.dao.UserDaoImpl.findAll()" {
+		t.Errorf("Expected <property ref> to select UserDaoImpl.findAll, got %v", children)
+	}
+
+	// constructor-arg ref picks AuditDaoImpl although UserDao has two implementations
+	view := pool.GetMethod("com.company.legacy.web.BoardController.view")
+	if view.URL != "/board/view.do" {
+		t.Errorf("Multi-action method should be mapped by path, got %q", view.URL)
+	}
+	if len(view.Children) != 1 || view.Children[0].ID != "com.company.legacy.dao.AuditDaoImpl.findAll()" {
+		t.Errorf("Expected constructor-arg ref to select AuditDaoImpl, got %v", view.Children)
+	}
+
+	if setter := pool.GetMethod("com.company.legacy.web.UserController.setUserService"); setter.IsController() {
+		t.Errorf("Setter of a handler bean should not be a controller method")
+	}
+
+	// A handler inherited from a shared base class is mapped per controller
+	if base := pool.GetMethod("com.company.legacy.web.BaseListController.handleRequestInternal"); base.URL != "" {
+		t.Errorf("Inherited handler should keep no URL, got %q", base.URL)
+	}
+	for className, url := range map[string]string{"NoticeController": "/notice/list.do", "FaqController": "/faq/list.do"} {
+		handler := pool.GetMethod("com.company.legacy.web." + className + ".handleRequestInternal")
+		if handler == nil || handler.URL != url || len(handler.URLs) != 0 || !handler.IsController() {
+			t.Errorf("%s: unexpected handler %+v", className, handler)
+		}
+	}
+
+	home := pool.GetMethod(ViewControllerClass + ".home")
+	if home == nil || home.URL != "/" || home.Source() != "src/main/webapp/WEB-INF/dispatcher-servlet.xml:41" {
+		t.Errorf("Unexpected view controller node %+v", home)
+	}
+
+	var paths []string
+	for _, endpoint := range analyzer.ExtractEndpoints(NewLinker(pool).GetAllNodes(), pool.ClassMap, pool.FieldTypeMap) {
+		paths = append(paths, endpoint.Path)
+	}
+	slices.Sort(paths)
+	if want := []string{"/board/list.do", "/board/view.do", "/faq/list.do", "/notice/list.do", "/user/list.do"}; !slices.Equal(paths, want) {
+		t.Errorf("Endpoints: got %v, want %v", paths, want)
+	}
+}
@@ -0,0 +1,182 @@
+package linker
+
+import (
+	"reflect"
+	"testing"
+
+	"spec-recon/internal/analyzer"
+	"spec-recon/internal/model"
+	"spec-recon/internal/xmlparser"
+)
+
+// TestInjectionFindings verifies ${} and string-built SQL detection, the
+// endpoints reaching each finding and the ranking by request-parameter origin
+func TestInjectionFindings(t *testing.T) {
+	sources := []string{
+		`package com.company.report;
+
+@RestController
+@RequestMapping("/reports")
+public class ReportController {
+    @Autowired
+    private ReportService reportService;
+
+    @GetMapping("/list")
+    public List<Map<String, Object>> list(@RequestParam("sort") String sortColumn) {
+        return reportService.list(sortColumn);
+    }
+
+    @PostMapping("/search")
+    public List<ReportVO> search(@RequestBody ReportSearch search) {
+        return reportService.search(search);
+    }
+
+    @GetMapping("/raw")
+    public List<Map<String, Object>> raw(@RequestParam Map<String, Object> params) {
+        return reportService.raw(params);
+    }
+}
+`,
+		`package com.company.report;
+
+@Service
+public class ReportService {
+    @Autowired
+    private ReportMapper reportMapper;
+    @Autowired
+    private ReportDao reportDao;
+
+    public List<Map<String, Object>> list(String sort) {
+        return reportMapper.selectList(sort);
+    }
+
+    public List<ReportVO> search(ReportSearch search) {
+        return reportDao.search(search, "REPORT");
+    }
+
+    public List<Map<String, Object>> raw(Map<String, Object> params) {
+        return reportMapper.selectRaw(params);
+    }
+}
+`,
+		`package com.company.report;
+
+@Mapper
+public interface ReportMapper {
+    List<Map<String, Object>> selectList(String sort);
+    List<Map<String, Object>> selectRaw(Map<String, Object> params);
+    List<Map<String, Object>> selectArchive(String table);
+}
+`,
+		`package com.company.report;
+
+@Repository
+public class ReportDao {
+    private static final String SELECT_ALL = "SELECT * FROM TB_REPORT";
+
+    @Autowired
+    private JdbcTemplate jdbcTemplate;
+
+    public List<ReportVO> search(ReportSearch search, String type) {
+        StringBuilder sql = new StringBuilder("SELECT * FROM TB_REPORT WHERE TYPE = '" + type + "'");
+        sql.append(" AND TITLE LIKE '%").append(search.getKeyword()).append("%'");
+        jdbcTemplate.query(SELECT_ALL, rowMapper);
+        String countSql = "SELECT COUNT(*) FROM " + TABLE_NAME + " WHERE TYPE = ?";
+        jdbcTemplate.queryForObject(countSql, Integer.class, type);
+        return jdbcTemplate.query(sql.toString(), rowMapper);
+    }
+
+    public void run(Connection conn, String where) throws SQLException {
+        String sql = buildDelete(where);
+        Statement st = conn.createStatement();
+        st.executeUpdate(sql);
+    }
+}
+`,
+		`package com.company.report;
+
+public class ReportSearch {
+    private String keyword;
+}
+`,
+	}
+	mapperXML := `<?xml version="1.0" encoding="UTF-8"?>
+<mapper namespace="com.company.report.ReportMapper">
+    <select id="selectList" resultType="map">
+        SELECT * FROM TB_REPORT ORDER BY ${sort}
+    </select>
+    <select id="selectRaw" parameterType="map" resultType="map">
+        SELECT * FROM ${tableName} WHERE ID = #{id}
+    </select>
+    <select id="selectArchive" resultType="map">
+        SELECT * FROM ${table}
+    </select>
+    <select id="selectSafe" resultType="map">
+        SELECT * FROM TB_REPORT WHERE ID = #{id}
+    </select>
+</mapper>
+`
+
+	pool := NewTestPool(t, sources...)
+	mapper, err := xmlparser.ParseXMLFile(mapperXML)
+	if err != nil {
+		t.Fatalf("ParseXMLFile failed: %v", err)
+	}
+	pool.AddMapperXML(mapper)
+	tree := NewLinker(pool).BuildCallGraph()
+
+	findings := analyzer.BuildInjectionFindings(tree, pool.ClassMap, pool.FieldTypeMap)
+
+	type summary struct {
+		Risk, Kind, Location string
+		Expressions          []string
+		Endpoints, TracedTo  []string
+	}
+	var got []summary
+	for _, f := range findings {
+		got = append(got, summary{f.Risk, f.Kind, f.Location, f.Expressions, f.Endpoints, f.TracedTo})
+	}
+	want := []summary{
+		{
+			Risk: model.RiskHigh, Kind: model.FindingStringBuilt,
+			Location:    "com.company.report.ReportDao.search(ReportSearch,String)",
+			Expressions: []string{"type", "search.getKeyword()"},
+			Endpoints:   []string{"POST /reports/search"},
+			TracedTo:    []string{"keyword (POST /reports/search)"},
+		},
+		{
+			Risk: model.RiskHigh, Kind: model.FindingSubstitution,
+			Location:    "com.company.report.ReportMapper.selectList",
+			Expressions: []string{"${sort}"},
+			Endpoints:   []string{"GET /reports/list"},
+			TracedTo:    []string{"sort (GET /reports/list)"},
+		},
+		{
+			Risk: model.RiskHigh, Kind: model.FindingSubstitution,
+			Location:    "com.company.report.ReportMapper.selectRaw",
+			Expressions: []string{"${tableName}"},
+			Endpoints:   []string{"GET /reports/raw"},
+			TracedTo:    []string{"tableName via request map (GET /reports/raw)"},
+		},
+		{
+			Risk: model.RiskLow, Kind: model.FindingStringBuilt,
+			Location:    "com.company.report.ReportDao.run(Connection,String)",
+			Expressions: []string{"buildDelete(where)"},
+			Endpoints:   []string{},
+			TracedTo:    []string{},
+		},
+		{
+			Risk: model.RiskLow, Kind: model.FindingSubstitution,
+			Location:    "com.company.report.ReportMapper.selectArchive",
+			Expressions: []string{"${table}"},
+			Endpoints:   []string{},
+			TracedTo:    []string{},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildInjectionFindings() =\n%+v\nwant\n%+v", got, want)
+	}
+	if findings[0].Sink != "jdbcTemplate.query" {
+		t.Errorf("Sink = %q, want jdbcTemplate.query", findings[0].Sink)
+	}
+}
@@ -0,0 +1,175 @@
+package linker
+
+import (
+	"fmt"
+	"strings"
+
+	"spec-recon/internal/javaparser"
+	"spec-recon/internal/model"
+)
+
+// statementMethods are the data-access operations whose first argument is a
+// mapped statement ID
+var statementMethods = map[string]bool{
+	// MyBatis SqlSession / SqlSessionTemplate / SqlSessionDaoSupport, eGovFrame EgovAbstractMapper
+	"selectList": true, "selectOne": true, "selectMap": true, "selectCursor": true,
+	"select": true, "insert": true, "update": true, "delete": true,
+	// iBatis 2 SqlMapClient / SqlMapClientTemplate
+	"queryForList": true, "queryForObject": true, "queryForMap": true,
+	"queryForPaginatedList": true, "queryWithRowHandler": true,
+	// eGovFrame EgovAbstractDAO / EgovAbstractMapper helpers
+	"list": true, "listWithPaging": true, "selectByPk": true,
+}
+
+// statementTypes are the receiver types of those operations
+var statementTypes = map[string]bool{
+	"SqlSession": true, "SqlSessionTemplate": true,
+	"SqlMapClient": true, "SqlMapClientTemplate": true, "SqlMapSession": true, "SqlMapExecutor": true,
+}
+
+// statementGetters are the SqlSessionDaoSupport / SqlMapClientDaoSupport
+// accessors returning a session or client
+var statementGetters = map[string]bool{
+	"getSqlSession": true, "getSqlSessionTemplate": true,
+	"getSqlMapClient": true, "getSqlMapClientTemplate": true,
+}
+
+// statementDAOBases are the DAO base classes whose inherited helpers
+// (selectOne, insert, list, ...) execute a statement by ID
+var statementDAOBases = map[string]bool{
+	"EgovAbstractMapper": true, "EgovAbstractDAO": true,
+	"EgovComAbstractMapper": true, "EgovComAbstractDAO": true,
+}
+
+// StatementCall is a call that executes a mapped statement by ID, e.g.,
+// sqlSession.selectList("com.x.UserMapper.selectUser", p),
+// getSqlMapClientTemplate().queryForList("User.selectList", param) or an
+// inherited DAO helper such as selectOne(NAMESPACE + ".select", vo)
+type StatementCall struct {
+	Receiver   string // Variable name, the getter for getSqlSession().insert(...), or "" for an inherited helper
+	Getter     bool   // Receiver is a DAO support getter call
+	MethodName string // selectList, queryForList, insert, ...
+	IDExpr     string // Source text of the statement ID argument
+}
+
+// FindStatementCalls finds calls to data-access operations in a method body
+// The receiver may be a variable, a getter call (getSqlMapClientTemplate()),
+// super, this or absent for helpers inherited from a DAO base class; receiver
+// types are checked by the linker. The statement ID argument is returned as
+// written and evaluated by the linker
+func FindStatementCalls(source string) []StatementCall {
+	var calls []StatementCall
+
+	tokens := javaparser.Tokenize(source)
+	for i := 0; i+2 < len(tokens); i++ {
+		name, paren := tokens[i], tokens[i+1]
+		if name.Kind != javaparser.TokenIdent || !statementMethods[name.Text] || !paren.Is("(") {
+			continue
+		}
+		// "List selectList(" is a declaration, "new Foo.select(" never a DAO call
+		if i > 0 && (tokens[i-1].Kind == javaparser.TokenIdent || tokens[i-1].Is("new") || tokens[i-1].Is(">")) {
+			continue
+		}
+		call := StatementCall{MethodName: name.Text}
+		if i >= 2 && tokens[i-1].Is(".") {
+			switch receiver := tokens[i-2]; {
+			case receiver.Is("super") || receiver.Is("this"):
+			case receiver.Kind == javaparser.TokenIdent:
+				call.Receiver = receiver.Text
+			case receiver.Is(")") && i >= 4 && tokens[i-3].Is("(") && tokens[i-4].Kind == javaparser.TokenIdent:
+				call.Receiver, call.Getter = tokens[i-4].Text, true
+			default:
+				continue
+			}
+		}
+		args := splitArguments(source, tokens, i+1)
+		if len(args) == 0 {
+			continue
+		}
+		call.IDExpr = args[0]
+		calls = append(calls, call)
+	}
+
+	return calls
+}
+
+// isStatementReceiver reports whether a call executes a mapped statement: its
+// receiver is a session or client (a field, parameter or local variable of a
+// statementTypes type, or a DAO support getter), or, without a receiver, the
+// class inherits the helpers of a DAO base
+func (pool *ComponentPool) isStatementReceiver(fullClassName string, call StatementCall, declared map[string]string) bool {
+	switch {
+	case call.Getter:
+		return statementGetters[call.Receiver]
+	case call.Receiver != "":
+		receiverType, ok := declared[call.Receiver]
+		if !ok {
+			receiverType = javaparser.EraseType(pool.GetFieldType(fullClassName, call.Receiver))
+		}
+		return statementTypes[extractSimpleTypeName(receiverType)]
+	}
+	for _, className := range pool.superClassChain(fullClassName) {
+		if statementDAOBases[extractSimpleTypeName(className)] {
+			return true
+		}
+	}
+	return false
+}
+
+// FindStatement returns the SQL node of a statement ID as used in DAO code
+// "User.selectList" matches namespace "User" exactly; an unqualified ID (iBatis
+// without useStatementNamespaces) matches when exactly one statement has it
+func (pool *ComponentPool) FindStatement(statementID string) *model.Node {
+	if node := pool.SQLMap[statementID]; node != nil {
+		return node
+	}
+
+	var match *model.Node
+	for _, node := range pool.SQLMap {
+		if node.Method != statementID {
+			continue
+		}
+		if match != nil {
+			return nil // Ambiguous across namespaces
+		}
+		match = node
+	}
+	return match
+}
+
+// linkStatementCalls links DAO methods to the SQL statements they execute by ID
+// The ID may be a literal or built from namespace constants; calls on other
+// receivers with the same method names are ignored (see isStatementReceiver)
+func (l *Linker) linkStatementCalls() error {
+	for methodKey, methodNode := range l.Pool.MethodMap {
+		body := l.Pool.MethodBodyMap[methodKey]
+		if body == "" {
+			continue
+		}
+		fullClassName, _ := splitMethodKey(methodKey)
+
+		declared := collectDeclaredTypes(body)
+		for name, typeName := range collectDeclaredTypes(methodNode.Params) {
+			declared[name] = typeName
+		}
+		for _, call := range FindStatementCalls(body) {
+			if !l.Pool.isStatementReceiver(fullClassName, call, declared) {
+				continue // e.g. redisTemplate.delete("user"), restTemplate.delete(url)
+			}
+			statementID, ok := l.Pool.EvaluateString(fullClassName, call.IDExpr)
+			if !ok || statementID == "" {
+				continue // Not a statement ID (e.g., a variable or a non-DAO call)
+			}
+			if strings.ContainsAny(statementID, " \t\r\n") {
+				continue // SQL text given to JdbcTemplate (see linkJdbcCalls)
+			}
+			sqlNode := l.Pool.FindStatement(statementID)
+			if sqlNode == nil {
+				fmt.Printf("[LINKER SKIP] Unknown statement '%s' in %s\n", statementID, methodKey)
+				continue
+			}
+			methodNode.AddChild(sqlNode)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+package linker
+
+import (
+	"testing"
+
+	"spec-recon/internal/javaparser"
+	"spec-recon/internal/xmlparser"
+)
+
+// TestSqlMapStatementLinking verifies iBatis 2 sqlMap parsing and DAO statement-ID linking
+func TestSqlMapStatementLinking(t *testing.T) {
+	sqlMapXML := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE sqlMap PUBLIC "-//ibatis.apache.org//DTD SQL Map 2.0//EN" "http://ibatis.apache.org/dtd/sql-map-2.dtd">
+<sqlMap namespace="User">
+    <typeAlias alias="user" type="com.company.legacy.domain.User"/>
+
+    <select id="selectList" parameterClass="map" resultClass="user">
+        SELECT USER_ID, USER_NAME FROM TB_USER
+        <dynamic prepend="WHERE">
+            <isNotEmpty prepend="AND" property="userName">
+                USER_NAME LIKE #userName#
+            </isNotEmpty>
+            <iterate prepend="AND" property="ids" open="USER_ID IN (" close=")" conjunction=",">
+                #ids[]#
+            </iterate>
+        </dynamic>
+    </select>
+
+    <insert id="insertUser" parameterClass="user">
+        <selectKey resultClass="int" keyProperty="userId">SELECT SEQ_USER.NEXTVAL FROM DUAL</selectKey>
+        INSERT INTO TB_USER (USER_ID, USER_NAME) VALUES (#userId#, #userName#)
+    </insert>
+
+    <statement id="purgeUsers">
+        DELETE FROM TB_USER WHERE DEL_YN = 'Y'
+    </statement>
+
+    <procedure id="callStats" parameterClass="map">
+        { call PROC_USER_STATS(#from#, #to#) }
+    </procedure>
+</sqlMap>
+`
+	daoSource := `package com.company.legacy.dao;
+
+public class UserDao extends SqlMapClientDaoSupport {
+    public List selectList(Map param) {
+        return getSqlMapClientTemplate().queryForList("User.selectList", param);
+    }
+
+    public void insertUser(User user) {
+        getSqlMapClientTemplate().insert("User.insertUser", user);
+    }
+
+    public int purge() {
+        return getSqlMapClient().delete("purgeUsers");
+    }
+
+    public void unknown() {
+        getSqlMapClientTemplate().update("User.missing");
+    }
+}
+`
+
+	mapper, err := xmlparser.ParseSqlMapWithPath("src/main/resources/sqlmap/User.xml", sqlMapXML)
+	if err != nil {
+		t.Fatalf("ParseSqlMapWithPath failed: %v", err)
+	}
+	if mapper.Namespace != "User" || mapper.CountStatements() != 4 {
+		t.Fatalf("Expected 4 statements in namespace User, got %q / %d", mapper.Namespace, mapper.CountStatements())
+	}
+
+	sel := mapper.GetSQLByID("selectList")
+	expectedSQL := "SELECT USER_ID, USER_NAME FROM TB_USER WHERE USER_NAME LIKE #userName# AND USER_ID IN ( #ids[]# )"
+	if sel.Content != expectedSQL {
+		t.Errorf("Unexpected dynamic SQL content:\n got: %s\nwant: %s", sel.Content, expectedSQL)
+	}
+	if sel.ResultType != "com.company.legacy.domain.User" || sel.ParameterType != "map" || sel.Line != 6 {
+		t.Errorf("Unexpected selectList attributes: %+v", sel)
+	}
+	if ins := mapper.GetSQLByID("insertUser"); ins.Content != "INSERT INTO TB_USER (USER_ID, USER_NAME) VALUES (#userId#, #userName#)" {
+		t.Errorf("<selectKey> should not be part of the statement, got %q", ins.Content)
+	}
+	if purge := mapper.GetSQLByID("purgeUsers"); purge.Type != "delete" {
+		t.Errorf("<statement> type should be inferred from the SQL, got %q", purge.Type)
+	}
+	if call := mapper.GetSQLByID("callStats"); call.Type != "procedure" {
+		t.Errorf("Expected procedure type, got %q", call.Type)
+	}
+
+	if _, err := xmlparser.ParseSqlMap(`<mapper namespace="x"/>`); err == nil {
+		t.Error("ParseSqlMap should reject a MyBatis mapper")
+	}
+
+	pool := NewComponentPool()
+	cls, err := javaparser.ParseJavaFile(daoSource)
+	if err != nil {
+		t.Fatalf("ParseJavaFile failed: %v", err)
+	}
+	pool.AddJavaClass(cls, daoSource)
+	pool.AddMapperXML(mapper)
+
+	if err := NewLinker(pool).Link(); err != nil {
+		t.Fatalf("Link failed: %v", err)
+	}
+
+	tests := map[string]string{
+		"com.company.legacy.dao.UserDao.selectList": "User.selectList",
+		"com.company.legacy.dao.UserDao.insertUser": "User.insertUser",
+		"com.company.legacy.dao.UserDao.purge":      "User.purgeUsers", // unqualified ID
+	}
+	for method, sqlID := range tests {
+		node := pool.GetMethod(method)
+		if len(node.Children) != 1 || node.Children[0].ID != sqlID {
+			t.Errorf("%s: expected link to %s, got %v", method, sqlID, node.Children)
+		}
+	}
+	if node := pool.GetMethod("com.company.legacy.dao.UserDao.unknown"); len(node.Children) != 0 {
+		t.Errorf("Unknown statement IDs must not be linked, got %v", node.Children)
+	}
+}
+
+// TestSqlMapIncludes verifies that <sql> fragments of iBatis sqlMaps are
+// expanded into statements, within the file and across namespaces
+func TestSqlMapIncludes(t *testing.T) {
+	commonXML := `<?xml version="1.0" encoding="UTF-8"?>
+<sqlMap namespace="Common">
+    <sql id="pagingTail">
+        LIMIT #limit# OFFSET #offset#
+    </sql>
+</sqlMap>
+`
+	userXML := `<?xml version="1.0" encoding="UTF-8"?>
+<sqlMap namespace="User">
+    <sql id="userColumns">USER_ID, USER_NAME</sql>
+    <sql id="userFilter">
+        <dynamic prepend="WHERE">
+            <isNotEmpty prepend="AND" property="userName">USER_NAME LIKE #userName#</isNotEmpty>
+        </dynamic>
+    </sql>
+
+    <select id="selectList" parameterClass="map">
+        SELECT <include refid="userColumns"/> FROM TB_USER
+        <include refid="userFilter"/>
+        <include refid="Common.pagingTail"/>
+    </select>
+
+    <select id="selectBroken">
+        SELECT * FROM TB_USER <include refid="missing"/>
+    </select>
+</sqlMap>
+`
+	var mappers []*xmlparser.MapperXML
+	for _, content := range []string{commonXML, userXML} {
+		mapper, err := xmlparser.ParseSqlMapWithPath("User.xml", content)
+		if err != nil {
+			t.Fatalf("ParseSqlMapWithPath failed: %v", err)
+		}
+		mappers = append(mappers, mapper)
+	}
+	if got := mappers[1].CountStatements(); got != 2 {
+		t.Fatalf("<sql> fragments must not be statements, got %d statements", got)
+	}
+
+	pool := NewComponentPool()
+	if err := NewLinker(pool).LoadMapperXMLs(mappers); err != nil {
+		t.Fatalf("LoadMapperXMLs failed: %v", err)
+	}
+
+	tests := map[string]string{
+		"User.selectList":   "SELECT USER_ID, USER_NAME FROM TB_USER WHERE USER_NAME LIKE #userName# LIMIT #limit# OFFSET #offset#",
+		"User.selectBroken": "SELECT * FROM TB_USER [unresolved include missing]",
+	}
+	for id, want := range tests {
+		if node := pool.SQLMap[id]; node == nil || node.Comment != want {
+			t.Errorf("%s:\n got: %v\nwant: %s", id, node, want)
+		}
+	}
+}
+
+// TestSqlSessionStatementLinking verifies SqlSession / eGovFrame DAO calls whose
+// statement IDs are literals or built from namespace constants, and that calls
+// with the same names on other receivers are not linked
+func TestSqlSessionStatementLinking(t *testing.T) {
+	userMapperXML := `<?xml version="1.0" encoding="UTF-8"?>
+<mapper namespace="com.company.mapper.UserMapper">
+    <select id="selectUser">SELECT * FROM TB_USER WHERE USER_ID = #{userId}</select>
+    <insert id="insertUser">INSERT INTO TB_USER (USER_ID) VALUES (#{userId})</insert>
+</mapper>
+`
+	boardMapperXML := `<?xml version="1.0" encoding="UTF-8"?>
+<mapper namespace="boardDAO">
+    <select id="select">SELECT * FROM TB_BOARD WHERE BOARD_ID = #{boardId}</select>
+    <delete id="user">DELETE FROM TB_BOARD WHERE WRITER_ID = #{userId}</delete>
+</mapper>
+`
+	sources := []string{
+		`package com.company.common;
+public interface SqlIds {
+    String BASE = "com.company.mapper.";
+    String USER_NS = BASE + "UserMapper";
+}
+`,
+		`package com.company.dao;
+
+import static com.company.common.SqlIds.USER_NS;
+
+public class UserDAO extends EgovAbstractMapper {
+    private static final String NAMESPACE = "com.company.mapper.UserMapper.";
+
+    public UserVO selectUser(String userId) {
+        return selectOne(NAMESPACE + "selectUser", userId);
+    }
+
+    public void insertUser(UserVO vo) {
+        insert(USER_NS + ".insertUser", vo);
+    }
+
+    public List selectAll(String queryId) {
+        return selectList(queryId);
+    }
+}
+`,
+		`package com.company.dao;
+
+import com.company.common.SqlIds;
+
+public class BoardDAO {
+    private SqlSessionTemplate sqlSession;
+
+    public BoardVO select(String boardId) {
+        return sqlSession.selectOne("boardDAO.select", boardId);
+    }
+
+    public UserVO writer(String userId) {
+        return sqlSession.selectOne(SqlIds.USER_NS + ".selectUser", userId);
+    }
+}
+`,
+		`package com.company.cache;
+
+public class UserCache {
+    private RedisTemplate<String, Object> redisTemplate;
+    private RestTemplate restTemplate;
+
+    public void evict() {
+        redisTemplate.delete("user");
+        restTemplate.delete("boardDAO.select");
+        Cache cache = cacheManager.getCache("users");
+        cache.select("boardDAO.select");
+        delete("user");
+    }
+}
+`,
+	}
+
+	pool := NewComponentPool()
+	for _, src := range sources {
+		cls, err := javaparser.ParseJavaFile(src)
+		if err != nil {
+			t.Fatalf("ParseJavaFile failed: %v", err)
+		}
+		pool.AddJavaClass(cls, src)
+	}
+	for _, content := range []string{userMapperXML, boardMapperXML} {
+		mapper, err := xmlparser.ParseXMLFile(content)
+		if err != nil {
+			t.Fatalf("ParseXMLFile failed: %v", err)
+		}
+		pool.AddMapperXML(mapper)
+	}
+
+	if err := NewLinker(pool).Link(); err != nil {
+		t.Fatalf("Link failed: %v", err)
+	}
+
+	tests := map[string]string{
+		"com.company.dao.UserDAO.selectUser": "com.company.mapper.UserMapper.selectUser", // class constant
+		"com.company.dao.UserDAO.insertUser": "com.company.mapper.UserMapper.insertUser", // statically imported, derived constant
+		"com.company.dao.BoardDAO.select":    "boardDAO.select",                          // literal on a SqlSession
+		"com.company.dao.BoardDAO.writer":    "com.company.mapper.UserMapper.selectUser", // qualified constant
+	}
+	for method, sqlID := range tests {
+		node := pool.GetMethod(method)
+		if len(node.Children) != 1 || node.Children[0].ID != sqlID {
+			t.Errorf("%s: expected link to %s, got %v", method, sqlID, node.Children)
+		}
+	}
+	if node := pool.GetMethod("com.company.dao.UserDAO.selectAll"); len(node.Children) != 0 {
+		t.Errorf("A statement ID held in a variable cannot be resolved, got %v", node.Children)
+	}
+	if node := pool.GetMethod("com.company.cache.UserCache.evict"); len(node.Children) != 0 {
+		t.Errorf("Calls on other receivers than a SqlSession or DAO base must not be linked, got %v", node.Children)
+	}
+
+	if value, ok := pool.EvaluateString("com.company.dao.UserDAO", `USER_NS + '.' + "x"`); !ok || value != "com.company.mapper.UserMapper.x" {
+		t.Errorf("Unexpected constant evaluation: %q, %v", value, ok)
+	}
+}
@@ -0,0 +1,25 @@
+package linker
+
+import (
+	"testing"
+
+	"spec-recon/internal/javaparser"
+)
+
+// NewTestPool parses Java sources into a component pool, for the tests of the
+// linker and of the packages reading its graph; every top-level and nested
+// type of a source is added, and a source that does not parse fails the test
+func NewTestPool(tb testing.TB, sources ...string) *ComponentPool {
+	tb.Helper()
+	pool := NewComponentPool()
+	for _, source := range sources {
+		types, err := javaparser.ParseJavaTypes("", source)
+		if err != nil {
+			tb.Fatalf("ParseJavaTypes failed: %v", err)
+		}
+		for _, cls := range types {
+			pool.AddJavaClass(cls, source)
+		}
+	}
+	return pool
+}
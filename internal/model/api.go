@@ -1,10 +1,20 @@
 package model
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 // EndpointDef represents a REST API endpoint definition optimized for documentation
 type EndpointDef struct {
-	// HTTP Method (GET, POST, PUT, DELETE, etc.)
+	// HTTP Method (GET, POST, PUT, DELETE, etc.), the methods joined with ", "
+	// when the handler accepts several, or ALL when it accepts any
 	Method string
 
+	// Every HTTP method the handler accepts (empty when it accepts any)
+	Methods []string
+
 	// Full URL path (e.g., "/api/v1/users")
 	Path string
 
@@ -14,6 +24,10 @@ type EndpointDef struct {
 	// Method name in the controller
 	MethodName string
 
+	// Source location of the handler method (repository-relative path and line)
+	File string
+	Line int
+
 	// Summary from JavaDoc or annotation
 	Summary string
 
@@ -23,8 +37,18 @@ type EndpointDef struct {
 	// Request parameters
 	Params []ParamDef
 
-	// Response definition
-	Response ResponseDef
+	// Parameter and header conditions of the mapping ("action=save", "!debug")
+	ParamConditions  []string
+	HeaderConditions []string
+
+	// Media types of the request body and the response (consumes/produces)
+	Consumes []string
+	Produces []string
+
+	// Responses by status code, in ascending order: the handler's own
+	// (@ResponseStatus, ResponseEntity statuses, 200/204 by default) and the
+	// error responses of exceptions thrown along the call chain
+	Responses []ResponseDef
 }
 
 // ParamDef represents a parameter in the API request
@@ -51,6 +75,27 @@ type ParamDef struct {
 	// Nested fields (for complex types like DTOs)
 	// If this parameter is a DTO, Fields contains its properties
 	Fields []ParamDef
+
+	// Inferred marks a low-confidence parameter guessed from SQL placeholders
+	// rather than declared by the handler; Description names the statement
+	Inferred bool
+
+	// Bean Validation constraints (nil or empty when not constrained)
+	// Lengths bound the characters of a String or the items of a collection
+	MinLength *int
+	MaxLength *int
+	Minimum   *float64
+	Maximum   *float64
+	Pattern   string
+	Format    string // "email"
+
+	// DatePattern is the @JsonFormat pattern a date or time is written with
+	// ("yyyy-MM-dd"); it describes serialization, not validation
+	DatePattern string
+
+	// Annotations of the field a nested parameter documents, from which the
+	// constraints of the validation groups in effect are applied
+	Annotations []Annotation
 }
 
 // ResponseDef represents the API response
@@ -69,9 +114,110 @@ type ResponseDef struct {
 	Fields []ParamDef
 }
 
+// ConstraintText describes the validation constraints of a parameter:
+// "minLength: 2, maxLength: 20, pattern: ^[a-z]+$"
+func (p ParamDef) ConstraintText() string {
+	var parts []string
+	if p.MinLength != nil {
+		parts = append(parts, fmt.Sprintf("minLength: %d", *p.MinLength))
+	}
+	if p.MaxLength != nil {
+		parts = append(parts, fmt.Sprintf("maxLength: %d", *p.MaxLength))
+	}
+	if p.Minimum != nil {
+		parts = append(parts, "minimum: "+strconv.FormatFloat(*p.Minimum, 'f', -1, 64))
+	}
+	if p.Maximum != nil {
+		parts = append(parts, "maximum: "+strconv.FormatFloat(*p.Maximum, 'f', -1, 64))
+	}
+	if p.Pattern != "" {
+		parts = append(parts, "pattern: "+p.Pattern)
+	}
+	if p.Format != "" {
+		parts = append(parts, "format: "+p.Format)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Source returns the handler location as "path/to/File.java:42"
+func (e EndpointDef) Source() string {
+	return FormatSource(e.File, e.Line)
+}
+
+// SuccessResponse returns the first 2xx response (the first response when
+// there is none, or an empty one)
+func (e EndpointDef) SuccessResponse() ResponseDef {
+	for _, response := range e.Responses {
+		if !response.IsError() {
+			return response
+		}
+	}
+	if len(e.Responses) > 0 {
+		return e.Responses[0]
+	}
+	return ResponseDef{}
+}
+
+// IsError reports whether the response has a 4xx or 5xx status code
+func (r ResponseDef) IsError() bool {
+	return r.StatusCode >= 400
+}
+
 // NewEndpointDef creates a new endpoint definition
 func NewEndpointDef() *EndpointDef {
 	return &EndpointDef{
 		Params: make([]ParamDef, 0),
 	}
 }
+
+// httpStatusCodes maps Spring HttpStatus constants to their codes
+var httpStatusCodes = map[string]int{
+	"CONTINUE": 100, "OK": 200, "CREATED": 201, "ACCEPTED": 202, "NO_CONTENT": 204, "RESET_CONTENT": 205,
+	"PARTIAL_CONTENT": 206, "MOVED_PERMANENTLY": 301, "FOUND": 302, "SEE_OTHER": 303, "NOT_MODIFIED": 304,
+	"TEMPORARY_REDIRECT": 307, "PERMANENT_REDIRECT": 308, "BAD_REQUEST": 400, "UNAUTHORIZED": 401,
+	"PAYMENT_REQUIRED": 402, "FORBIDDEN": 403, "NOT_FOUND": 404, "METHOD_NOT_ALLOWED": 405,
+	"NOT_ACCEPTABLE": 406, "REQUEST_TIMEOUT": 408, "CONFLICT": 409, "GONE": 410, "PRECONDITION_FAILED": 412,
+	"PAYLOAD_TOO_LARGE": 413, "UNSUPPORTED_MEDIA_TYPE": 415, "UNPROCESSABLE_ENTITY": 422, "LOCKED": 423,
+	"TOO_MANY_REQUESTS": 429, "INTERNAL_SERVER_ERROR": 500, "NOT_IMPLEMENTED": 501, "BAD_GATEWAY": 502,
+	"SERVICE_UNAVAILABLE": 503, "GATEWAY_TIMEOUT": 504,
+}
+
+// HTTPStatusCode reads a status code written as HttpStatus.NOT_FOUND,
+// NOT_FOUND, HttpStatus.valueOf(404), HttpStatus.NOT_FOUND.value(),
+// HttpServletResponse.SC_NOT_FOUND or 404; 0 when it is not recognized or
+// outside the 100-599 range of status codes
+func HTTPStatusCode(expr string) int {
+	expr = strings.TrimSuffix(strings.TrimSpace(expr), ".value()")
+	for _, prefix := range []string{"HttpStatus.", "HttpStatusCode.", "HttpServletResponse.", "SC_"} {
+		expr = strings.TrimPrefix(expr, prefix)
+	}
+	if strings.HasPrefix(expr, "valueOf(") || strings.HasPrefix(expr, "of(") {
+		expr = strings.TrimSuffix(expr[strings.Index(expr, "(")+1:], ")")
+	}
+	if code, err := strconv.Atoi(strings.TrimSpace(expr)); err == nil {
+		if code < 100 || code > 599 {
+			return 0
+		}
+		return code
+	}
+	return httpStatusCodes[expr]
+}
+
+// HTTPStatusText returns the reason phrase of a status code ("Not Found"),
+// or "" when it is not known
+func HTTPStatusText(code int) string {
+	for name, value := range httpStatusCodes {
+		if value != code {
+			continue
+		}
+		if name == "OK" {
+			return name
+		}
+		words := strings.Split(strings.ToLower(name), "_")
+		for i, word := range words {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+		return strings.Join(words, " ")
+	}
+	return ""
+}
@@ -0,0 +1,37 @@
+package model
+
+import "strings"
+
+// TableAccess describes how a SQL statement uses one table
+type TableAccess struct {
+	Table      string   // Upper-cased table name, schema-qualified when written so
+	Operations string   // Subset of "CRUD", in that order
+	Columns    []string // Columns referenced, sorted
+}
+
+// CRUDMatrix is the endpoint × table usage matrix of the analyzed system
+type CRUDMatrix struct {
+	Tables  []string            // All tables used by at least one endpoint, sorted
+	Columns map[string][]string // Table -> columns referenced by those endpoints, sorted
+	Rows    []CRUDRow           // One row per endpoint, sorted by path
+}
+
+// CRUDRow holds the table operations reachable from one endpoint
+type CRUDRow struct {
+	Method     string            // HTTP method
+	Path       string            // Request path
+	Controller string            // Controller simple name
+	Handler    string            // Handler method name
+	Access     map[string]string // Table -> operations ("CR", "U", ...)
+}
+
+// MergeOperations combines two CRUD operation strings, keeping "CRUD" order
+func MergeOperations(a, b string) string {
+	var sb strings.Builder
+	for _, op := range "CRUD" {
+		if strings.ContainsRune(a, op) || strings.ContainsRune(b, op) {
+			sb.WriteRune(op)
+		}
+	}
+	return sb.String()
+}
@@ -19,7 +19,7 @@ const (
 // Node represents a unified code element (Controller, Service, Mapper, SQL, or Util)
 type Node struct {
 	// Identity
-	ID   string   // Unique identifier: "package.ClassName.methodName"
+	ID   string   // Unique identifier: "package.ClassName.methodName(ParamType,...)"
 	Type NodeType // Node type (CONTROLLER, SERVICE, MAPPER, SQL, UTIL)
 
 	// Location
@@ -28,19 +28,248 @@ type Node struct {
 	Line    int    // Line number where method/query is defined
 
 	// Method/Query Details
-	Method       string // Method name or SQL query ID
-	Params       string // Input parameters (formatted string)
-	ReturnDetail string // Return type or result description
-	Body         string // Method body content (for analysis)
-	Comment      string // JavaDoc summary or query description
+	Method       string      // Method name or SQL query ID
+	Params       string      // Input parameters (formatted string)
+	ParamTypes   []string    // Erased parameter types, in order (for overload resolution)
+	Varargs      bool        // Last parameter is declared with ...
+	ReturnDetail string      // Return type or result description
+	Body         string      // Method body content (for analysis)
+	Comment      string      // JavaDoc summary or query description
+	Doc          *DocComment // Parsed JavaDoc (nil when the declaration has none)
 
 	// Linking (for building call chains)
 	Children []*Node // Direct downstream nodes
 	Parent   *Node   // Direct upstream node
 
 	// Metadata
-	Annotation string // Primary annotation (@Controller, @Service, etc.)
-	URL        string // Request mapping URL (for controllers only)
+	Annotation string   // Primary annotation (@Controller, @Service, etc.)
+	URL        string   // Request mapping URL (for controllers only)
+	URLs       []string // Every mapped URL when the mapping declares several (URL is the first)
+
+	// XMLMapped marks a handler whose URLs come from a handler mapping bean of
+	// a Spring XML context rather than from annotations
+	XMLMapped bool
+
+	// Request conditions (handler methods with a mapping annotation only)
+	Mapping *RequestMapping
+
+	// Exceptions (resolved by the linker): those a method declares or throws,
+	// the @ExceptionHandler a method implements, and the @ResponseStatus code
+	// of a method or exception class (0 when not declared)
+	Throws           []string // Full names (simple names for types outside the project)
+	ExceptionHandler *ExceptionHandler
+	ResponseStatus   int
+
+	// Annotations of a method's parameters, by parameter name (resolved by the linker)
+	ParamAnnotations map[string][]Annotation
+
+	// Declared fields (class nodes only)
+	Fields []FieldInfo
+
+	// JSON binding (class nodes only, resolved by the linker): the annotations
+	// of the class declaration and the Jackson naming strategy of its
+	// properties (@JsonNaming, else the project-wide one; "" for field names)
+	TypeAnnotations []Annotation
+	PropertyNaming  string
+
+	// Dependency injection (class nodes only)
+	BeanName   string          // Spring bean name ("" when the class is not a bean)
+	Injections []InjectionInfo // Injected dependencies
+
+	// Type hierarchy (class nodes only, resolved by the linker)
+	Kind       string   // Declaration kind: class, interface, enum, record or annotation
+	SuperClass string   // Full name of the superclass (simple name when not in the project)
+	Interfaces []string // Full names of directly implemented (or extended) interfaces
+
+	// Dynamic SQL (SQL nodes only): <if>/<when> tests, each an optional input
+	Conditions []string
+
+	// Table usage (SQL nodes only), read from the statement text
+	Tables []TableAccess
+
+	// Placeholders (SQL nodes only): #{} and ${} inputs of the statement
+	Placeholders []Placeholder
+
+	// Statement mapping (SQL nodes only)
+	ParameterType string        // parameterType / parameterClass
+	ResultType    string        // resultType / resultClass, or the type of the resultMap
+	ResultMap     string        // Namespace-qualified resultMap ID
+	ResultFields  []ResultField // Properties the result mapping fills (resolved by the linker)
+
+	// JDBC call (SQL nodes annotated "JDBC" only): source text of the SQL
+	// argument, e.g., sql.toString(), read with the body of the calling method
+	SQLArgument string
+
+	// Dispatch (method nodes reached through an interface or abstract method)
+	ImplementationOf    string // Full name of the type whose method this overrides
+	ImplementationCount int    // Number of implementations found for that method
+}
+
+// FieldInfo describes a field declared on a class node
+type FieldInfo struct {
+	Name    string // Field name
+	Type    string // Declared type (simple name, generics preserved)
+	File    string // File path relative to source root
+	Line    int    // Line number of the field name
+	Comment string // JavaDoc summary
+
+	// Annotations of the declaration (resolved by the linker)
+	Annotations []Annotation
+}
+
+// Annotation is an annotation with its element values evaluated: constants
+// and concatenations read as their String value, class literals as the
+// class's simple name, and array elements as several values
+type Annotation struct {
+	Name   string              // Simple name: "NotNull", "Size", "JsonProperty"
+	Values map[string][]string // Element values: {"max": ["20"], "groups": ["Create"]}
+}
+
+// Value returns the first value of an annotation element, or ""
+func (a Annotation) Value(key string) string {
+	if values := a.Values[key]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// FindAnnotation returns the annotation with the given simple name
+func FindAnnotation(annotations []Annotation, name string) (Annotation, bool) {
+	for _, ann := range annotations {
+		if ann.Name == name {
+			return ann, true
+		}
+	}
+	return Annotation{}, false
+}
+
+// ResultField is a property filled from a statement's result set, as declared
+// by a resultMap or, for map results, a column of the select list
+type ResultField struct {
+	Property   string        // Property name or map key
+	Column     string        // Column the value is read from ("" for nested results)
+	Type       string        // Declared javaType/ofType, if any
+	Collection bool          // A <collection>: the property holds a list of Type
+	Fields     []ResultField // Mappings of a nested <association> or <collection>
+}
+
+// RequestMapping holds the conditions of a handler mapping beyond its path,
+// combined with those of the class the way Spring merges them
+type RequestMapping struct {
+	Methods  []string // HTTP methods (empty when any method is accepted)
+	Params   []string // Parameter conditions: "action=save", "!debug"
+	Headers  []string // Header conditions: "X-API-VERSION=2"
+	Consumes []string // Request body media types
+	Produces []string // Response media types
+}
+
+// ExceptionHandler describes an @ExceptionHandler method
+type ExceptionHandler struct {
+	Exceptions   []string // Full names of the handled exception types
+	Advice       bool     // Declared in a @ControllerAdvice class (else it serves its own controller)
+	BasePackages []string // Packages the advice is limited to (empty for every controller)
+}
+
+// Placeholder is a named input of a statement, read from its SQL text
+type Placeholder struct {
+	Name string // Property path: "userId", "search.keyword"
+	Raw  bool   // ${name}: substituted into the SQL text instead of bound
+}
+
+// Syntax renders the placeholder as written in a mapper: #{name} or ${name}
+func (p Placeholder) Syntax() string {
+	if p.Raw {
+		return "${" + p.Name + "}"
+	}
+	return "#{" + p.Name + "}"
+}
+
+// Paths returns the request paths a handler method is mapped to, falling back
+// to "/" + method name when no mapping URL is known
+func (n *Node) Paths() []string {
+	if len(n.URLs) > 0 {
+		return n.URLs
+	}
+	if n.URL != "" {
+		return []string{n.URL}
+	}
+	return []string{"/" + n.Method}
+}
+
+// ImplementationNote describes how an implementation method was reached,
+// e.g. "[Impl] UserService", or "[Impl of 2] UserService" when there are several
+func (n *Node) ImplementationNote() string {
+	if n.ImplementationOf == "" {
+		return ""
+	}
+	simpleName := n.ImplementationOf[strings.LastIndex(n.ImplementationOf, ".")+1:]
+	if n.ImplementationCount > 1 {
+		return fmt.Sprintf("[Impl of %d] %s", n.ImplementationCount, simpleName)
+	}
+	return "[Impl] " + simpleName
+}
+
+// InjectionInfo describes a dependency injected into a bean
+type InjectionInfo struct {
+	Name      string // Field the dependency is stored in
+	Type      string // Declared type (simple name, generics preserved)
+	Qualifier string // Requested bean name (@Qualifier, @Named, @Resource(name)), if any
+	Kind      string // field, constructor or setter
+	Line      int    // Line of the field or parameter
+}
+
+// GetInjection returns the injection point stored in the named field, or nil
+func (n *Node) GetInjection(name string) *InjectionInfo {
+	for i := range n.Injections {
+		if n.Injections[i].Name == name {
+			return &n.Injections[i]
+		}
+	}
+	return nil
+}
+
+// GetField returns the declared field with the given name, or nil
+func (n *Node) GetField(name string) *FieldInfo {
+	for i := range n.Fields {
+		if n.Fields[i].Name == name {
+			return &n.Fields[i]
+		}
+	}
+	return nil
+}
+
+// Source returns the source location as "path/to/File.java:42"
+// Returns an empty string when the node has no file information
+func (n *Node) Source() string {
+	return FormatSource(n.File, n.Line)
+}
+
+// FormatSource renders a file path and line number as "file:line"
+func FormatSource(file string, line int) string {
+	if file == "" {
+		return ""
+	}
+	if line <= 0 {
+		return file
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+// DocComment holds the parts of a JavaDoc comment used for documentation output
+type DocComment struct {
+	Summary     string            // First sentence of the description
+	Description string            // Full main description
+	Params      map[string]string // @param name -> description
+	Return      string            // @return description
+	Throws      map[string]string // @throws type -> description
+}
+
+// ParamDescription returns the @param description for name, if documented
+func (d *DocComment) ParamDescription(name string) string {
+	if d == nil {
+		return ""
+	}
+	return d.Params[name]
 }
 
 // NewNode creates a new Node with the given type
@@ -142,7 +371,11 @@ func (s *Summary) AddControllerStat(stat ControllerStat) {
 
 // IsModelClass checks if the node represents a DTO, VO, or Entity based on its ID/Name
 func IsModelClass(id string) bool {
-	// Extract class name from ID (package.ClassName.methodName)
+	// Extract class name from ID (package.ClassName.methodName(ParamTypes))
+	// The signature is dropped first: varargs types contain dots
+	if idx := strings.Index(id, "("); idx >= 0 {
+		id = id[:idx]
+	}
 	parts := strings.Split(id, ".")
 	if len(parts) == 0 {
 		return false
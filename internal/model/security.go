@@ -0,0 +1,33 @@
+package model
+
+// Injection finding kinds
+const (
+	FindingSubstitution = "MyBatis ${} substitution"
+	FindingStringBuilt  = "String-built SQL"
+)
+
+// Injection risk levels, highest first
+const (
+	RiskHigh   = "High"   // The value traces back to a request parameter
+	RiskMedium = "Medium" // Reachable from an endpoint, origin of the value unknown
+	RiskLow    = "Low"    // Not reachable from any endpoint
+)
+
+// InjectionFinding is a place where SQL text is built from runtime values
+// instead of bind parameters
+type InjectionFinding struct {
+	Kind        string   // FindingSubstitution or FindingStringBuilt
+	Risk        string   // RiskHigh, RiskMedium or RiskLow
+	Location    string   // Statement ID or method ID
+	File        string   // Mapper or Java file, relative to the source root
+	Line        int      // Line of the statement or method
+	Sink        string   // Statement ID, or the JDBC call that runs the SQL ("jdbcTemplate.query")
+	Expressions []string // Substituted values: "${sortColumn}", or the concatenated operands
+	Endpoints   []string // Endpoints reaching the finding: "GET /users/list", sorted
+	TracedTo    []string // Request parameters the values come from: "sortColumn (GET /users/list)"
+}
+
+// Source returns the finding location as "path/to/File.java:42"
+func (f InjectionFinding) Source() string {
+	return FormatSource(f.File, f.Line)
+}
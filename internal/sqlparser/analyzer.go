@@ -0,0 +1,526 @@
+package sqlparser
+
+import (
+	"sort"
+	"strings"
+)
+
+// CRUD operation letters, in the order they are reported
+const (
+	OpCreate = 'C'
+	OpRead   = 'R'
+	OpUpdate = 'U'
+	OpDelete = 'D'
+)
+
+// crudOrder is the display order of operation letters
+const crudOrder = "CRUD"
+
+// TableAccess describes how a statement uses one table
+type TableAccess struct {
+	Table      string   // Upper-cased table name, schema-qualified when written so
+	Operations string   // Subset of "CRUD", in that order
+	Columns    []string // Upper-cased column names referenced, sorted
+}
+
+// sqlKeywords are words never taken as table, alias or column names
+// Pseudo-columns (ROWNUM, SYSDATE, ...) are included so they are not reported as columns
+var sqlKeywords = toSet(
+	"SELECT", "FROM", "WHERE", "AND", "OR", "NOT", "NULL", "IS", "IN", "EXISTS", "BETWEEN", "LIKE", "ESCAPE",
+	"AS", "ON", "JOIN", "LEFT", "RIGHT", "INNER", "OUTER", "FULL", "CROSS", "NATURAL", "LATERAL", "APPLY",
+	"GROUP", "BY", "ORDER", "HAVING", "ASC", "DESC", "NULLS", "FIRST", "LAST",
+	"LIMIT", "OFFSET", "FETCH", "NEXT", "ROWS", "ROW", "ONLY", "TOP",
+	"UNION", "INTERSECT", "MINUS", "EXCEPT", "ALL", "ANY", "SOME", "DISTINCT",
+	"CASE", "WHEN", "THEN", "ELSE", "END", "OVER", "PARTITION", "WITHIN", "INTERVAL",
+	"INSERT", "INTO", "VALUES", "UPDATE", "SET", "DELETE", "MERGE", "USING", "MATCHED", "TRUNCATE", "TABLE",
+	"WITH", "RECURSIVE", "FOR", "OF", "NOWAIT", "WAIT", "SKIP", "LOCKED", "DUPLICATE", "KEY", "IGNORE",
+	"CONNECT", "START", "PRIOR", "NOCYCLE", "SIBLINGS",
+	"TRUE", "FALSE", "UNKNOWN", "DUAL", "ROWNUM", "ROWID", "LEVEL", "SYSDATE", "SYSTIMESTAMP",
+	"CURRENT_DATE", "CURRENT_TIME", "CURRENT_TIMESTAMP", "LOCALTIMESTAMP", "NEXTVAL", "CURRVAL",
+	"BEGIN", "DECLARE", "CALL", "EXEC", "EXECUTE", "RETURNING", "LEADING", "TRAILING", "BOTH",
+)
+
+// datetimeFields are the fields of EXTRACT(field FROM value), which are not columns
+var datetimeFields = toSet(
+	"YEAR", "QUARTER", "MONTH", "WEEK", "DAY", "HOUR", "MINUTE", "SECOND", "EPOCH", "DOW", "DOY",
+	"TIMEZONE_HOUR", "TIMEZONE_MINUTE",
+)
+
+// setOperators start a new query block at the same level
+var setOperators = toSet("UNION", "INTERSECT", "MINUS", "EXCEPT")
+
+// expressionClauses are keywords after which identifiers are column references
+var expressionClauses = toSet("WHERE", "ON", "HAVING", "GROUP", "ORDER", "CONNECT", "START", "SET")
+
+// clause is the part of a query block being read
+type clause int
+
+const (
+	clauseNone   clause = iota // Keywords, VALUES lists, ...
+	clauseSelect               // Select list: identifiers are columns, trailing names are aliases
+	clauseFrom                 // FROM list: "," introduces another table
+	clauseExpr                 // Conditions and SET assignments
+)
+
+// columnRef is a column reference, optionally qualified with a table or alias
+type columnRef struct {
+	qualifier string
+	name      string
+}
+
+// scope is one query block: its tables, aliases and unresolved column references
+type scope struct {
+	parent  *scope
+	aliases map[string]string // Alias or table name -> table ("" for derived tables and CTEs)
+	tables  []string          // Tables read or written directly in this block
+	refs    []columnRef
+	target  string // Table written by the statement (INSERT/UPDATE/DELETE/MERGE)
+	merge   bool   // Block is a MERGE statement
+	multi   bool   // Block is an Oracle INSERT ALL/FIRST: every INTO names a target
+}
+
+func newScope(parent *scope) *scope {
+	return &scope{parent: parent, aliases: make(map[string]string)}
+}
+
+// lookup resolves a qualifier through this block and the enclosing ones
+func (s *scope) lookup(qualifier string) (string, bool) {
+	for sc := s; sc != nil; sc = sc.parent {
+		if table, ok := sc.aliases[qualifier]; ok {
+			return table, true
+		}
+	}
+	return "", false
+}
+
+// usage accumulates the operations and columns of one table
+type usage struct {
+	ops     map[rune]bool
+	columns map[string]bool
+}
+
+// analysis walks the tokens of one SQL text
+type analysis struct {
+	tokens []token
+	pos    int
+	ctes   map[string]bool
+	tables map[string]*usage
+}
+
+// Analyze returns the tables a SQL text reads and writes, with the columns it
+// references, sorted by table name. The text may hold several statements
+// (separated by ';') and still carry MyBatis/iBatis parameters
+// This is a heuristic reader, not a full SQL grammar: tables given by ${...}
+// substitution are skipped, and unqualified columns are only attributed when
+// their query block uses a single table
+func Analyze(sql string) []TableAccess {
+	a := &analysis{
+		tokens: tokenize(sql),
+		ctes:   make(map[string]bool),
+		tables: make(map[string]*usage),
+	}
+	a.parseBlock(nil, false)
+
+	var accesses []TableAccess
+	for table, u := range a.tables {
+		var ops strings.Builder
+		for _, op := range crudOrder {
+			if u.ops[op] {
+				ops.WriteRune(op)
+			}
+		}
+		if ops.Len() == 0 {
+			continue
+		}
+		columns := make([]string, 0, len(u.columns))
+		for column := range u.columns {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		accesses = append(accesses, TableAccess{Table: table, Operations: ops.String(), Columns: columns})
+	}
+	sort.Slice(accesses, func(i, j int) bool { return accesses[i].Table < accesses[j].Table })
+	return accesses
+}
+
+func (a *analysis) peek(offset int) token {
+	if a.pos+offset < len(a.tokens) {
+		return a.tokens[a.pos+offset]
+	}
+	return token{kind: tokEOF}
+}
+
+func (a *analysis) next() token {
+	t := a.peek(0)
+	if a.pos < len(a.tokens) {
+		a.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is the given keyword
+func (a *analysis) accept(word string) bool {
+	if a.peek(0).isWord(word) {
+		a.pos++
+		return true
+	}
+	return false
+}
+
+// startsQuery reports whether the next tokens open a subquery: "(SELECT" or "(WITH"
+func (a *analysis) startsQuery() bool {
+	return a.peek(0).isSymbol("(") && (a.peek(1).isWord("SELECT") || a.peek(1).isWord("WITH"))
+}
+
+// parseBlock reads a query block; nested blocks end at their closing
+// parenthesis, which is consumed, the top level at the end of the text
+func (a *analysis) parseBlock(parent *scope, nested bool) {
+	sc := newScope(parent)
+	cl := clauseNone
+	depth := 0 // Parentheses opened inside this block
+	var prev token
+
+	for a.pos < len(a.tokens) {
+		if a.startsQuery() {
+			a.next()
+			a.parseBlock(sc, true)
+			if cl == clauseFrom {
+				a.parseAlias(sc, "") // Derived table
+			}
+			prev = token{kind: tokSymbol, text: ")"}
+			continue
+		}
+
+		t := a.next()
+		switch {
+		case t.isSymbol("("):
+			depth++
+		case t.isSymbol(")"):
+			if depth == 0 {
+				if nested {
+					a.resolve(sc)
+					return
+				}
+				break
+			}
+			depth--
+		case t.isSymbol(";") && !nested:
+			a.resolve(sc)
+			sc, cl, depth = newScope(parent), clauseNone, 0
+		case t.isSymbol(",") && cl == clauseFrom && depth == 0:
+			a.parseTableRef(sc, OpRead)
+		case t.isWord("FROM") && depth > 0:
+			// A function argument (subqueries are separate blocks): EXTRACT(YEAR FROM col),
+			// TRIM(' ' FROM col), SUBSTRING(col FROM 2)
+			if n := len(sc.refs); n > 0 && sc.refs[n-1].qualifier == "" && datetimeFields[sc.refs[n-1].name] && prev.isWord(sc.refs[n-1].name) {
+				sc.refs = sc.refs[:n-1]
+			}
+		case t.kind == tokIdent && sqlKeywords[strings.ToUpper(t.text)]:
+			cl = a.keyword(sc, strings.ToUpper(t.text), prev, cl)
+			if setOperators[strings.ToUpper(t.text)] && depth == 0 {
+				a.resolve(sc)
+				sc = newScope(parent)
+			}
+		case t.isName() && (cl == clauseSelect || cl == clauseExpr):
+			if a.peek(0).isSymbol("(") {
+				break // Function call
+			}
+			parts := []string{normalize(t)}
+			// Keywords are allowed after the dot: SEQ_USER.NEXTVAL has an unknown qualifier
+			for a.peek(0).isSymbol(".") && (a.peek(1).kind == tokIdent || a.peek(1).kind == tokQuoted || a.peek(1).isSymbol("*")) {
+				a.next()
+				parts = append(parts, normalize(a.next()))
+			}
+			if len(parts) == 1 && cl == clauseSelect && isOperand(prev) {
+				break // "SELECT name alias": the second name is a column alias
+			}
+			ref := columnRef{name: parts[len(parts)-1]}
+			if len(parts) > 1 {
+				ref.qualifier = parts[len(parts)-2]
+			}
+			if ref.name != "*" {
+				sc.refs = append(sc.refs, ref)
+			}
+		}
+		prev = a.peek(-1)
+	}
+	a.resolve(sc)
+}
+
+// keyword handles a keyword and returns the clause that follows it
+func (a *analysis) keyword(sc *scope, kw string, prev token, cl clause) clause {
+	switch kw {
+	case "SELECT":
+		return clauseSelect
+	case "FROM", "JOIN", "USING":
+		if kw == "USING" && !sc.merge {
+			return cl // JOIN ... USING (column)
+		}
+		a.parseTableRef(sc, OpRead)
+		return clauseFrom
+	case "AS":
+		if cl == clauseSelect && a.peek(0).isName() {
+			a.next() // Column alias
+		}
+		return cl
+	case "WITH":
+		a.parseCTEs(sc)
+		return clauseNone
+	case "INSERT":
+		switch {
+		case sc.merge:
+			a.use(sc.target, OpCreate)
+		case a.accept("ALL") || a.accept("FIRST"):
+			sc.multi = true
+			return clauseNone
+		default:
+			a.accept("INTO")
+			sc.target = a.parseInsertTarget(sc)
+		}
+		a.parseColumnList(sc.target)
+		return clauseNone
+	case "INTO":
+		if sc.multi {
+			a.parseColumnList(a.parseInsertTarget(sc))
+		}
+		return clauseNone
+	case "UPDATE":
+		switch {
+		case prev.isWord("FOR"):
+			return clauseNone // SELECT ... FOR UPDATE
+		case prev.isWord("KEY"), sc.merge:
+			// ON DUPLICATE KEY UPDATE / WHEN MATCHED THEN UPDATE
+			a.use(sc.target, OpUpdate)
+		default:
+			sc.target = a.parseTableRef(sc, OpUpdate)
+		}
+		return clauseNone
+	case "DELETE":
+		if sc.merge {
+			a.use(sc.target, OpDelete)
+			return clauseNone
+		}
+		a.accept("FROM")
+		sc.target = a.parseTableRef(sc, OpDelete)
+		return clauseNone
+	case "MERGE":
+		a.accept("INTO")
+		sc.merge = true
+		sc.target = a.parseTableRef(sc, 0)
+		return clauseNone
+	case "TRUNCATE":
+		a.accept("TABLE")
+		a.parseTableRef(sc, OpDelete)
+		return clauseNone
+	case "VALUES", "LIMIT", "OFFSET", "FETCH":
+		return clauseNone
+	}
+	if expressionClauses[kw] {
+		return clauseExpr
+	}
+	return cl
+}
+
+// parseTableRef reads a table reference with its optional alias and records
+// the operation on it (0 records the table without an operation yet)
+// Returns the table name, or "" for derived tables, CTEs and dynamic names
+func (a *analysis) parseTableRef(sc *scope, op rune) string {
+	if a.startsQuery() {
+		a.next()
+		a.parseBlock(sc, true)
+		a.parseAlias(sc, "")
+		return ""
+	}
+	t := a.peek(0)
+	if t.kind == tokParam {
+		a.next() // ${tableName}: resolved at runtime
+		a.parseAlias(sc, "")
+		return ""
+	}
+	if !t.isName() {
+		return ""
+	}
+
+	a.next()
+	parts := []string{normalize(t)}
+	for a.peek(0).isSymbol(".") && a.peek(1).isName() {
+		a.next()
+		parts = append(parts, normalize(a.next()))
+	}
+	if op == OpRead && a.peek(0).isSymbol("(") {
+		return "" // Table function (an INSERT target is followed by its column list)
+	}
+
+	table := strings.Join(parts, ".")
+	name := parts[len(parts)-1]
+	if len(parts) == 1 && a.ctes[table] {
+		sc.aliases[table] = ""
+		a.parseAlias(sc, "")
+		return ""
+	}
+
+	a.use(table, op)
+	sc.tables = append(sc.tables, table)
+	sc.aliases[table] = table
+	sc.aliases[name] = table
+	a.parseAlias(sc, table)
+	return table
+}
+
+// parseInsertTarget reads the table an INSERT writes. It does not count among
+// the block's tables for unqualified columns: its own are read from the column
+// list, and those of INSERT ... SELECT belong to the tables selected from
+func (a *analysis) parseInsertTarget(sc *scope) string {
+	table := a.parseTableRef(sc, OpCreate)
+	if table != "" {
+		sc.tables = sc.tables[:len(sc.tables)-1]
+	}
+	return table
+}
+
+// parseAlias reads an optional "[AS] alias" after a table reference
+func (a *analysis) parseAlias(sc *scope, table string) {
+	hasAs := a.accept("AS")
+	if t := a.peek(0); t.isName() || (hasAs && t.kind == tokIdent) {
+		a.next()
+		sc.aliases[normalize(t)] = table
+	}
+}
+
+// parseCTEs reads "name [(columns)] AS (query), ..." after WITH
+func (a *analysis) parseCTEs(sc *scope) {
+	a.accept("RECURSIVE")
+	for a.peek(0).isName() {
+		name := normalize(a.next())
+		a.ctes[name] = true
+		sc.aliases[name] = ""
+		if a.peek(0).isSymbol("(") {
+			a.skipParens()
+		}
+		a.accept("AS")
+		if !a.startsQuery() {
+			return
+		}
+		a.next()
+		a.parseBlock(sc, true)
+		if !a.peek(0).isSymbol(",") {
+			return
+		}
+		a.next()
+	}
+}
+
+// parseColumnList reads an INSERT column list "(a, b, ...)" into the table
+func (a *analysis) parseColumnList(table string) {
+	if !a.peek(0).isSymbol("(") || a.startsQuery() {
+		return
+	}
+	a.next()
+	for a.pos < len(a.tokens) {
+		t := a.next()
+		if t.isSymbol(")") {
+			return
+		}
+		if t.isName() && !a.peek(0).isSymbol(".") {
+			a.column(table, normalize(t))
+		}
+	}
+}
+
+// skipParens consumes a balanced parenthesized group
+func (a *analysis) skipParens() {
+	depth := 0
+	for a.pos < len(a.tokens) {
+		t := a.next()
+		switch {
+		case t.isSymbol("("):
+			depth++
+		case t.isSymbol(")"):
+			depth--
+			if depth == 0 {
+				return
+			}
+		}
+	}
+}
+
+// resolve attributes the column references of a block to tables
+// Unqualified columns go to the block's only table, or to its INSERT target
+// when it reads none (ON DUPLICATE KEY UPDATE)
+func (a *analysis) resolve(sc *scope) {
+	tables := uniqueStrings(sc.tables)
+	if len(tables) == 0 && sc.target != "" {
+		tables = []string{sc.target}
+	}
+	for _, ref := range sc.refs {
+		if ref.qualifier != "" {
+			if table, ok := sc.lookup(ref.qualifier); ok && table != "" {
+				a.column(table, ref.name)
+			}
+			continue
+		}
+		if len(tables) == 1 {
+			a.column(tables[0], ref.name)
+		}
+	}
+	sc.refs = nil
+}
+
+func (a *analysis) use(table string, op rune) {
+	if table == "" || table == "DUAL" {
+		return
+	}
+	u := a.tables[table]
+	if u == nil {
+		u = &usage{ops: make(map[rune]bool), columns: make(map[string]bool)}
+		a.tables[table] = u
+	}
+	if op != 0 {
+		u.ops[op] = true
+	}
+}
+
+func (a *analysis) column(table, name string) {
+	if u := a.tables[table]; u != nil && name != "" {
+		u.columns[name] = true
+	}
+}
+
+// isOperand reports whether a token ends a select-list expression, so that a
+// name directly after it is an alias
+func isOperand(t token) bool {
+	switch t.kind {
+	case tokQuoted, tokString, tokNumber, tokParam:
+		return true
+	case tokIdent:
+		return t.isName() || t.isWord("END")
+	case tokSymbol:
+		return t.text == ")" || t.text == "*"
+	}
+	return false
+}
+
+// normalize returns the upper-cased name of an identifier token
+func normalize(t token) string {
+	return strings.ToUpper(strings.TrimSpace(t.text))
+}
+
+func uniqueStrings(values []string) []string {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
+func toSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
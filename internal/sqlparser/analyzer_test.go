@@ -0,0 +1,174 @@
+package sqlparser
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestAnalyze verifies table, CRUD and column extraction across statement kinds
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []TableAccess
+	}{
+		{
+			name: "select with joins and aliases",
+			sql: `SELECT u.USER_ID, u.USER_NAME AS name, d.DEPT_NAME deptName
+				FROM TB_USER u LEFT OUTER JOIN TB_DEPT d ON u.DEPT_ID = d.DEPT_ID
+				WHERE u.USE_YN = 'Y' AND d.DEPT_ID = #{deptId} ORDER BY u.USER_NAME`,
+			want: []TableAccess{
+				{Table: "TB_DEPT", Operations: "R", Columns: []string{"DEPT_ID", "DEPT_NAME"}},
+				{Table: "TB_USER", Operations: "R", Columns: []string{"DEPT_ID", "USER_ID", "USER_NAME", "USE_YN"}},
+			},
+		},
+		{
+			name: "comma join with schema and unqualified columns on a single table",
+			sql:  `SELECT COUNT(*) cnt FROM APP.TB_ORDER WHERE STATUS = #status# AND ROWNUM <= 10`,
+			want: []TableAccess{
+				{Table: "APP.TB_ORDER", Operations: "R", Columns: []string{"STATUS"}},
+			},
+		},
+		{
+			name: "insert with column list and sequence",
+			sql:  `INSERT INTO TB_USER (USER_ID, USER_NAME, REG_DT) VALUES (SEQ_USER.NEXTVAL, #{userName}, SYSDATE)`,
+			want: []TableAccess{
+				{Table: "TB_USER", Operations: "C", Columns: []string{"REG_DT", "USER_ID", "USER_NAME"}},
+			},
+		},
+		{
+			name: "insert from select",
+			sql:  `INSERT INTO TB_USER_HIST (USER_ID) SELECT h.USER_ID FROM TB_USER h WHERE h.DEL_YN = 'Y'`,
+			want: []TableAccess{
+				{Table: "TB_USER", Operations: "R", Columns: []string{"DEL_YN", "USER_ID"}},
+				{Table: "TB_USER_HIST", Operations: "C", Columns: []string{"USER_ID"}},
+			},
+		},
+		{
+			name: "update with subquery",
+			sql: `UPDATE TB_USER SET USER_NAME = #{name}, MOD_DT = SYSDATE
+				WHERE DEPT_ID IN (SELECT DEPT_ID FROM TB_DEPT WHERE CLOSED_YN = 'Y')`,
+			want: []TableAccess{
+				{Table: "TB_DEPT", Operations: "R", Columns: []string{"CLOSED_YN", "DEPT_ID"}},
+				{Table: "TB_USER", Operations: "U", Columns: []string{"DEPT_ID", "MOD_DT", "USER_NAME"}},
+			},
+		},
+		{
+			name: "delete and truncate in one block",
+			sql:  `DELETE FROM TB_LOG WHERE LOG_DT < #{before}; TRUNCATE TABLE TB_TEMP`,
+			want: []TableAccess{
+				{Table: "TB_LOG", Operations: "D", Columns: []string{"LOG_DT"}},
+				{Table: "TB_TEMP", Operations: "D", Columns: []string{}},
+			},
+		},
+		{
+			name: "merge",
+			sql: `MERGE INTO TB_STOCK s USING (SELECT ITEM_ID, QTY FROM TB_ORDER_ITEM) o ON (s.ITEM_ID = o.ITEM_ID)
+				WHEN MATCHED THEN UPDATE SET s.QTY = s.QTY - o.QTY
+				WHEN NOT MATCHED THEN INSERT (ITEM_ID, QTY) VALUES (o.ITEM_ID, o.QTY)`,
+			want: []TableAccess{
+				{Table: "TB_ORDER_ITEM", Operations: "R", Columns: []string{"ITEM_ID", "QTY"}},
+				{Table: "TB_STOCK", Operations: "CU", Columns: []string{"ITEM_ID", "QTY"}},
+			},
+		},
+		{
+			name: "CTE, derived table, union and dynamic table",
+			sql: `WITH recent AS (SELECT ORDER_ID FROM TB_ORDER WHERE ORDER_DT > SYSDATE - 7)
+				SELECT r.ORDER_ID FROM recent r
+				UNION ALL
+				SELECT x.ORDER_ID FROM (SELECT ORDER_ID FROM TB_ORDER_ARCHIVE) x, ${tableName} t`,
+			want: []TableAccess{
+				{Table: "TB_ORDER", Operations: "R", Columns: []string{"ORDER_DT", "ORDER_ID"}},
+				{Table: "TB_ORDER_ARCHIVE", Operations: "R", Columns: []string{"ORDER_ID"}},
+			},
+		},
+		{
+			name: "insert from select with unqualified columns",
+			sql:  `INSERT INTO TB_DST (ID, MSG) SELECT ID, MSG FROM TB_SRC WHERE SENT_YN = 'N'`,
+			want: []TableAccess{
+				{Table: "TB_DST", Operations: "C", Columns: []string{"ID", "MSG"}},
+				{Table: "TB_SRC", Operations: "R", Columns: []string{"ID", "MSG", "SENT_YN"}},
+			},
+		},
+		{
+			name: "oracle multi-table insert",
+			sql: `INSERT ALL
+				INTO TB_A (ID, NAME) VALUES (#{id}, #{name})
+				INTO TB_B (ID) VALUES (#{id})
+				SELECT * FROM DUAL`,
+			want: []TableAccess{
+				{Table: "TB_A", Operations: "C", Columns: []string{"ID", "NAME"}},
+				{Table: "TB_B", Operations: "C", Columns: []string{"ID"}},
+			},
+		},
+		{
+			name: "FROM inside function arguments",
+			sql:  `SELECT EXTRACT(YEAR FROM REG_DT), TRIM(LEADING '0' FROM CODE) FROM TB_Y`,
+			want: []TableAccess{
+				{Table: "TB_Y", Operations: "R", Columns: []string{"CODE", "REG_DT"}},
+			},
+		},
+		{
+			name: "select for update",
+			sql:  `SELECT BALANCE FROM TB_ACCOUNT WHERE ACCOUNT_NO = ? FOR UPDATE`,
+			want: []TableAccess{
+				{Table: "TB_ACCOUNT", Operations: "R", Columns: []string{"ACCOUNT_NO", "BALANCE"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Analyze(tt.sql)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Analyze() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSelectColumns verifies result column names, including paging wrappers
+func TestSelectColumns(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want []string
+	}{
+		{
+			sql:  `SELECT u.USER_ID, u.USER_NAME AS userName, COUNT(*) cnt, NVL(u.EMAIL, '-') , d.DEPT_NAME "deptName" FROM TB_USER u`,
+			want: []string{"USER_ID", "userName", "cnt", "deptName"},
+		},
+		{
+			sql: `SELECT * FROM (SELECT ROWNUM RN, A.* FROM (
+					SELECT DISTINCT ORDER_ID, STATUS FROM TB_ORDER ORDER BY ORDER_ID
+				) A) WHERE RN BETWEEN #{start} AND #{end}`,
+			want: []string{"RN", "ORDER_ID", "STATUS"},
+		},
+		{
+			sql:  `INSERT INTO TB_USER (USER_ID) VALUES (#{userId})`,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := SelectColumns(tt.sql); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SelectColumns(%q) = %v, want %v", tt.sql, got, tt.want)
+		}
+	}
+}
+
+// TestParameters verifies placeholder names across MyBatis and iBatis syntax
+func TestParameters(t *testing.T) {
+	sql := `SELECT * FROM TB_USER WHERE USER_ID = #{userId, jdbcType=VARCHAR} AND NAME LIKE '%' || #{search.keyword} || '%'
+		AND STATUS = #status:VARCHAR# AND ID = ? AND REG_DT > :since AND USER_ID = #{userId}
+		ORDER BY ${sortColumn} $sortOrder$ -- #{commented}`
+	want := []Parameter{
+		{Name: "userId"},
+		{Name: "search.keyword"},
+		{Name: "status"},
+		{Name: "sortColumn", Raw: true},
+		{Name: "sortOrder", Raw: true},
+	}
+	if got := Parameters(sql); !reflect.DeepEqual(got, want) {
+		t.Errorf("Parameters() = %+v, want %+v", got, want)
+	}
+}
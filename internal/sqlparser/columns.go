@@ -0,0 +1,102 @@
+package sqlparser
+
+// SelectColumns returns the result column names of a query, in select-list
+// order: the alias of each item, or the name of a plain column reference
+// Expressions without an alias are skipped; "*" and "alias.*" are replaced by
+// the columns of the derived table in the FROM clause, so paging wrappers
+// (SELECT * FROM (SELECT ROWNUM RN, A.* FROM (...) A)) report the inner columns
+func SelectColumns(sql string) []string {
+	tokens := tokenize(sql)
+	depth := 0
+	for i, t := range tokens {
+		switch {
+		case t.isSymbol("("):
+			depth++
+		case t.isSymbol(")"):
+			depth--
+		case depth == 0 && t.isWord("SELECT"):
+			return uniqueStrings(selectColumnsAt(tokens, i+1))
+		}
+	}
+	return nil
+}
+
+// selectColumnsAt reads the select list that starts at tokens[start]
+func selectColumnsAt(tokens []token, start int) []string {
+	var columns []string
+	var item []token
+	star := false
+
+	flush := func() {
+		name, isStar := itemName(item)
+		if name != "" {
+			columns = append(columns, name)
+		}
+		star = star || isStar
+		item = item[:0]
+	}
+
+	depth := 0
+	i := start
+list:
+	for ; i < len(tokens); i++ {
+		t := tokens[i]
+		if depth == 0 && (t.isWord("FROM") || t.isSymbol(";") || (t.kind == tokIdent && setOperators[normalize(t)])) {
+			break
+		}
+		switch {
+		case t.isSymbol("("):
+			depth++
+		case t.isSymbol(")"):
+			if depth == 0 {
+				break list // End of the enclosing subquery
+			}
+			depth--
+		case depth == 0 && t.isSymbol(","):
+			flush()
+			continue
+		case len(item) == 0 && (t.isWord("DISTINCT") || t.isWord("ALL")):
+			continue
+		}
+		item = append(item, t)
+	}
+	flush()
+
+	if star && i < len(tokens) && tokens[i].isWord("FROM") {
+		// The columns of the first derived table
+		for j := i + 1; j+1 < len(tokens); j++ {
+			if tokens[j].isSymbol("(") && tokens[j+1].isWord("SELECT") {
+				columns = append(columns, selectColumnsAt(tokens, j+2)...)
+				break
+			}
+			if !tokens[j].isSymbol("(") {
+				break
+			}
+		}
+	}
+	return columns
+}
+
+// itemName returns the result name of a select-list item, or reports a star
+func itemName(item []token) (string, bool) {
+	n := len(item)
+	if n == 0 {
+		return "", false
+	}
+	last := item[n-1]
+	switch {
+	case last.isSymbol("*"):
+		return "", true
+	case !last.isName():
+		return "", false
+	case n == 1:
+		return last.text, false
+	case item[n-2].isWord("AS"):
+		return last.text, false
+	case item[n-2].isSymbol(".") && (n == 3 || n == 5 && item[n-4].isSymbol(".")):
+		return last.text, false // alias.COLUMN or schema.table.COLUMN
+	case item[n-2].kind != tokSymbol || item[n-2].isSymbol(")"):
+		return last.text, false // Implicit alias: "expr name", "ROWNUM RN"
+	}
+	return "", false
+}
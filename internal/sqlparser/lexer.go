@@ -0,0 +1,159 @@
+package sqlparser
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenKind classifies a SQL token
+type tokenKind int
+
+const (
+	tokEOF    tokenKind = iota
+	tokIdent            // Keywords and unquoted identifiers
+	tokQuoted           // "quoted" or `quoted` identifiers
+	tokString           // 'literal'
+	tokNumber           // 42, 3.14
+	tokParam            // #{id}, ${table}, #id#, $id$, ?, :id
+	tokSymbol           // Punctuation and operators, one character each
+)
+
+// token is a lexical unit of SQL text
+type token struct {
+	kind tokenKind
+	text string // Identifier text without quotes; parameters and literals as written
+}
+
+// isWord reports whether the token is the given keyword (case-insensitive)
+func (t token) isWord(word string) bool {
+	return t.kind == tokIdent && strings.EqualFold(t.text, word)
+}
+
+// isSymbol reports whether the token is the given punctuation character
+func (t token) isSymbol(symbol string) bool {
+	return t.kind == tokSymbol && t.text == symbol
+}
+
+// isName reports whether the token can name a table, alias or column
+func (t token) isName() bool {
+	return (t.kind == tokIdent && !sqlKeywords[strings.ToUpper(t.text)]) || t.kind == tokQuoted
+}
+
+// tokenize splits SQL text into tokens, skipping whitespace and comments
+func tokenize(sql string) []token {
+	var tokens []token
+	i := 0
+	for i < len(sql) {
+		c := sql[i]
+		next := byte(0)
+		if i+1 < len(sql) {
+			next = sql[i+1]
+		}
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '-' && next == '-':
+			i = skipUntil(sql, i+2, "\n")
+		case c == '/' && next == '*':
+			i = skipUntil(sql, i+2, "*/")
+		case c == '\'':
+			end := i + 1
+			for end < len(sql) {
+				if sql[end] == '\'' {
+					if end+1 < len(sql) && sql[end+1] == '\'' {
+						end += 2 // Escaped quote
+						continue
+					}
+					break
+				}
+				end++
+			}
+			end = min(end+1, len(sql))
+			tokens = append(tokens, token{kind: tokString, text: sql[i:end]})
+			i = end
+		case c == '"' || c == '`':
+			end := strings.IndexByte(sql[i+1:], c)
+			if end < 0 {
+				end = len(sql) - i - 1
+			}
+			tokens = append(tokens, token{kind: tokQuoted, text: sql[i+1 : i+1+end]})
+			i += end + 2
+		case (c == '#' || c == '$') && next == '{':
+			end := i + skipUntil(sql[i:], 2, "}")
+			tokens = append(tokens, token{kind: tokParam, text: sql[i:min(end, len(sql))]})
+			i = end
+		case c == '#' || c == '$':
+			// iBatis inline parameters: #name# and $name$
+			end := strings.IndexByte(sql[i+1:], c)
+			if end < 0 || strings.ContainsAny(sql[i+1:i+1+end], " \t\n") {
+				tokens = append(tokens, token{kind: tokSymbol, text: string(c)})
+				i++
+				continue
+			}
+			tokens = append(tokens, token{kind: tokParam, text: sql[i : i+end+2]})
+			i += end + 2
+		case c == '?':
+			tokens = append(tokens, token{kind: tokParam, text: "?"})
+			i++
+		case c == ':' && isIdentStart(next):
+			end := i + 1
+			for end < len(sql) && isIdentPart(sql[end:]) {
+				_, size := utf8.DecodeRuneInString(sql[end:])
+				end += size
+			}
+			tokens = append(tokens, token{kind: tokParam, text: sql[i:end]})
+			i = end
+		case c >= '0' && c <= '9':
+			end := i
+			for end < len(sql) && (sql[end] >= '0' && sql[end] <= '9' || sql[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: sql[i:end]})
+			i = end
+		case isIdentStart(c) || c >= utf8.RuneSelf:
+			end := i
+			for end < len(sql) && isIdentPart(sql[end:]) {
+				_, size := utf8.DecodeRuneInString(sql[end:])
+				end += size
+			}
+			if end == i {
+				// A non-letter multi-byte character
+				_, size := utf8.DecodeRuneInString(sql[i:])
+				tokens = append(tokens, token{kind: tokSymbol, text: sql[i : i+size]})
+				i += size
+				continue
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: sql[i:end]})
+			i = end
+		default:
+			tokens = append(tokens, token{kind: tokSymbol, text: string(c)})
+			i++
+		}
+	}
+	return tokens
+}
+
+// skipUntil returns the index after the next occurrence of terminator at or after from
+func skipUntil(s string, from int, terminator string) int {
+	if from > len(s) {
+		return len(s)
+	}
+	idx := strings.Index(s[from:], terminator)
+	if idx < 0 {
+		return len(s)
+	}
+	return from + idx + len(terminator)
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isIdentPart reports whether s starts with a character allowed inside an
+// identifier (letters of any script, digits, '_' and Oracle's '$')
+func isIdentPart(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,48 @@
+package sqlparser
+
+import "strings"
+
+// Parameter is a named placeholder of a mapped statement
+type Parameter struct {
+	Name string // Property path: "userId", "search.keyword", "ids[]"
+	Raw  bool   // ${name} or $name$: substituted into the SQL text, not bound
+}
+
+// Parameters returns the #{}/${} (and iBatis #name#/$name$) placeholders of a
+// statement, in order of first use and without duplicates
+// Placeholder options are dropped: #{userId,jdbcType=VARCHAR} and
+// #userId:VARCHAR# both name "userId". JDBC ? and :name markers are not named
+// statement inputs and are skipped
+func Parameters(sql string) []Parameter {
+	var params []Parameter
+	seen := make(map[Parameter]bool)
+	for _, t := range tokenize(sql) {
+		if t.kind != tokParam || (t.text[0] != '#' && t.text[0] != '$') {
+			continue
+		}
+		param := Parameter{Name: placeholderName(t.text), Raw: t.text[0] == '$'}
+		if param.Name == "" || seen[param] {
+			continue
+		}
+		seen[param] = true
+		params = append(params, param)
+	}
+	return params
+}
+
+// placeholderName returns the property path of a placeholder token
+func placeholderName(text string) string {
+	var name string
+	if strings.HasPrefix(text[1:], "{") {
+		name = strings.TrimSuffix(text[2:], "}")
+		if idx := strings.IndexByte(name, ','); idx >= 0 {
+			name = name[:idx]
+		}
+	} else {
+		name = strings.TrimSuffix(text[1:], text[:1])
+		if idx := strings.IndexByte(name, ':'); idx >= 0 {
+			name = name[:idx]
+		}
+	}
+	return strings.TrimSpace(name)
+}
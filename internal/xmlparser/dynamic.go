@@ -0,0 +1,268 @@
+package xmlparser
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// SQLNode is a node of a statement's dynamic SQL tree
+// Text nodes have an empty Tag; element nodes (<if>, <where>, <foreach>, ...)
+// keep their attributes and children
+type SQLNode struct {
+	Tag      string            // "" for text, otherwise the element name (if, where, choose, when, ...)
+	Text     string            // Text content (text nodes only)
+	Attrs    map[string]string // Element attributes (test, collection, item, prefix, refid, ...)
+	Children []*SQLNode        // Child nodes in document order
+	Line     int               // Line of the element (or of the text)
+}
+
+// conditionalTags are the MyBatis elements whose content is only included when a test holds
+var conditionalTags = map[string]bool{"if": true, "when": true}
+
+// Render returns the complete statement with its dynamic parts marked:
+// conditional branches as "[if test] ... [/if]", <choose> alternatives as
+// "[when test] ... [/when]" and "[otherwise] ... [/otherwise]", loops as
+// "[foreach item in collection] ... [/foreach]". <where>, <set> and <trim>
+// are expanded into their keyword and overrides the way MyBatis applies them
+func (n *SQLNode) Render() string {
+	if n == nil {
+		return ""
+	}
+	var sb strings.Builder
+	n.render(&sb, false)
+	return cleanSQLContent(sb.String())
+}
+
+// PlainSQL returns the statement as SQL text with every conditional branch and
+// loop body included once and no markers, for reading the tables and columns
+// it can touch. The result is not a statement MyBatis would ever execute
+func (n *SQLNode) PlainSQL() string {
+	if n == nil {
+		return ""
+	}
+	var sb strings.Builder
+	n.render(&sb, true)
+	return cleanSQLContent(sb.String())
+}
+
+func (n *SQLNode) render(sb *strings.Builder, plain bool) {
+	if n.Tag == "" {
+		sb.WriteString(n.Text)
+		return
+	}
+
+	children := func() string {
+		var inner strings.Builder
+		for _, child := range n.Children {
+			child.render(&inner, plain)
+		}
+		return cleanSQLContent(inner.String())
+	}
+	write := func(parts ...string) {
+		for _, part := range parts {
+			if part != "" {
+				sb.WriteString(" " + part)
+			}
+		}
+		sb.WriteString(" ")
+	}
+
+	if plain {
+		switch n.Tag {
+		case "if", "when", "otherwise":
+			write(children())
+			return
+		case "foreach":
+			write(n.Attrs["open"], children(), n.Attrs["close"])
+			return
+		case "bind", "include":
+			return
+		}
+	}
+
+	switch n.Tag {
+	case "if", "when":
+		write("["+n.Tag+" "+n.Attrs["test"]+"]", children(), "[/"+n.Tag+"]")
+	case "otherwise":
+		write("[otherwise]", children(), "[/otherwise]")
+	case "choose":
+		write(children())
+	case "where":
+		write("WHERE", trimOverrides(children(), "AND |OR ", ""))
+	case "set":
+		write("SET", trimOverrides(children(), "", ","))
+	case "trim":
+		body := trimOverrides(children(), n.Attrs["prefixOverrides"], n.Attrs["suffixOverrides"])
+		write(n.Attrs["prefix"], body, n.Attrs["suffix"])
+	case "foreach":
+		loop := "[foreach " + n.Attrs["collection"] + "]"
+		if item := n.Attrs["item"]; item != "" {
+			loop = "[foreach " + item + " in " + n.Attrs["collection"] + "]"
+		}
+		write(loop, n.Attrs["open"], children(), n.Attrs["close"], "[/foreach]")
+	case "bind":
+		write("[bind " + n.Attrs["name"] + " = " + n.Attrs["value"] + "]")
+	case "include":
+		// Resolved includes are replaced by the fragment (see expandIncludes)
+		write("[unresolved include " + n.Attrs["refid"] + "]")
+	case "selectKey":
+		// Executed as a separate query before or after the statement
+	default:
+		write(children())
+	}
+}
+
+// trimOverrides removes one leading prefix and one trailing suffix override
+// ('|'-separated, case-insensitive) from plain SQL text. Overrides inside a
+// marked branch are kept, since whether they apply depends on the condition
+func trimOverrides(body, prefixes, suffixes string) string {
+	for _, prefix := range strings.Split(prefixes, "|") {
+		p := strings.TrimSpace(prefix)
+		if p == "" || len(body) < len(p) || !strings.EqualFold(body[:len(p)], p) {
+			continue
+		}
+		// "AND" must not strip the start of "ANDROID_ID"
+		if rest := body[len(p):]; rest == "" || !isWordChar(rest[0]) || !isWordChar(p[len(p)-1]) {
+			body = strings.TrimSpace(rest)
+			break
+		}
+	}
+	for _, suffix := range strings.Split(suffixes, "|") {
+		s := strings.TrimSpace(suffix)
+		if s != "" && len(body) >= len(s) && strings.EqualFold(body[len(body)-len(s):], s) {
+			body = strings.TrimSpace(body[:len(body)-len(s)])
+			break
+		}
+	}
+	return body
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// Conditions returns the test expression of every <if> and <when> in the tree,
+// in document order and without duplicates
+func (n *SQLNode) Conditions() []string {
+	var conditions []string
+	seen := make(map[string]bool)
+	n.Walk(func(node *SQLNode) {
+		if test := strings.TrimSpace(node.Attrs["test"]); conditionalTags[node.Tag] && test != "" && !seen[test] {
+			seen[test] = true
+			conditions = append(conditions, test)
+		}
+	})
+	return conditions
+}
+
+// LocalNames returns the names a statement defines for itself: <foreach> item
+// and index variables and <bind> names. Placeholders rooted at them are not
+// inputs of the statement
+func (n *SQLNode) LocalNames() map[string]bool {
+	names := make(map[string]bool)
+	n.Walk(func(node *SQLNode) {
+		switch node.Tag {
+		case "foreach":
+			for _, attr := range []string{"item", "index"} {
+				if name := strings.TrimSpace(node.Attrs[attr]); name != "" {
+					names[name] = true
+				}
+			}
+		case "bind":
+			if name := strings.TrimSpace(node.Attrs["name"]); name != "" {
+				names[name] = true
+			}
+		}
+	})
+	return names
+}
+
+// Walk calls fn for the node and each of its descendants, depth-first
+func (n *SQLNode) Walk(fn func(*SQLNode)) {
+	if n == nil {
+		return
+	}
+	fn(n)
+	for _, child := range n.Children {
+		child.Walk(fn)
+	}
+}
+
+// statementTrees parses the dynamic SQL tree of every statement, of every
+// <sql> fragment and of every <resultMap>, keyed by ID. Each root node is the
+// element itself and carries its start line
+// encoding/xml does not expose positions through Unmarshal, so the document is
+// scanned a second time with a Decoder, using the offset before each token
+func statementTrees(content string) (statements, fragments, resultMaps map[string]*SQLNode) {
+	statements = make(map[string]*SQLNode)
+	fragments = make(map[string]*SQLNode)
+	resultMaps = make(map[string]*SQLNode)
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	decoder.Strict = false
+
+	lineOf := lineCounter(content)
+	var stack []*SQLNode
+	for {
+		offset := decoder.InputOffset()
+		tok, err := decoder.Token()
+		if err != nil {
+			break
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			node := &SQLNode{Tag: t.Name.Local, Attrs: make(map[string]string), Line: lineOf(int(offset))}
+			for _, attr := range t.Attr {
+				node.Attrs[attr.Name.Local] = attr.Value
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, node)
+				stack = append(stack, node)
+				continue
+			}
+			trees := statements
+			switch node.Tag {
+			case "sql":
+				trees = fragments
+			case "resultMap":
+				trees = resultMaps
+			case "select", "insert", "update", "delete":
+			default:
+				continue
+			}
+			if _, seen := trees[node.Attrs["id"]]; !seen {
+				trees[node.Attrs["id"]] = node
+			}
+			stack = append(stack, node)
+
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+
+		case xml.CharData:
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, &SQLNode{Text: string(t), Line: lineOf(int(offset))})
+			}
+		}
+	}
+	return statements, fragments, resultMaps
+}
+
+// lineCounter returns a function that maps byte offsets to 1-based line numbers
+// Offsets are expected in increasing order (as a Decoder produces them), so
+// each call only counts the newlines since the previous one
+func lineCounter(content string) func(offset int) int {
+	pos, line := 0, 1
+	return func(offset int) int {
+		offset = min(offset, len(content))
+		if offset < pos {
+			return lineAt(content, offset)
+		}
+		line += strings.Count(content[pos:offset], "\n")
+		pos = offset
+		return line
+	}
+}
@@ -0,0 +1,196 @@
+package xmlparser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxIncludeDepth bounds nested <include> expansion
+const maxIncludeDepth = 16
+
+// placeholderRegex matches ${name} placeholders substituted from <include> properties
+var placeholderRegex = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// Fragment is a <sql id> element that statements pull in with <include refid>
+type Fragment struct {
+	Namespace string   // Namespace of the mapper that declares it
+	ID        string   // Fragment ID (without namespace)
+	File      string   // Source file path
+	Root      *SQLNode // The <sql> element
+}
+
+// IncludeDiagnostic reports an <include> whose fragment could not be resolved
+type IncludeDiagnostic struct {
+	File        string // Mapper file of the statement
+	Line        int    // Line of the <include> element
+	StatementID string // Statement that contains the include
+	RefID       string // refid as written (after property substitution)
+	Reason      string // Why it was not expanded
+}
+
+func (d IncludeDiagnostic) String() string {
+	return fmt.Sprintf("%s:%d: <include refid=\"%s\"> in '%s': %s", d.File, d.Line, d.RefID, d.StatementID, d.Reason)
+}
+
+// FragmentRegistry collects <sql> fragments from every mapper file so that
+// <include refid> can be expanded across files and namespaces
+// Register every mapper first, then Expand each of them
+type FragmentRegistry struct {
+	fragments map[string]*Fragment // "namespace.id" (or "id" without namespace) -> fragment
+}
+
+// NewFragmentRegistry creates an empty fragment registry
+func NewFragmentRegistry() *FragmentRegistry {
+	return &FragmentRegistry{fragments: make(map[string]*Fragment)}
+}
+
+// Register adds the <sql> fragments of a mapper to the registry
+func (r *FragmentRegistry) Register(mapper *MapperXML) {
+	for id, root := range mapper.Fragments {
+		r.fragments[qualifyRefID(id, mapper.Namespace)] = &Fragment{
+			Namespace: mapper.Namespace,
+			ID:        id,
+			File:      mapper.File,
+			Root:      root,
+		}
+	}
+}
+
+// Expand replaces every <include> in the statements of a mapper with the
+// referenced fragment, substituting ${name} placeholders from its <property>
+// elements, and re-renders the statements. Includes that cannot be resolved
+// stay in the tree (rendered as "[unresolved include id]") and are reported
+func (r *FragmentRegistry) Expand(mapper *MapperXML) []IncludeDiagnostic {
+	var diagnostics []IncludeDiagnostic
+	for i := range mapper.SQLs {
+		sql := &mapper.SQLs[i]
+		if sql.Root == nil {
+			continue
+		}
+		report := func(include *SQLNode, refID, reason string) {
+			diagnostics = append(diagnostics, IncludeDiagnostic{
+				File:        mapper.File,
+				Line:        include.Line,
+				StatementID: sql.ID,
+				RefID:       refID,
+				Reason:      reason,
+			})
+		}
+		r.expandIncludes(sql.Root, []string{mapper.Namespace}, nil, nil, report)
+		sql.Content = sql.Root.Render()
+	}
+	return diagnostics
+}
+
+// expandIncludes splices resolved fragments into node's children, recursively
+// Unqualified refids are looked up in namespaces, innermost first (a fragment's
+// own namespace, then the statement's, which is what MyBatis uses); props holds
+// the <property> values of the enclosing includes; active lists the fragments
+// being expanded, to stop cycles
+func (r *FragmentRegistry) expandIncludes(node *SQLNode, namespaces []string, props map[string]string, active []string,
+	report func(include *SQLNode, refID, reason string)) {
+	var children []*SQLNode
+	for _, child := range node.Children {
+		if child.Tag != "include" {
+			r.expandIncludes(child, namespaces, props, active, report)
+			children = append(children, child)
+			continue
+		}
+
+		refID := substitute(child.Attrs["refid"], props)
+		child.Attrs["refid"] = refID
+		qualifiedID, fragment := r.lookup(refID, namespaces)
+		switch {
+		case refID == "":
+			report(child, refID, "missing refid")
+		case fragment == nil:
+			report(child, refID, "fragment not found")
+		case containsString(active, qualifiedID):
+			report(child, refID, "circular include")
+			fragment = nil
+		case len(active) >= maxIncludeDepth:
+			report(child, refID, "includes nested too deeply")
+			fragment = nil
+		}
+		if fragment == nil {
+			children = append(children, child)
+			continue
+		}
+
+		// Properties of this include, evaluated in the enclosing context
+		scoped := make(map[string]string, len(props))
+		for name, value := range props {
+			scoped[name] = value
+		}
+		for _, prop := range child.Children {
+			if prop.Tag == "property" && prop.Attrs["name"] != "" {
+				scoped[prop.Attrs["name"]] = substitute(prop.Attrs["value"], props)
+			}
+		}
+
+		copied := fragment.Root.clone(scoped)
+		scope := append([]string{fragment.Namespace}, namespaces...)
+		r.expandIncludes(copied, scope, scoped, append(active, qualifiedID), report)
+		children = append(children, copied.Children...)
+	}
+	node.Children = children
+}
+
+// lookup resolves a refid against each namespace in turn
+func (r *FragmentRegistry) lookup(refID string, namespaces []string) (string, *Fragment) {
+	for _, namespace := range namespaces {
+		qualifiedID := qualifyRefID(refID, namespace)
+		if fragment := r.fragments[qualifiedID]; fragment != nil {
+			return qualifiedID, fragment
+		}
+	}
+	return qualifyRefID(refID, namespaces[0]), nil
+}
+
+// clone deep-copies a tree, substituting ${name} placeholders in text and
+// attribute values when props is non-empty
+func (n *SQLNode) clone(props map[string]string) *SQLNode {
+	copied := &SQLNode{Tag: n.Tag, Text: substitute(n.Text, props), Line: n.Line}
+	if n.Attrs != nil {
+		copied.Attrs = make(map[string]string, len(n.Attrs))
+		for name, value := range n.Attrs {
+			copied.Attrs[name] = substitute(value, props)
+		}
+	}
+	for _, child := range n.Children {
+		copied.Children = append(copied.Children, child.clone(props))
+	}
+	return copied
+}
+
+// substitute replaces ${name} placeholders that name an include property
+// Other placeholders are runtime parameters and are left as written
+func substitute(text string, props map[string]string) string {
+	if len(props) == 0 || !strings.Contains(text, "${") {
+		return text
+	}
+	return placeholderRegex.ReplaceAllStringFunc(text, func(placeholder string) string {
+		if value, ok := props[strings.TrimSpace(placeholder[2:len(placeholder)-1])]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// qualifyRefID applies the current namespace to an unqualified refid, as MyBatis does
+func qualifyRefID(refID, namespace string) string {
+	if namespace == "" || strings.Contains(refID, ".") {
+		return refID
+	}
+	return namespace + "." + refID
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
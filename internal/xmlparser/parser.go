@@ -7,17 +7,25 @@ import (
 	"strings"
 )
 
-// SQL represents a single SQL statement in MyBatis mapper
+// SQL represents a single SQL statement in a MyBatis mapper or iBatis sqlMap
 type SQL struct {
-	ID      string // SQL statement ID (e.g., "selectUserCount")
-	Type    string // Type: select, insert, update, delete
-	Content string // SQL query content
+	ID            string   // SQL statement ID (e.g., "selectUserCount")
+	Type          string   // Type: select, insert, update, delete (iBatis: also procedure, statement)
+	Content       string   // SQL query content
+	ParameterType string   // parameterType (iBatis: parameterClass, alias resolved)
+	ResultType    string   // resultType (iBatis: resultClass, alias resolved)
+	ResultMap     string   // resultMap ID, as written (may name another namespace)
+	Root          *SQLNode // Dynamic SQL tree (iBatis sqlMaps: text and <include> nodes only)
+	Line          int      // Line number of the statement's start tag
 }
 
-// MapperXML represents a parsed MyBatis XML mapper file
+// MapperXML represents a parsed MyBatis XML mapper or iBatis sqlMap file
 type MapperXML struct {
-	Namespace string // Mapper namespace (matches Java interface)
-	SQLs      []SQL  // List of SQL statements
+	Namespace  string                // Mapper namespace (matches Java interface; iBatis: sqlMap namespace, may be empty)
+	File       string                // Source file path (set by ParseXMLFileWithPath)
+	SQLs       []SQL                 // List of SQL statements
+	Fragments  map[string]*SQLNode   // <sql id> fragments, by ID
+	ResultMaps map[string]*ResultMap // <resultMap> elements, by ID
 }
 
 // RawMapper is used for XML unmarshaling
@@ -32,12 +40,20 @@ type RawMapper struct {
 
 // RawSQL represents a SQL element in XML
 type RawSQL struct {
-	ID      string `xml:"id,attr"`
-	Content string `xml:",chardata"`
+	ID            string `xml:"id,attr"`
+	ParameterType string `xml:"parameterType,attr"`
+	ResultType    string `xml:"resultType,attr"`
+	ResultMap     string `xml:"resultMap,attr"`
+	Content       string `xml:",chardata"`
 }
 
 // ParseXMLFile parses a MyBatis XML mapper file
 func ParseXMLFile(content string) (*MapperXML, error) {
+	return ParseXMLFileWithPath("", content)
+}
+
+// ParseXMLFileWithPath parses a MyBatis XML mapper file and records path as its source file
+func ParseXMLFileWithPath(path, content string) (*MapperXML, error) {
 	// Parse XML
 	var rawMapper RawMapper
 	err := xml.Unmarshal([]byte(content), &rawMapper)
@@ -47,48 +63,62 @@ func ParseXMLFile(content string) (*MapperXML, error) {
 
 	mapper := &MapperXML{
 		Namespace: rawMapper.Namespace,
+		File:      path,
 		SQLs:      []SQL{},
 	}
-
-	// Extract SELECT statements
-	for _, sel := range rawMapper.Selects {
-		mapper.SQLs = append(mapper.SQLs, SQL{
-			ID:      sel.ID,
-			Type:    "select",
-			Content: cleanSQLContent(sel.Content),
-		})
+	trees, fragments, resultMaps := statementTrees(content)
+	mapper.Fragments = fragments
+	mapper.ResultMaps = make(map[string]*ResultMap, len(resultMaps))
+	for id, root := range resultMaps {
+		mapper.ResultMaps[id] = resultMapOf(mapper.Namespace, root)
 	}
 
-	// Extract INSERT statements
-	for _, ins := range rawMapper.Inserts {
-		mapper.SQLs = append(mapper.SQLs, SQL{
-			ID:      ins.ID,
-			Type:    "insert",
-			Content: cleanSQLContent(ins.Content),
-		})
+	statements := []struct {
+		sqlType string
+		raws    []RawSQL
+	}{
+		{"select", rawMapper.Selects},
+		{"insert", rawMapper.Inserts},
+		{"update", rawMapper.Updates},
+		{"delete", rawMapper.Deletes},
 	}
-
-	// Extract UPDATE statements
-	for _, upd := range rawMapper.Updates {
-		mapper.SQLs = append(mapper.SQLs, SQL{
-			ID:      upd.ID,
-			Type:    "update",
-			Content: cleanSQLContent(upd.Content),
-		})
+	for _, group := range statements {
+		for _, raw := range group.raws {
+			sql := SQL{
+				ID:            raw.ID,
+				Type:          group.sqlType,
+				Content:       cleanSQLContent(raw.Content),
+				ParameterType: strings.TrimSpace(raw.ParameterType),
+				ResultType:    strings.TrimSpace(raw.ResultType),
+				ResultMap:     strings.TrimSpace(raw.ResultMap),
+			}
+			if root := trees[raw.ID]; root != nil {
+				// The tree keeps the text of <if>, <where>, <foreach>, ... that chardata drops
+				sql.Root = root
+				sql.Content = root.Render()
+				sql.Line = root.Line
+			}
+			mapper.SQLs = append(mapper.SQLs, sql)
+		}
 	}
 
-	// Extract DELETE statements
-	for _, del := range rawMapper.Deletes {
-		mapper.SQLs = append(mapper.SQLs, SQL{
-			ID:      del.ID,
-			Type:    "delete",
-			Content: cleanSQLContent(del.Content),
-		})
-	}
+	// Fragments of the same file are always available; includes from other
+	// files stay in the tree until a FragmentRegistry expands them
+	local := NewFragmentRegistry()
+	local.Register(mapper)
+	local.Expand(mapper)
 
 	return mapper, nil
 }
 
+// lineAt returns the 1-based line number of a byte offset
+func lineAt(content string, offset int) int {
+	if offset > len(content) {
+		offset = len(content)
+	}
+	return strings.Count(content[:offset], "\n") + 1
+}
+
 // cleanSQLContent cleans up SQL content by removing excessive whitespace
 func cleanSQLContent(sql string) string {
 	// Remove leading/trailing whitespace
@@ -168,3 +198,35 @@ func (m *MapperXML) MatchesJavaInterface(javaClassName string) bool {
 	namespaceName := m.GetNamespaceName()
 	return strings.EqualFold(namespaceName, javaClassName)
 }
+
+// ParseScript reads the SQL of a statement declared in Java (MyBatis @Select,
+// @Insert, ...) the way a mapper file element would be read. A "<script>" body
+// is dynamic SQL in mapper XML syntax; any other text is taken literally
+func ParseScript(kind, id, script string) (SQL, error) {
+	body := strings.TrimSpace(script)
+	if strings.HasPrefix(body, "<script>") && strings.HasSuffix(body, "</script>") {
+		body = strings.TrimSuffix(strings.TrimPrefix(body, "<script>"), "</script>")
+	} else {
+		var escaped strings.Builder
+		if err := xml.EscapeText(&escaped, []byte(body)); err != nil {
+			return SQL{}, err
+		}
+		body = escaped.String()
+	}
+
+	var doc strings.Builder
+	doc.WriteString(`<mapper namespace=""><` + kind + ` id="`)
+	xml.EscapeText(&doc, []byte(id))
+	doc.WriteString(`">` + body + `</` + kind + `></mapper>`)
+
+	mapper, err := ParseXMLFile(doc.String())
+	if err != nil {
+		return SQL{}, err
+	}
+	if len(mapper.SQLs) != 1 {
+		return SQL{}, fmt.Errorf("unsupported statement kind %q", kind)
+	}
+	sql := mapper.SQLs[0]
+	sql.Line = 0 // Relative to the generated document; the caller knows the source line
+	return sql, nil
+}
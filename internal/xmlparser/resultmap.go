@@ -0,0 +1,74 @@
+package xmlparser
+
+// ResultMap is a <resultMap>: how the columns of a result set map onto a type
+type ResultMap struct {
+	Namespace string          // Namespace of the declaring mapper
+	ID        string          // resultMap ID (without namespace)
+	Type      string          // type attribute (iBatis: class), alias resolved where known
+	Extends   string          // ID of the inherited resultMap, as written
+	Mappings  []ResultMapping // Mappings in document order
+	Line      int             // Line of the <resultMap> element
+}
+
+// ResultMapping maps a column, or a nested result, onto a property
+type ResultMapping struct {
+	Kind         string          // id, result, idArg, arg, association, collection or case (<discriminator>)
+	Property     string          // Property name (constructor arguments: the name attribute, if any)
+	Column       string          // Column the value is read from
+	JavaType     string          // javaType; for collections the element type (ofType)
+	ResultMap    string          // Nested resultMap ID, as written
+	Select       string          // Nested select statement ID, as written
+	ColumnPrefix string          // Prefix applied to the columns of the nested result
+	Mappings     []ResultMapping // Inline mappings of an association, collection or discriminator case
+}
+
+// resultMapOf converts a parsed <resultMap> element
+func resultMapOf(namespace string, node *SQLNode) *ResultMap {
+	return &ResultMap{
+		Namespace: namespace,
+		ID:        node.Attrs["id"],
+		Type:      firstAttr(node, "type", "class"),
+		Extends:   node.Attrs["extends"],
+		Mappings:  resultMappings(node),
+		Line:      node.Line,
+	}
+}
+
+// resultMappings converts the mapping elements below node
+// <constructor> arguments and <discriminator> cases are flattened into the
+// list: a case contributes the properties it may add
+func resultMappings(node *SQLNode) []ResultMapping {
+	var mappings []ResultMapping
+	for _, child := range node.Children {
+		switch child.Tag {
+		case "id", "result", "idArg", "arg", "association", "collection", "case":
+			mapping := ResultMapping{
+				Kind:         child.Tag,
+				Property:     firstAttr(child, "property", "name"),
+				Column:       child.Attrs["column"],
+				JavaType:     firstAttr(child, "ofType", "javaType", "resultType"),
+				ResultMap:    child.Attrs["resultMap"],
+				Select:       child.Attrs["select"],
+				ColumnPrefix: child.Attrs["columnPrefix"],
+				Mappings:     resultMappings(child),
+			}
+			if child.Tag == "case" {
+				mapping.Property, mapping.Column = "", ""
+			}
+			mappings = append(mappings, mapping)
+		case "constructor", "discriminator":
+			mappings = append(mappings, resultMappings(child)...)
+		}
+	}
+	return mappings
+}
+
+// firstAttr returns the first non-empty attribute among names
+func firstAttr(node *SQLNode, names ...string) string {
+	for _, name := range names {
+		if value := node.Attrs[name]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
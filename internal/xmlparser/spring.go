@@ -0,0 +1,375 @@
+package xmlparser
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SpringContext is a parsed Spring XML application context
+// (applicationContext.xml, dispatcher-servlet.xml, ...)
+type SpringContext struct {
+	File            string            // Source file path
+	Beans           []SpringBean      // Top-level <bean> definitions
+	Aliases         map[string]string // <alias alias="..." name="..."/>: alias -> bean name
+	URLMappings     []URLMapping      // Handler mappings: URL -> controller bean
+	ViewControllers []ViewController  // <mvc:view-controller> and <mvc:redirect-view-controller>
+}
+
+// SpringBean is a <bean> definition
+type SpringBean struct {
+	ID              string         // id attribute
+	Names           []string       // name attribute, split on ',', ';' and spaces
+	Class           string         // Fully qualified class name (empty for child definitions)
+	Parent          string         // parent bean definition
+	Abstract        bool           // abstract="true" templates are never instantiated
+	Properties      []BeanProperty // <property> elements and p: attributes
+	ConstructorArgs []BeanProperty // <constructor-arg> elements
+	Line            int            // Line of the <bean> tag
+}
+
+// BeanProperty is a <property> or <constructor-arg> of a bean
+type BeanProperty struct {
+	Name  string // Property name (constructor-arg name, when given)
+	Index int    // constructor-arg index, -1 when not given
+	Ref   string // Referenced bean name (ref attribute or <ref bean>)
+	Value string // Literal value
+	Class string // Class of an inner <bean>
+	Line  int    // Line of the element
+}
+
+// URLMapping maps a URL pattern to a controller bean
+type URLMapping struct {
+	Path string // e.g., "/user/list.do"
+	Bean string // Controller bean name
+	Line int    // Line of the mapping entry
+}
+
+// ViewController maps a URL straight to a view without a controller class
+type ViewController struct {
+	Path     string // e.g., "/"
+	ViewName string // e.g., "home" or "redirect:/main.do"
+	Line     int    // Line of the element
+}
+
+const (
+	pNamespace = "http://www.springframework.org/schema/p"
+)
+
+// RootElement returns the local name of the document element ("mapper", "beans", ...)
+// Returns an empty string when the content is not XML
+func RootElement(content string) string {
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	decoder.Strict = false
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start.Name.Local
+		}
+	}
+}
+
+// ParseSpringXML parses a Spring XML application context
+func ParseSpringXML(content string) (*SpringContext, error) {
+	return ParseSpringXMLWithPath("", content)
+}
+
+// ParseSpringXMLWithPath parses a Spring XML application context and records path as its source file
+func ParseSpringXMLWithPath(path, content string) (*SpringContext, error) {
+	if root := RootElement(content); root != "beans" {
+		return nil, fmt.Errorf("failed to parse Spring XML: root element is <%s>, expected <beans>", root)
+	}
+
+	p := &springParser{
+		content: content,
+		ctx:     &SpringContext{File: path, Aliases: make(map[string]string)},
+	}
+	if err := p.parse(); err != nil {
+		return nil, fmt.Errorf("failed to parse Spring XML: %w", err)
+	}
+	return p.ctx, nil
+}
+
+// GetBean returns the bean with the given id or name (aliases included), or nil
+func (c *SpringContext) GetBean(name string) *SpringBean {
+	if target, ok := c.Aliases[name]; ok {
+		name = target
+	}
+	for i := range c.Beans {
+		if c.Beans[i].ID == name {
+			return &c.Beans[i]
+		}
+		for _, n := range c.Beans[i].Names {
+			if n == name {
+				return &c.Beans[i]
+			}
+		}
+	}
+	return nil
+}
+
+// springFrame is an open element while walking the document
+type springFrame struct {
+	name  string
+	bean  *SpringBean   // <bean>
+	prop  *BeanProperty // <property> / <constructor-arg>
+	key   string        // <prop key> / <entry key>
+	ref   string        // <entry value-ref> or nested <ref>
+	text  strings.Builder
+	line  int
+	inner bool // <bean> nested inside a property value
+}
+
+type springParser struct {
+	content string
+	ctx     *SpringContext
+	stack   []*springFrame
+}
+
+func (p *springParser) parse() error {
+	decoder := xml.NewDecoder(strings.NewReader(p.content))
+	decoder.Strict = false
+
+	for {
+		offset := decoder.InputOffset()
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			p.start(t, lineAt(p.content, int(offset)))
+		case xml.EndElement:
+			if len(p.stack) > 0 {
+				frame := p.stack[len(p.stack)-1]
+				p.stack = p.stack[:len(p.stack)-1]
+				p.end(frame)
+			}
+		case xml.CharData:
+			if len(p.stack) > 0 {
+				p.stack[len(p.stack)-1].text.Write(t)
+			}
+		}
+	}
+}
+
+func (p *springParser) start(el xml.StartElement, line int) {
+	frame := &springFrame{name: el.Name.Local, line: line}
+	attr := func(name string) string {
+		for _, a := range el.Attr {
+			if a.Name.Local == name && a.Name.Space == "" {
+				return strings.TrimSpace(a.Value)
+			}
+		}
+		return ""
+	}
+
+	switch frame.name {
+	case "bean":
+		bean := &SpringBean{
+			ID:       attr("id"),
+			Names:    splitBeanNames(attr("name")),
+			Class:    strings.ReplaceAll(attr("class"), "$", "."),
+			Parent:   attr("parent"),
+			Abstract: attr("abstract") == "true",
+			Line:     line,
+		}
+		for _, a := range el.Attr {
+			if a.Name.Space != pNamespace && a.Name.Space != "p" {
+				continue
+			}
+			prop := BeanProperty{Name: a.Name.Local, Index: -1, Value: a.Value, Line: line}
+			if strings.HasSuffix(prop.Name, "-ref") {
+				prop.Name, prop.Ref, prop.Value = strings.TrimSuffix(prop.Name, "-ref"), a.Value, ""
+			}
+			bean.Properties = append(bean.Properties, prop)
+		}
+		frame.bean = bean
+		if owner := p.nearest("property", "constructor-arg"); owner != nil {
+			frame.inner = true
+			if owner.prop.Class == "" {
+				owner.prop.Class = bean.Class
+			}
+		}
+
+	case "property", "constructor-arg":
+		prop := &BeanProperty{
+			Name:  attr("name"),
+			Index: -1,
+			Ref:   attr("ref"),
+			Value: attr("value"),
+			Line:  line,
+		}
+		if index := attr("index"); index != "" {
+			fmt.Sscanf(index, "%d", &prop.Index)
+		}
+		frame.prop = prop
+
+	case "ref", "idref":
+		target := attr("bean")
+		if target == "" {
+			target = attr("local")
+		}
+		if target == "" {
+			target = attr("parent")
+		}
+		if entry := p.nearest("entry"); entry != nil && entry.ref == "" && p.insideMapping() {
+			entry.ref = target
+		} else if owner := p.valueOwner(); owner != nil && owner.prop.Ref == "" && frame.name == "ref" {
+			owner.prop.Ref = target
+		}
+
+	case "prop":
+		frame.key = attr("key")
+
+	case "entry":
+		frame.key = attr("key")
+		frame.ref = attr("value-ref")
+		if frame.ref == "" {
+			frame.ref = attr("value")
+		}
+
+	case "alias":
+		if name, alias := attr("name"), attr("alias"); name != "" && alias != "" {
+			p.ctx.Aliases[alias] = name
+		}
+
+	case "view-controller":
+		p.ctx.ViewControllers = append(p.ctx.ViewControllers, ViewController{
+			Path:     attr("path"),
+			ViewName: attr("view-name"),
+			Line:     line,
+		})
+
+	case "redirect-view-controller":
+		p.ctx.ViewControllers = append(p.ctx.ViewControllers, ViewController{
+			Path:     attr("path"),
+			ViewName: "redirect:" + attr("redirect-url"),
+			Line:     line,
+		})
+	}
+
+	p.stack = append(p.stack, frame)
+}
+
+func (p *springParser) end(frame *springFrame) {
+	text := strings.TrimSpace(frame.text.String())
+
+	switch frame.name {
+	case "bean":
+		if frame.inner || p.nearest("bean") != nil {
+			return
+		}
+		bean := *frame.bean
+		p.ctx.Beans = append(p.ctx.Beans, bean)
+		// BeanNameUrlHandlerMapping: beans named after the URL they handle
+		for _, name := range append([]string{bean.ID}, bean.Names...) {
+			if strings.HasPrefix(name, "/") {
+				p.ctx.URLMappings = append(p.ctx.URLMappings, URLMapping{Path: name, Bean: name, Line: bean.Line})
+			}
+		}
+
+	case "property", "constructor-arg":
+		owner := p.nearest("bean")
+		if owner == nil {
+			return
+		}
+		if frame.name == "property" {
+			owner.bean.Properties = append(owner.bean.Properties, *frame.prop)
+		} else {
+			owner.bean.ConstructorArgs = append(owner.bean.ConstructorArgs, *frame.prop)
+		}
+
+	case "value":
+		owner := p.valueOwner()
+		if owner == nil {
+			return
+		}
+		if p.insideMapping() {
+			p.ctx.URLMappings = append(p.ctx.URLMappings, parseMappingProperties(frame.text.String(), frame.line)...)
+			return
+		}
+		if owner.prop.Value == "" {
+			owner.prop.Value = text
+		}
+
+	case "prop":
+		if p.insideMapping() && frame.key != "" && text != "" {
+			p.ctx.URLMappings = append(p.ctx.URLMappings, URLMapping{Path: frame.key, Bean: text, Line: frame.line})
+		}
+
+	case "entry":
+		if p.insideMapping() && frame.key != "" && frame.ref != "" {
+			p.ctx.URLMappings = append(p.ctx.URLMappings, URLMapping{Path: frame.key, Bean: frame.ref, Line: frame.line})
+		}
+	}
+}
+
+// nearest returns the innermost open element with one of the given names
+func (p *springParser) nearest(names ...string) *springFrame {
+	for i := len(p.stack) - 1; i >= 0; i-- {
+		for _, name := range names {
+			if p.stack[i].name == name {
+				return p.stack[i]
+			}
+		}
+	}
+	return nil
+}
+
+// valueOwner returns the property or constructor-arg that directly owns the
+// current value, or nil when the value belongs to a list, set, map or props
+func (p *springParser) valueOwner() *springFrame {
+	frame := p.nearest("property", "constructor-arg", "list", "set", "map", "props")
+	if frame == nil || frame.prop == nil {
+		return nil
+	}
+	return frame
+}
+
+// insideMapping reports whether the current element is inside the "mappings" or
+// "urlMap" property of a SimpleUrlHandlerMapping bean
+func (p *springParser) insideMapping() bool {
+	prop := p.nearest("property")
+	bean := p.nearest("bean")
+	if prop == nil || bean == nil {
+		return false
+	}
+	return strings.HasSuffix(bean.bean.Class, "SimpleUrlHandlerMapping") &&
+		(prop.prop.Name == "mappings" || prop.prop.Name == "urlMap")
+}
+
+// parseMappingProperties parses "/url=beanName" lines of a <value> mapping block
+func parseMappingProperties(text string, line int) []URLMapping {
+	var mappings []URLMapping
+	for i, raw := range strings.Split(text, "\n") {
+		entry := strings.TrimSpace(raw)
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		sep := strings.IndexAny(entry, "=:")
+		if sep <= 0 {
+			continue
+		}
+		mappings = append(mappings, URLMapping{
+			Path: strings.TrimSpace(entry[:sep]),
+			Bean: strings.TrimSpace(entry[sep+1:]),
+			Line: line + i,
+		})
+	}
+	return mappings
+}
+
+func splitBeanNames(names string) []string {
+	return strings.FieldsFunc(names, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' '
+	})
+}
@@ -0,0 +1,224 @@
+package xmlparser
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// sqlMapStatements are the iBatis 2 statement elements
+var sqlMapStatements = map[string]bool{
+	"select": true, "insert": true, "update": true, "delete": true,
+	"statement": true, "procedure": true,
+}
+
+// sqlMapFrame is an open dynamic element (<dynamic>, <isNotEmpty>, <iterate>, ...)
+type sqlMapFrame struct {
+	close       string // close attribute, written when the element ends
+	dropPrepend bool   // The next child's prepend is dropped
+}
+
+// ParseSqlMap parses an iBatis 2 <sqlMap> file
+func ParseSqlMap(content string) (*MapperXML, error) {
+	return ParseSqlMapWithPath("", content)
+}
+
+// ParseSqlMapWithPath parses an iBatis 2 <sqlMap> file and records path as its source file
+// Statements become the same SQL entries as MyBatis mapper statements; the text of
+// <dynamic> and <isXxx> tags is kept (with their prepend) so the SQL stays readable
+// <sql> fragments and <include refid> become tree nodes, expanded like those of
+// MyBatis mappers (see FragmentRegistry)
+func ParseSqlMapWithPath(path, content string) (*MapperXML, error) {
+	if root := RootElement(content); root != "sqlMap" {
+		return nil, fmt.Errorf("failed to parse sqlMap: root element is <%s>, expected <sqlMap>", root)
+	}
+
+	mapper := &MapperXML{File: path, SQLs: []SQL{}, Fragments: make(map[string]*SQLNode), ResultMaps: make(map[string]*ResultMap)}
+	aliases := make(map[string]string)
+	var resultMap *ResultMap // Open <resultMap>
+
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	decoder.Strict = false
+
+	var current *SQL // Open statement or <sql> fragment
+	var text strings.Builder
+	var parts []*SQLNode      // Text and <include> nodes of the current statement, in order
+	var closers []sqlMapFrame // Open elements inside the current statement
+	skipDepth := 0            // Depth of a <selectKey> whose text is not part of the statement
+	flush := func() {
+		if text.Len() > 0 {
+			parts = append(parts, &SQLNode{Text: text.String(), Line: current.Line})
+			text.Reset()
+		}
+	}
+
+	for {
+		offset := decoder.InputOffset()
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse sqlMap: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			attr := func(name string) string {
+				for _, a := range t.Attr {
+					if a.Name.Local == name {
+						return strings.TrimSpace(a.Value)
+					}
+				}
+				return ""
+			}
+
+			if current != nil {
+				if t.Name.Local == "include" && skipDepth == 0 {
+					flush()
+					parts = append(parts, &SQLNode{
+						Tag:   "include",
+						Attrs: map[string]string{"refid": attr("refid")},
+						Line:  lineAt(content, int(offset)),
+					})
+					closers = append(closers, sqlMapFrame{})
+					continue
+				}
+				prepend := attr("prepend")
+				// <dynamic> (and removeFirstPrepend="true") drops the prepend of its first child
+				if n := len(closers); n > 0 && closers[n-1].dropPrepend {
+					closers[n-1].dropPrepend = false
+					prepend = ""
+				}
+				closers = append(closers, sqlMapFrame{
+					close:       attr("close"),
+					dropPrepend: t.Name.Local == "dynamic" || attr("removeFirstPrepend") == "true",
+				})
+				if t.Name.Local == "selectKey" && skipDepth == 0 {
+					skipDepth = len(closers)
+				}
+				if skipDepth == 0 {
+					text.WriteString(" " + prepend + " " + attr("open") + " ")
+				}
+				continue
+			}
+
+			switch {
+			case t.Name.Local == "sqlMap":
+				mapper.Namespace = attr("namespace")
+			case t.Name.Local == "typeAlias":
+				aliases[attr("alias")] = attr("type")
+			case t.Name.Local == "resultMap":
+				resultMap = &ResultMap{
+					Namespace: mapper.Namespace,
+					ID:        attr("id"),
+					Type:      attr("class"),
+					Extends:   attr("extends"),
+					Line:      lineAt(content, int(offset)),
+				}
+				mapper.ResultMaps[resultMap.ID] = resultMap
+			case t.Name.Local == "result" && resultMap != nil:
+				resultMap.Mappings = append(resultMap.Mappings, ResultMapping{
+					Kind:      "result",
+					Property:  attr("property"),
+					Column:    attr("column"),
+					JavaType:  attr("javaType"),
+					ResultMap: attr("resultMap"),
+					Select:    attr("select"),
+				})
+			case sqlMapStatements[t.Name.Local] || t.Name.Local == "sql":
+				current = &SQL{
+					ID:            attr("id"),
+					Type:          t.Name.Local,
+					ParameterType: attr("parameterClass"),
+					ResultType:    attr("resultClass"),
+					ResultMap:     attr("resultMap"),
+					Line:          lineAt(content, int(offset)),
+				}
+				text.Reset()
+				parts = nil
+				closers = closers[:0]
+			}
+
+		case xml.EndElement:
+			if current == nil {
+				if t.Name.Local == "resultMap" {
+					resultMap = nil
+				}
+				continue
+			}
+			if len(closers) == 0 {
+				flush()
+				current.Root = &SQLNode{Tag: current.Type, Attrs: map[string]string{"id": current.ID}, Children: parts, Line: current.Line}
+				current.Content = current.Root.Render()
+				switch current.Type {
+				case "sql":
+					if _, seen := mapper.Fragments[current.ID]; !seen {
+						mapper.Fragments[current.ID] = current.Root
+					}
+				case "statement":
+					current.Type = statementType(current.Content)
+					fallthrough
+				default:
+					mapper.SQLs = append(mapper.SQLs, *current)
+				}
+				current = nil
+				continue
+			}
+			closer := closers[len(closers)-1]
+			if len(closers) == skipDepth {
+				skipDepth = 0
+			} else if skipDepth == 0 {
+				text.WriteString(" " + closer.close + " ")
+			}
+			closers = closers[:len(closers)-1]
+
+		case xml.CharData:
+			if current != nil && skipDepth == 0 {
+				text.Write(t)
+			}
+		}
+	}
+
+	// <typeAlias> may be declared after the statements that use it
+	for i := range mapper.SQLs {
+		mapper.SQLs[i].ParameterType = resolveAlias(aliases, mapper.SQLs[i].ParameterType)
+		mapper.SQLs[i].ResultType = resolveAlias(aliases, mapper.SQLs[i].ResultType)
+	}
+	for _, rm := range mapper.ResultMaps {
+		rm.Type = resolveAlias(aliases, rm.Type)
+		for i := range rm.Mappings {
+			rm.Mappings[i].JavaType = resolveAlias(aliases, rm.Mappings[i].JavaType)
+		}
+	}
+
+	local := NewFragmentRegistry()
+	local.Register(mapper)
+	local.Expand(mapper)
+
+	return mapper, nil
+}
+
+// resolveAlias maps a <typeAlias> alias to its class name
+func resolveAlias(aliases map[string]string, typeName string) string {
+	if full, ok := aliases[typeName]; ok && full != "" {
+		return full
+	}
+	return typeName
+}
+
+// statementType infers select/insert/update/delete from the leading SQL keyword
+// of a generic <statement>; anything else stays "statement"
+func statementType(sql string) string {
+	fields := strings.Fields(strings.TrimLeft(sql, "( "))
+	if len(fields) > 0 {
+		switch keyword := strings.ToLower(fields[0]); keyword {
+		case "select", "insert", "update", "delete":
+			return keyword
+		case "with":
+			return "select"
+		}
+	}
+	return "statement"
+}